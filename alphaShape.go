@@ -0,0 +1,326 @@
+package iotmaker_geo_osm
+
+import (
+	"math"
+	"sort"
+)
+
+// delaunayTriangleStt has the vertices counterclockwise and, at each position, the triangle across the edge opposite
+// to the vertex, or -1
+type delaunayTriangleStt struct {
+	vertex   [3]int
+	neighbor [3]int
+	removed  bool
+}
+
+// delaunayStt is the Delaunay triangulation of planar points, made by Bowyer-Watson: each point removes the triangles
+// whose circumcircle contains it and joins itself to the edges of the hole left. The last three points are the
+// vertices of a triangle that contains all the others.
+type delaunayStt struct {
+	points    [][2]float64
+	triangles []delaunayTriangleStt
+	last      int
+
+	// marks of the triangles removed by the point being inserted
+	mark  []int
+	marks int
+}
+
+// delaunayOrient is twice the signed area of the triangle, positive when it is counterclockwise
+func delaunayOrient(a, b, c [2]float64) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+// delaunayInCircle is positive when the point d is inside the circumcircle of the counterclockwise triangle a, b, c
+func delaunayInCircle(a, b, c, d [2]float64) float64 {
+	var ax, ay = a[0] - d[0], a[1] - d[1]
+	var bx, by = b[0] - d[0], b[1] - d[1]
+	var cx, cy = c[0] - d[0], c[1] - d[1]
+
+	return (ax*ax+ay*ay)*(bx*cy-cx*by) - (bx*bx+by*by)*(ax*cy-cx*ay) + (cx*cx+cy*cy)*(ax*by-bx*ay)
+}
+
+// newDelaunay triangulates the points, which must be distinct
+func newDelaunay(points [][2]float64) *delaunayStt {
+	var minimum, maximum = [2]float64{math.Inf(1), math.Inf(1)}, [2]float64{math.Inf(-1), math.Inf(-1)}
+	for _, point := range points {
+		minimum = [2]float64{math.Min(minimum[0], point[0]), math.Min(minimum[1], point[1])}
+		maximum = [2]float64{math.Max(maximum[0], point[0]), math.Max(maximum[1], point[1])}
+	}
+
+	var center = [2]float64{(minimum[0] + maximum[0]) / 2, (minimum[1] + maximum[1]) / 2}
+	var size = math.Max(math.Max(maximum[0]-minimum[0], maximum[1]-minimum[1]), 1) * 20
+
+	var triangulation = &delaunayStt{points: make([][2]float64, len(points), len(points)+3)}
+	copy(triangulation.points, points)
+	triangulation.points = append(triangulation.points,
+		[2]float64{center[0] - size, center[1] - size},
+		[2]float64{center[0] + size, center[1] - size},
+		[2]float64{center[0], center[1] + size},
+	)
+
+	var n = len(points)
+	triangulation.triangles = []delaunayTriangleStt{{vertex: [3]int{n, n + 1, n + 2}, neighbor: [3]int{-1, -1, -1}}}
+	triangulation.mark = []int{0}
+
+	// the points in the order of their cells in a grid, row after row and back, so that each point is near the last
+	// triangle made
+	var order = make([]int, n)
+	var cell = math.Max(maximum[0]-minimum[0], maximum[1]-minimum[1])/math.Sqrt(float64(n)+1) + 1e-9
+	var key = make([][2]int, n)
+	for k, point := range points {
+		order[k] = k
+
+		var row = int((point[1] - minimum[1]) / cell)
+		var column = int((point[0] - minimum[0]) / cell)
+		if row%2 == 1 {
+			column = -column
+		}
+		key[k] = [2]int{row, column}
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		var a, b = key[order[i]], key[order[j]]
+		return a[0] < b[0] || a[0] == b[0] && a[1] < b[1]
+	})
+
+	for _, point := range order {
+		triangulation.insert(point)
+	}
+
+	return triangulation
+}
+
+// locate is a triangle that contains the point, found by walking from the last triangle made towards it
+func (el *delaunayStt) locate(point [2]float64) int {
+	var triangle = el.last
+
+	for steps := 0; steps != len(el.triangles); steps += 1 {
+		var next = -1
+		for i := 0; i != 3; i += 1 {
+			var a = el.points[el.triangles[triangle].vertex[(i+1)%3]]
+			var b = el.points[el.triangles[triangle].vertex[(i+2)%3]]
+			if delaunayOrient(a, b, point) < 0 && el.triangles[triangle].neighbor[i] != -1 {
+				next = el.triangles[triangle].neighbor[i]
+				break
+			}
+		}
+
+		if next == -1 {
+			return triangle
+		}
+
+		triangle = next
+	}
+
+	// the rounding made the walk go around in circles
+	for k := range el.triangles {
+		var vertex = el.triangles[k].vertex
+		if !el.triangles[k].removed &&
+			delaunayOrient(el.points[vertex[0]], el.points[vertex[1]], point) >= 0 &&
+			delaunayOrient(el.points[vertex[1]], el.points[vertex[2]], point) >= 0 &&
+			delaunayOrient(el.points[vertex[2]], el.points[vertex[0]], point) >= 0 {
+			return k
+		}
+	}
+
+	return triangle
+}
+
+// insert adds the point to the triangulation
+func (el *delaunayStt) insert(index int) {
+	var point = el.points[index]
+	var first = el.locate(point)
+
+	el.marks += 1
+	el.mark[first] = el.marks
+
+	var cavity = []int{first}
+	for k := 0; k != len(cavity); k += 1 {
+		for _, neighbor := range el.triangles[cavity[k]].neighbor {
+			if neighbor == -1 || el.mark[neighbor] == el.marks {
+				continue
+			}
+
+			var vertex = el.triangles[neighbor].vertex
+			if delaunayInCircle(el.points[vertex[0]], el.points[vertex[1]], el.points[vertex[2]], point) > 0 {
+				el.mark[neighbor] = el.marks
+				cavity = append(cavity, neighbor)
+			}
+		}
+	}
+
+	// each edge of the border of the cavity makes a triangle with the point, and the new triangles are joined to each
+	// other by the edges that start at the point or end at it
+	var byStart, byEnd = make(map[int]int), make(map[int]int)
+	var made = make([]int, 0)
+
+	for _, removed := range cavity {
+		for i := 0; i != 3; i += 1 {
+			var outside = el.triangles[removed].neighbor[i]
+			if outside != -1 && el.mark[outside] == el.marks {
+				continue
+			}
+
+			var a, b = el.triangles[removed].vertex[(i+1)%3], el.triangles[removed].vertex[(i+2)%3]
+			var triangle = len(el.triangles)
+			el.triangles = append(el.triangles, delaunayTriangleStt{vertex: [3]int{a, b, index}, neighbor: [3]int{-1, -1, outside}})
+			el.mark = append(el.mark, 0)
+
+			if outside != -1 {
+				for j := range el.triangles[outside].neighbor {
+					if el.triangles[outside].neighbor[j] == removed {
+						el.triangles[outside].neighbor[j] = triangle
+					}
+				}
+			}
+
+			byStart[a], byEnd[b] = triangle, triangle
+			made = append(made, triangle)
+		}
+
+		el.triangles[removed].removed = true
+	}
+
+	for _, triangle := range made {
+		var vertex = el.triangles[triangle].vertex
+		el.triangles[triangle].neighbor[0] = byStart[vertex[1]]
+		el.triangles[triangle].neighbor[1] = byEnd[vertex[0]]
+	}
+
+	el.last = made[0]
+}
+
+// alphaShape are the rings of the region covered by the triangles of the Delaunay triangulation of the points whose
+// circumcircle radius is not greater than alpha: the outer rings counterclockwise, each one with the holes inside it,
+// clockwise
+func alphaShape(points [][2]float64, alpha float64) (outer [][][2]float64, holes [][][][2]float64) {
+	if len(points) < 3 {
+		return nil, nil
+	}
+
+	var triangulation = newDelaunay(points)
+	var n = len(points)
+
+	var kept = make([]bool, len(triangulation.triangles))
+	for k, triangle := range triangulation.triangles {
+		if triangle.removed || triangle.vertex[0] >= n || triangle.vertex[1] >= n || triangle.vertex[2] >= n {
+			continue
+		}
+
+		var a, b, c = points[triangle.vertex[0]], points[triangle.vertex[1]], points[triangle.vertex[2]]
+		var area = delaunayOrient(a, b, c)
+		var sides = math.Hypot(b[0]-a[0], b[1]-a[1]) * math.Hypot(c[0]-b[0], c[1]-b[1]) * math.Hypot(a[0]-c[0], a[1]-c[1])
+
+		kept[k] = area > 0 && sides <= 2*alpha*area
+	}
+
+	// the edges of the border have the region on the left
+	var edges = make([][2]int, 0)
+	var leaving = make(map[int][]int)
+	for k, triangle := range triangulation.triangles {
+		if !kept[k] {
+			continue
+		}
+
+		for i, neighbor := range triangle.neighbor {
+			if neighbor == -1 || !kept[neighbor] {
+				var edge = [2]int{triangle.vertex[(i+1)%3], triangle.vertex[(i+2)%3]}
+				leaving[edge[0]] = append(leaving[edge[0]], len(edges))
+				edges = append(edges, edge)
+			}
+		}
+	}
+
+	// where rings touch at a vertex, the edge that follows is the first one clockwise from the way back, which keeps
+	// the region of the edge that arrives on the left
+	var next = func(edge [2]int) int {
+		var from, at = points[edge[0]], points[edge[1]]
+		var back = math.Atan2(from[1]-at[1], from[0]-at[0])
+
+		var best, bestAngle = -1, math.Inf(1)
+		for _, candidate := range leaving[edge[1]] {
+			var to = points[edges[candidate][1]]
+			var angle = math.Mod(back-math.Atan2(to[1]-at[1], to[0]-at[0])+4*math.Pi, 2*math.Pi)
+			if angle == 0 {
+				angle = 2 * math.Pi
+			}
+
+			if angle < bestAngle {
+				best, bestAngle = candidate, angle
+			}
+		}
+
+		return best
+	}
+
+	var used = make([]bool, len(edges))
+	var rings = make([][][2]float64, 0)
+	for k := range edges {
+		if used[k] {
+			continue
+		}
+
+		var ring = make([][2]float64, 0)
+		for edge := k; edge != -1 && !used[edge]; edge = next(edges[edge]) {
+			used[edge] = true
+			ring = append(ring, points[edges[edge][0]])
+		}
+
+		rings = append(rings, ring)
+	}
+
+	var areas = make([]float64, len(rings))
+	for k, ring := range rings {
+		areas[k] = alphaShapeArea(ring)
+		if areas[k] > 0 {
+			outer = append(outer, ring)
+		}
+	}
+
+	holes = make([][][][2]float64, len(outer))
+	for k, ring := range rings {
+		if areas[k] >= 0 {
+			continue
+		}
+
+		// the hole goes into the smallest outer ring that contains the middle of its first edge
+		var middle = [2]float64{(ring[0][0] + ring[1%len(ring)][0]) / 2, (ring[0][1] + ring[1%len(ring)][1]) / 2}
+		var owner, ownerArea = -1, math.Inf(1)
+		for o, candidate := range outer {
+			if area := alphaShapeArea(candidate); area < ownerArea && alphaShapeContains(candidate, middle) {
+				owner, ownerArea = o, area
+			}
+		}
+
+		if owner != -1 {
+			holes[owner] = append(holes[owner], ring)
+		}
+	}
+
+	return outer, holes
+}
+
+// alphaShapeArea is the signed area of the ring, positive when it is counterclockwise
+func alphaShapeArea(ring [][2]float64) float64 {
+	var area = 0.0
+	for k := range ring {
+		var a, b = ring[k], ring[(k+1)%len(ring)]
+		area += a[0]*b[1] - b[0]*a[1]
+	}
+
+	return area / 2
+}
+
+// alphaShapeContains tells if the point is inside the ring, by the crossings of a ray to the east
+func alphaShapeContains(ring [][2]float64, point [2]float64) bool {
+	var inside = false
+	for k := range ring {
+		var a, b = ring[k], ring[(k+len(ring)-1)%len(ring)]
+		if (a[1] > point[1]) != (b[1] > point[1]) && point[0] < a[0]+(point[1]-a[1])/(b[1]-a[1])*(b[0]-a[0]) {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
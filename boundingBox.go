@@ -1,14 +1,14 @@
 package iotmaker_geo_osm
 
-// Bounding box surrounding the point at given coordinates,
+// Bounding box surrounding the point at given coordinates, with the corners calculated by the selected distance model
 func BoundingBox(pointAStt PointStt, distanceAStt DistanceStt) BoxStt {
 	var returnLStt BoxStt
 	var angleLStt AngleStt
 	angleLStt.SetDegrees(-135.0)
-	returnLStt.BottomLeft = DestinationPoint(pointAStt, distanceAStt, angleLStt)
+	returnLStt.BottomLeft = destination(pointAStt, distanceAStt, angleLStt)
 
 	angleLStt.SetDegrees(45.0)
-	returnLStt.UpperRight = DestinationPoint(pointAStt, distanceAStt, angleLStt)
+	returnLStt.UpperRight = destination(pointAStt, distanceAStt, angleLStt)
 
 	return returnLStt
 }
@@ -0,0 +1,103 @@
+package iotmaker_geo_osm
+
+import (
+	"sync"
+)
+
+type DistanceModel int
+
+const (
+	// English: law of cosines on a sphere with the radius of the ellipsoid at the first point, the original model
+	//
+	// Português: lei dos cossenos em uma esfera com o raio do elipsoide no primeiro ponto, o modelo original
+	DISTANCE_MODEL_SPHERICAL DistanceModel = iota
+
+	// English: Vincenty's formulae on the ellipsoid of the datum, with Karney's algorithm when they do not converge
+	//
+	// Português: fórmulas de Vincenty no elipsoide do datum, com o algoritmo de Karney quando elas não convergem
+	DISTANCE_MODEL_VINCENTY
+
+	// English: Karney's algorithm on the ellipsoid of the datum
+	//
+	// Português: algoritmo de Karney no elipsoide do datum
+	DISTANCE_MODEL_KARNEY
+)
+
+var distanceModels = [...]string{
+	"Spherical",
+	"Vincenty",
+	"Karney",
+}
+
+func (e DistanceModel) String() string {
+	return distanceModels[e]
+}
+
+var distanceModelSelected = struct {
+	sync.RWMutex
+	model DistanceModel
+}{
+	model: DISTANCE_MODEL_SPHERICAL,
+}
+
+// English: Selects the model used to calculate the distances and angles of WayStt.Init() and PolygonStt.Init() and
+// the corners of BoundingBox(). The default is DISTANCE_MODEL_SPHERICAL.
+//
+// The model is global and can be selected by any goroutine, but the data processed by the others at the same time may
+// use both models.
+//
+// Português: Seleciona o modelo usado para calcular as distâncias e ângulos de WayStt.Init() e PolygonStt.Init() e os
+// cantos de BoundingBox(). O padrão é DISTANCE_MODEL_SPHERICAL.
+//
+// O modelo é global e pode ser selecionado por qualquer goroutine, mas os dados processados pelas outras ao mesmo tempo
+// podem usar os dois modelos.
+func SetDistanceModel(model DistanceModel) {
+	distanceModelSelected.Lock()
+	defer distanceModelSelected.Unlock()
+
+	distanceModelSelected.model = model
+}
+
+// English: Returns the model selected by SetDistanceModel().
+//
+// Português: Devolve o modelo selecionado por SetDistanceModel().
+func GetDistanceModel() DistanceModel {
+	distanceModelSelected.RLock()
+	defer distanceModelSelected.RUnlock()
+
+	return distanceModelSelected.model
+}
+
+// distanceAndDirection is the distance from point A to B and the direction at A, following the selected model.
+func distanceAndDirection(pointAAStt, pointBAStt PointStt) (DistanceStt, AngleStt) {
+	switch GetDistanceModel() {
+	case DISTANCE_MODEL_VINCENTY:
+		distance, angle, _, err := GeodesicInverseVincenty(pointAAStt, pointBAStt)
+		if err == nil {
+			return distance, angle
+		}
+
+	case DISTANCE_MODEL_SPHERICAL:
+		return DistanceBetweenTwoPoints(pointAAStt, pointBAStt), DirectionBetweenTwoPoints(pointAAStt, pointBAStt)
+	}
+
+	distance, angle, _ := GeodesicInverseKarney(pointAAStt, pointBAStt)
+	return distance, angle
+}
+
+// destination is the point at the distance and angle from point A, following the selected model.
+func destination(pointAStt PointStt, distanceAStt DistanceStt, angleAStt AngleStt) PointStt {
+	switch GetDistanceModel() {
+	case DISTANCE_MODEL_VINCENTY:
+		point, _, err := GeodesicDirectVincenty(pointAStt, distanceAStt, angleAStt)
+		if err == nil {
+			return point
+		}
+
+	case DISTANCE_MODEL_SPHERICAL:
+		return DestinationPoint(pointAStt, distanceAStt, angleAStt)
+	}
+
+	point, _ := GeodesicDirectKarney(pointAStt, distanceAStt, angleAStt)
+	return point
+}
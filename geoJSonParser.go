@@ -0,0 +1,601 @@
+package iotmaker_geo_osm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// English: Feature decoded by ParseGeoJSON().
+//
+// The geometry is decoded into the types of the package: Point and MultiPoint into Point, LineString and
+// MultiLineString into Way, Polygon into Polygon, with the holes in Inner, and MultiPolygon into PolygonList. The
+// geometries of a GeometryCollection are added to the same lists. The properties are copied into Tag of each element.
+//
+// Português: Feature decodificada por ParseGeoJSON().
+//
+// A geometria é decodificada nos tipos do pacote: Point e MultiPoint em Point, LineString e MultiLineString em Way,
+// Polygon em Polygon, com os buracos em Inner, e MultiPolygon em PolygonList. As geometrias de uma GeometryCollection
+// são adicionadas nas mesmas listas. As propriedades são copiadas em Tag de cada elemento.
+type GeoJSonFeatureStt struct {
+	// English: id of the feature, as text. When it is an integer number, it is also copied into Id of each element.
+	//
+	// Português: id da feature, como texto. Quando é um número inteiro, também é copiado em Id de cada elemento.
+	Id string
+
+	// English: type of the geometry, as "Polygon", or empty for features without geometry
+	//
+	// Português: tipo da geometria, como "Polygon", ou vazio para features sem geometria
+	Type string
+
+	// English: properties of the feature. Values that are not text are kept as JSON, and null values are ignored.
+	//
+	// Português: propriedades da feature. Valores que não são texto são mantidos como JSON e valores null são
+	// ignorados.
+	Tag map[string]string
+
+	// English: bbox of the feature or of its geometry, with four or six values
+	//
+	// Português: bbox da feature ou da sua geometria, com quatro ou seis valores
+	BBox []float64
+
+	// English: members of the feature and of its geometry not defined by RFC 7946
+	//
+	// Português: membros da feature e da sua geometria não definidos pela RFC 7946
+	ForeignMembers         map[string]json.RawMessage
+	GeometryForeignMembers map[string]json.RawMessage
+
+	// English: true when at least one position has three values
+	//
+	// Português: verdadeiro quando pelo menos uma posição tem três valores
+	HasAltitude bool
+
+	Point       []PointStt
+	Way         []WayStt
+	Polygon     []PolygonStt
+	PolygonList []PolygonListStt
+
+	idNumber int64
+}
+
+// English: Content decoded by ParseGeoJSON(). A single Feature or geometry becomes a list with one feature.
+//
+// Português: Conteúdo decodificado por ParseGeoJSON(). Uma única Feature ou geometria vira uma lista com uma feature.
+type GeoJSonDecodedStt struct {
+	BBox           []float64
+	ForeignMembers map[string]json.RawMessage
+	Features       []GeoJSonFeatureStt
+}
+
+var geoJSonMembersOfFeatureCollection = map[string]bool{"type": true, "features": true, "bbox": true}
+var geoJSonMembersOfFeature = map[string]bool{"type": true, "id": true, "geometry": true, "properties": true, "bbox": true}
+var geoJSonMembersOfGeometry = map[string]bool{"type": true, "coordinates": true, "geometries": true, "bbox": true}
+
+// English: Decodes a GeoJSON FeatureCollection, Feature or geometry into the types of the package.
+//
+// Positions may have two or three values, longitude, latitude and altitude, and the altitude is kept in Alt. Errors
+// tell the path of the malformed member, as "features[1].geometry.coordinates[0]".
+//
+// Português: Decodifica uma FeatureCollection, Feature ou geometria GeoJSON nos tipos do pacote.
+//
+// As posições podem ter dois ou três valores, longitude, latitude e altitude, e a altitude é mantida em Alt. Os erros
+// informam o caminho do membro mal formado, como "features[1].geometry.coordinates[0]".
+func ParseGeoJSON(data []byte) (GeoJSonDecodedStt, error) {
+	var ret = GeoJSonDecodedStt{}
+	var object map[string]json.RawMessage
+	var objectType string
+	var err error
+
+	object, objectType, err = geoJSonObject(data, "geojson")
+	if err != nil {
+		return ret, err
+	}
+
+	switch objectType {
+	case "FeatureCollection":
+		ret.BBox, err = geoJSonBBox(object, "bbox")
+		if err != nil {
+			return ret, err
+		}
+
+		ret.ForeignMembers = geoJSonForeignMembers(object, geoJSonMembersOfFeatureCollection)
+
+		var features []json.RawMessage
+		if _, found := object["features"]; !found {
+			return ret, errors.New("features: member not found")
+		}
+		err = json.Unmarshal(object["features"], &features)
+		if err != nil || features == nil {
+			return ret, errors.New("features: must be an array")
+		}
+
+		ret.Features = make([]GeoJSonFeatureStt, len(features))
+		for key, feature := range features {
+			ret.Features[key], err = geoJSonFeature(feature, fmt.Sprintf("features[%v]", key))
+			if err != nil {
+				return ret, err
+			}
+		}
+
+	case "Feature":
+		feature, err := geoJSonFeature(data, "feature")
+		if err != nil {
+			return ret, err
+		}
+		ret.Features = []GeoJSonFeatureStt{feature}
+
+	default:
+		var feature = GeoJSonFeatureStt{}
+		err = feature.addGeometry(data, "geometry")
+		if err != nil {
+			return ret, err
+		}
+
+		feature.BBox, err = geoJSonBBox(object, "bbox")
+		if err != nil {
+			return ret, err
+		}
+
+		feature.GeometryForeignMembers = geoJSonForeignMembers(object, geoJSonMembersOfGeometry)
+
+		err = feature.init()
+		if err != nil {
+			return ret, err
+		}
+		ret.Features = []GeoJSonFeatureStt{feature}
+	}
+
+	return ret, nil
+}
+
+// geoJSonObject decodes a JSON object and its type member.
+func geoJSonObject(data []byte, path string) (map[string]json.RawMessage, string, error) {
+	var object map[string]json.RawMessage
+	var objectType string
+
+	err := json.Unmarshal(data, &object)
+	if err != nil || object == nil {
+		return nil, "", fmt.Errorf("%v: must be an object", path)
+	}
+
+	err = json.Unmarshal(object["type"], &objectType)
+	if err != nil || objectType == "" {
+		return nil, "", fmt.Errorf("%v.type: must be a non empty string", path)
+	}
+
+	return object, objectType, nil
+}
+
+func geoJSonForeignMembers(object map[string]json.RawMessage, members map[string]bool) map[string]json.RawMessage {
+	var ret map[string]json.RawMessage
+
+	for key, value := range object {
+		if members[key] {
+			continue
+		}
+
+		if ret == nil {
+			ret = make(map[string]json.RawMessage)
+		}
+		ret[key] = value
+	}
+
+	return ret
+}
+
+func geoJSonBBox(object map[string]json.RawMessage, path string) ([]float64, error) {
+	var bbox []float64
+
+	data, found := object["bbox"]
+	if !found {
+		return nil, nil
+	}
+
+	err := json.Unmarshal(data, &bbox)
+	if err != nil {
+		return nil, fmt.Errorf("%v: must be an array of numbers", path)
+	}
+
+	if len(bbox) != 4 && len(bbox) != 6 {
+		return nil, fmt.Errorf("%v: must have four or six values, found %v", path, len(bbox))
+	}
+
+	return bbox, nil
+}
+
+func geoJSonFeature(data []byte, path string) (GeoJSonFeatureStt, error) {
+	var ret = GeoJSonFeatureStt{}
+
+	object, objectType, err := geoJSonObject(data, path)
+	if err != nil {
+		return ret, err
+	}
+
+	if objectType != "Feature" {
+		return ret, fmt.Errorf("%v.type: expected Feature, found %v", path, objectType)
+	}
+
+	ret.BBox, err = geoJSonBBox(object, path+".bbox")
+	if err != nil {
+		return ret, err
+	}
+
+	ret.ForeignMembers = geoJSonForeignMembers(object, geoJSonMembersOfFeature)
+
+	err = ret.decodeId(object["id"], path+".id")
+	if err != nil {
+		return ret, err
+	}
+
+	err = ret.decodeProperties(object["properties"], path+".properties")
+	if err != nil {
+		return ret, err
+	}
+
+	geometry, found := object["geometry"]
+	if !found {
+		return ret, fmt.Errorf("%v.geometry: member not found", path)
+	}
+
+	if !bytes.Equal(bytes.TrimSpace(geometry), []byte("null")) {
+		err = ret.addGeometry(geometry, path+".geometry")
+		if err != nil {
+			return ret, err
+		}
+
+		var geometryObject map[string]json.RawMessage
+		_ = json.Unmarshal(geometry, &geometryObject)
+		ret.GeometryForeignMembers = geoJSonForeignMembers(geometryObject, geoJSonMembersOfGeometry)
+
+		if ret.BBox == nil {
+			ret.BBox, err = geoJSonBBox(geometryObject, path+".geometry.bbox")
+			if err != nil {
+				return ret, err
+			}
+		}
+	}
+
+	err = ret.init()
+	if err != nil {
+		return ret, fmt.Errorf("%v: %v", path, err)
+	}
+
+	return ret, nil
+}
+
+func (el *GeoJSonFeatureStt) decodeId(data json.RawMessage, path string) error {
+	if data == nil {
+		return nil
+	}
+
+	var text string
+	var number json.Number
+	var decoder = json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if json.Unmarshal(data, &text) == nil {
+		el.Id = text
+	} else if decoder.Decode(&number) == nil {
+		el.Id = number.String()
+	} else {
+		return fmt.Errorf("%v: must be a string or a number", path)
+	}
+
+	id, err := strconv.ParseInt(el.Id, 10, 64)
+	if err == nil {
+		el.idNumber = id
+	}
+
+	return nil
+}
+
+func (el *GeoJSonFeatureStt) decodeProperties(data json.RawMessage, path string) error {
+	var properties map[string]json.RawMessage
+
+	if data == nil {
+		return nil
+	}
+
+	err := json.Unmarshal(data, &properties)
+	if err != nil {
+		return fmt.Errorf("%v: must be an object or null", path)
+	}
+
+	for key, value := range properties {
+		var text string
+
+		value = bytes.TrimSpace(value)
+		if bytes.Equal(value, []byte("null")) {
+			continue
+		}
+
+		if el.Tag == nil {
+			el.Tag = make(map[string]string)
+		}
+
+		if json.Unmarshal(value, &text) == nil {
+			el.Tag[key] = text
+			continue
+		}
+
+		var buffer = bytes.Buffer{}
+		if json.Compact(&buffer, value) == nil {
+			el.Tag[key] = buffer.String()
+		} else {
+			el.Tag[key] = string(value)
+		}
+	}
+
+	return nil
+}
+
+// addGeometry decodes a geometry object and adds its elements to the lists of the feature.
+func (el *GeoJSonFeatureStt) addGeometry(data []byte, path string) error {
+	var err error
+
+	object, objectType, err := geoJSonObject(data, path)
+	if err != nil {
+		return err
+	}
+
+	if objectType == GEOJSON_GEOMETRY_COLLECTION.String() {
+		var geometries []json.RawMessage
+
+		err = json.Unmarshal(object["geometries"], &geometries)
+		if err != nil || geometries == nil {
+			return fmt.Errorf("%v.geometries: must be an array", path)
+		}
+
+		if el.Type == "" {
+			el.Type = objectType
+		}
+
+		for key, geometry := range geometries {
+			err = el.addGeometry(geometry, fmt.Sprintf("%v.geometries[%v]", path, key))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	coordinates, found := object["coordinates"]
+	if !found {
+		return fmt.Errorf("%v.coordinates: member not found", path)
+	}
+	path += ".coordinates"
+
+	switch objectType {
+	case GEOJSON_POINT.String():
+		var position []float64
+		err = geoJSonUnmarshal(coordinates, &position, path)
+		if err != nil {
+			return err
+		}
+
+		point, err := el.point(position, path)
+		if err != nil {
+			return err
+		}
+		el.Point = append(el.Point, point)
+
+	case GEOJSON_MULTI_POINT.String():
+		var positionList [][]float64
+		err = geoJSonUnmarshal(coordinates, &positionList, path)
+		if err != nil {
+			return err
+		}
+
+		for key, position := range positionList {
+			point, err := el.point(position, fmt.Sprintf("%v[%v]", path, key))
+			if err != nil {
+				return err
+			}
+			el.Point = append(el.Point, point)
+		}
+
+	case GEOJSON_LINE_STRING.String():
+		var positionList [][]float64
+		err = geoJSonUnmarshal(coordinates, &positionList, path)
+		if err != nil {
+			return err
+		}
+
+		way, err := el.way(positionList, path)
+		if err != nil {
+			return err
+		}
+		el.Way = append(el.Way, way)
+
+	case GEOJSON_MULTI_LINE_STRING.String():
+		var lineList [][][]float64
+		err = geoJSonUnmarshal(coordinates, &lineList, path)
+		if err != nil {
+			return err
+		}
+
+		for key, positionList := range lineList {
+			way, err := el.way(positionList, fmt.Sprintf("%v[%v]", path, key))
+			if err != nil {
+				return err
+			}
+			el.Way = append(el.Way, way)
+		}
+
+	case GEOJSON_POLYGON.String():
+		var ringList [][][]float64
+		err = geoJSonUnmarshal(coordinates, &ringList, path)
+		if err != nil {
+			return err
+		}
+
+		polygon, err := el.polygon(ringList, path)
+		if err != nil {
+			return err
+		}
+		el.Polygon = append(el.Polygon, polygon)
+
+	case GEOJSON_MULTI_POLYGON.String():
+		var polygonList [][][][]float64
+		err = geoJSonUnmarshal(coordinates, &polygonList, path)
+		if err != nil {
+			return err
+		}
+
+		var list = PolygonListStt{Id: el.idNumber, Tag: el.Tag}
+		for key, ringList := range polygonList {
+			polygon, err := el.polygon(ringList, fmt.Sprintf("%v[%v]", path, key))
+			if err != nil {
+				return err
+			}
+			list.AddPolygon(&polygon)
+		}
+		el.PolygonList = append(el.PolygonList, list)
+
+	default:
+		return fmt.Errorf("%v.type: unknown geometry type %v", path[:len(path)-len(".coordinates")], objectType)
+	}
+
+	if el.Type == "" {
+		el.Type = objectType
+	}
+
+	return nil
+}
+
+func geoJSonUnmarshal(data []byte, value interface{}, path string) error {
+	err := json.Unmarshal(data, value)
+	if err != nil {
+		return fmt.Errorf("%v: malformed coordinates: %v", path, err)
+	}
+
+	return nil
+}
+
+func (el *GeoJSonFeatureStt) point(position []float64, path string) (PointStt, error) {
+	var point = PointStt{Id: el.idNumber, Tag: el.Tag}
+
+	if len(position) < 2 {
+		return point, fmt.Errorf("%v: a position must have at least two values", path)
+	}
+
+	if position[0] < -180 || position[0] > 180 || position[1] < -90 || position[1] > 90 {
+		return point, fmt.Errorf("%v: longitude must be between -180 and 180 and latitude between -90 and 90, found %v", path, position[:2])
+	}
+
+	err := point.SetLngLatDegrees(position[0], position[1])
+	if err != nil {
+		return point, fmt.Errorf("%v: %v", path, err)
+	}
+
+	if len(position) > 2 {
+		point.Alt = position[2]
+		el.HasAltitude = true
+	}
+
+	return point, nil
+}
+
+func (el *GeoJSonFeatureStt) way(positionList [][]float64, path string) (WayStt, error) {
+	var way = WayStt{Id: el.idNumber, Tag: el.Tag, Visible: true}
+	var hasAltitude bool
+
+	if len(positionList) < 2 {
+		return way, fmt.Errorf("%v: a line string must have at least two positions", path)
+	}
+
+	for key, position := range positionList {
+		point, err := el.point(position, fmt.Sprintf("%v[%v]", path, key))
+		if err != nil {
+			return way, err
+		}
+
+		way.Loc = append(way.Loc, point.Loc)
+		way.Rad = append(way.Rad, point.Rad)
+		way.Alt = append(way.Alt, point.Alt)
+		hasAltitude = hasAltitude || len(position) > 2
+	}
+
+	if !hasAltitude {
+		way.Alt = nil
+	}
+
+	return way, nil
+}
+
+func (el *GeoJSonFeatureStt) polygon(ringList [][][]float64, path string) (PolygonStt, error) {
+	var polygon = PolygonStt{Id: el.idNumber}
+
+	if len(ringList) == 0 {
+		return polygon, fmt.Errorf("%v: a polygon must have at least one ring", path)
+	}
+
+	for key, positionList := range ringList {
+		var ring = PolygonStt{Id: el.idNumber}
+		var ringPath = fmt.Sprintf("%v[%v]", path, key)
+
+		if len(positionList) < 4 {
+			return polygon, fmt.Errorf("%v: a linear ring must have at least four positions", ringPath)
+		}
+
+		var first = positionList[0]
+		var last = positionList[len(positionList)-1]
+		if len(first) < 2 || len(last) < 2 || first[0] != last[0] || first[1] != last[1] {
+			return polygon, fmt.Errorf("%v: the first and the last positions of a linear ring must be the same", ringPath)
+		}
+
+		for pointKey, position := range positionList {
+			point, err := el.point(position, fmt.Sprintf("%v[%v]", ringPath, pointKey))
+			if err != nil {
+				return polygon, err
+			}
+			point.Id = 0
+			point.Tag = nil
+			ring.PointsList = append(ring.PointsList, point)
+		}
+
+		if key == 0 {
+			polygon = ring
+		} else {
+			polygon.Inner = append(polygon.Inner, ring)
+		}
+	}
+
+	polygon.Tag = el.Tag
+
+	return polygon, nil
+}
+
+// init calls Init() of the decoded elements.
+func (el *GeoJSonFeatureStt) init() error {
+	var err error
+
+	for key := range el.Way {
+		err = el.Way[key].Init()
+		if err != nil {
+			return err
+		}
+	}
+
+	for key := range el.Polygon {
+		err = el.Polygon[key].Init()
+		if err != nil {
+			return err
+		}
+	}
+
+	for key := range el.PolygonList {
+		for polygonKey := range el.PolygonList[key].List {
+			err = el.PolygonList[key].List[polygonKey].Init()
+			if err != nil {
+				return err
+			}
+		}
+		el.PolygonList[key].Initialize()
+	}
+
+	return nil
+}
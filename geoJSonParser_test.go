@@ -0,0 +1,49 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+func ExampleParseGeoJSON() {
+	var geoJSon = `{
+  "type": "FeatureCollection",
+  "features": [
+    {
+      "type": "Feature",
+      "id": 7,
+      "properties": {"name": "park", "level": 2, "closed": null},
+      "geometry": {
+        "type": "Polygon",
+        "coordinates": [
+          [[0, 0], [4, 0], [4, 4], [0, 4], [0, 0]],
+          [[1, 1], [1, 2], [2, 2], [2, 1], [1, 1]]
+        ]
+      }
+    },
+    {
+      "type": "Feature",
+      "properties": null,
+      "geometry": {"type": "LineString", "coordinates": [[-43.2, -22.9, 10], [-43.1, -22.8, 12.5]]},
+      "source": "front end"
+    }
+  ]
+}`
+
+	decoded, err := ParseGeoJSON([]byte(geoJSon))
+	fmt.Printf("error: %v\n", err)
+
+	var polygon = decoded.Features[0].Polygon[0]
+	fmt.Printf("%v %v: id %v, points %v, holes %v, tag %v\n", decoded.Features[0].Type, decoded.Features[0].Id, polygon.Id, len(polygon.PointsList), len(polygon.Inner), polygon.Tag)
+
+	var way = decoded.Features[1].Way[0]
+	fmt.Printf("%v: %v %v, altitude %v, foreign members %s\n", decoded.Features[1].Type, way.Loc, way.Alt, decoded.Features[1].HasAltitude, decoded.Features[1].ForeignMembers["source"])
+
+	_, err = ParseGeoJSON([]byte(`{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1]]]}}`))
+	fmt.Printf("error: %v\n", err)
+
+	// Output:
+	// error: <nil>
+	// Polygon 7: id 7, points 5, holes 1, tag map[level:2 name:park]
+	// LineString: [[-43.2 -22.9] [-43.1 -22.8]] [10 12.5], altitude true, foreign members "front end"
+	// error: feature.geometry.coordinates[0]: the first and the last positions of a linear ring must be the same
+}
@@ -0,0 +1,595 @@
+package iotmaker_geo_osm
+
+import (
+	"math"
+	"sort"
+)
+
+// geoJSonShapeStt is the geometry of a feature in a form common to all geometry types, used to write the output.
+type geoJSonShapeStt struct {
+	typeConst GeoJSonType
+	positions [][3]float64
+	lines     [][][3]float64
+	polygons  [][][][3]float64
+}
+
+func (el *geoJSonShapeStt) eachPosition(function func(position *[3]float64)) {
+	for k := range el.positions {
+		function(&el.positions[k])
+	}
+
+	for k := range el.lines {
+		for p := range el.lines[k] {
+			function(&el.lines[k][p])
+		}
+	}
+
+	for k := range el.polygons {
+		for r := range el.polygons[k] {
+			for p := range el.polygons[k][r] {
+				function(&el.polygons[k][r][p])
+			}
+		}
+	}
+}
+
+func (el *geoJSonShapeStt) hasAltitude() bool {
+	var ret = false
+
+	el.eachPosition(func(position *[3]float64) {
+		ret = ret || position[2] != 0
+	})
+
+	return ret
+}
+
+// geoJSonShapeFromGeometry copies the coordinates of the geometry built by GeoJSon.
+func geoJSonShapeFromGeometry(geometry *geometry) (geoJSonShapeStt, bool) {
+	var shape = geoJSonShapeStt{typeConst: geometry.typeConst}
+
+	switch coordinates := geometry.Coordinates.(type) {
+	case [3]float64:
+		shape.positions = [][3]float64{coordinates}
+
+	case []lineString:
+		var line = make([][3]float64, len(coordinates))
+		for k := range coordinates {
+			line[k] = coordinates[k]
+		}
+		shape.lines = [][][3]float64{line}
+
+	case []multiPoint:
+		for k := range coordinates {
+			shape.positions = append(shape.positions, coordinates[k])
+		}
+
+	case []polygon:
+		var rings = make([][][3]float64, len(coordinates))
+		for k := range coordinates {
+			rings[k] = append([][3]float64{}, coordinates[k]...)
+		}
+		shape.polygons = [][][][3]float64{rings}
+
+	case []multiLineString:
+		for k := range coordinates {
+			shape.lines = append(shape.lines, append([][3]float64{}, coordinates[k]...))
+		}
+
+	case []multiPolygon:
+		for k := range coordinates {
+			var rings = make([][][3]float64, len(coordinates[k]))
+			for r := range coordinates[k] {
+				rings[r] = append([][3]float64{}, coordinates[k][r]...)
+			}
+			shape.polygons = append(shape.polygons, rings)
+		}
+
+	default:
+		return shape, false
+	}
+
+	return shape, true
+}
+
+// outputFeature returns the feature as it must be written, following the Rfc7946 and Precision options.
+func (e *GeoJSon) outputFeature(feature features) features {
+	if e.Rfc7946 == false && e.Precision <= 0 {
+		return feature
+	}
+
+	if feature.Geometry.typeConst != GEOJSON_GEOMETRY_COLLECTION {
+		shape, crossAntimeridian, ok := e.outputShape(&feature.Geometry)
+		if ok {
+			feature.Geometry = e.outputGeometry(feature.Geometry, &shape, crossAntimeridian, shape.hasAltitude())
+		}
+
+		return feature
+	}
+
+	// the geometries of a collection share the number of dimensions and the box is made over all of them
+	var collection = geoJSonShapeStt{typeConst: GEOJSON_GEOMETRY_COLLECTION}
+	var shapes = make([]geoJSonShapeStt, len(feature.Geometry.Geometries))
+	var valid = make([]bool, len(feature.Geometry.Geometries))
+	var crossed = make([]bool, len(feature.Geometry.Geometries))
+	var crossAntimeridian = false
+
+	for k := range feature.Geometry.Geometries {
+		shapes[k], crossed[k], valid[k] = e.outputShape(&feature.Geometry.Geometries[k])
+		if valid[k] == false {
+			continue
+		}
+
+		crossAntimeridian = crossAntimeridian || crossed[k]
+		collection.positions = append(collection.positions, shapes[k].positions...)
+		collection.lines = append(collection.lines, shapes[k].lines...)
+		collection.polygons = append(collection.polygons, shapes[k].polygons...)
+	}
+
+	var altitude = collection.hasAltitude()
+	var geometries = make([]geometry, len(feature.Geometry.Geometries))
+	for k := range feature.Geometry.Geometries {
+		geometries[k] = feature.Geometry.Geometries[k]
+		if valid[k] {
+			geometries[k] = e.outputGeometry(geometries[k], &shapes[k], crossed[k], altitude)
+		}
+	}
+
+	feature.Geometry = e.outputGeometry(feature.Geometry, &collection, crossAntimeridian, altitude)
+	feature.Geometry.Geometries = geometries
+
+	return feature
+}
+
+// outputShape copies the coordinates of the geometry and, in Rfc7946 mode, splits them at the antimeridian and
+// applies the right-hand rule. It also reports whether the geometry was split.
+func (e *GeoJSon) outputShape(geometry *geometry) (geoJSonShapeStt, bool, bool) {
+	shape, ok := geoJSonShapeFromGeometry(geometry)
+	if !ok || e.Rfc7946 == false {
+		return shape, false, ok
+	}
+
+	var crossAntimeridian = shape.splitAntimeridian()
+	shape.rightHandRule()
+
+	return shape, crossAntimeridian, true
+}
+
+// outputGeometry writes the shape into a copy of the geometry, rounded to Precision.
+func (e *GeoJSon) outputGeometry(geometry geometry, shape *geoJSonShapeStt, crossAntimeridian, altitude bool) geometry {
+	var dimensions = 3
+	if e.Rfc7946 && altitude == false {
+		dimensions = 2
+	}
+
+	geometry.Type = shape.typeConst.String()
+	geometry.typeConst = shape.typeConst
+	if shape.typeConst != GEOJSON_GEOMETRY_COLLECTION {
+		geometry.Coordinates = shape.coordinates(dimensions, e.Precision)
+	}
+
+	if e.Rfc7946 {
+		geometry.BoundingBox = shape.boundingBox(dimensions, crossAntimeridian, e.Precision)
+	} else if box, ok := geometry.BoundingBox.([4]float64); ok {
+		for k := range box {
+			box[k] = geoJSonRound(box[k], e.Precision)
+		}
+		geometry.BoundingBox = box
+	}
+
+	return geometry
+}
+
+func geoJSonRound(value float64, precision int) float64 {
+	if precision <= 0 {
+		return value
+	}
+
+	var power = math.Pow(10, float64(precision))
+	return math.Round(value*power) / power
+}
+
+func geoJSonPosition(position [3]float64, dimensions, precision int) []float64 {
+	var ret = make([]float64, dimensions)
+	for k := range ret {
+		ret[k] = geoJSonRound(position[k], precision)
+	}
+
+	return ret
+}
+
+func geoJSonPositionList(list [][3]float64, dimensions, precision int) [][]float64 {
+	var ret = make([][]float64, len(list))
+	for k := range list {
+		ret[k] = geoJSonPosition(list[k], dimensions, precision)
+	}
+
+	return ret
+}
+
+// coordinates returns the coordinates member of the geometry.
+func (el *geoJSonShapeStt) coordinates(dimensions, precision int) interface{} {
+	switch el.typeConst {
+	case GEOJSON_POINT:
+		return geoJSonPosition(el.positions[0], dimensions, precision)
+
+	case GEOJSON_MULTI_POINT:
+		return geoJSonPositionList(el.positions, dimensions, precision)
+
+	case GEOJSON_LINE_STRING:
+		return geoJSonPositionList(el.lines[0], dimensions, precision)
+
+	case GEOJSON_MULTI_LINE_STRING:
+		var ret = make([][][]float64, len(el.lines))
+		for k := range el.lines {
+			ret[k] = geoJSonPositionList(el.lines[k], dimensions, precision)
+		}
+		return ret
+
+	case GEOJSON_POLYGON:
+		var ret = make([][][]float64, len(el.polygons[0]))
+		for r := range el.polygons[0] {
+			ret[r] = geoJSonPositionList(el.polygons[0][r], dimensions, precision)
+		}
+		return ret
+
+	case GEOJSON_MULTI_POLYGON:
+		var ret = make([][][][]float64, len(el.polygons))
+		for k := range el.polygons {
+			ret[k] = make([][][]float64, len(el.polygons[k]))
+			for r := range el.polygons[k] {
+				ret[k][r] = geoJSonPositionList(el.polygons[k][r], dimensions, precision)
+			}
+		}
+		return ret
+	}
+
+	return nil
+}
+
+// boundingBox follows RFC 7946 section 5: [west, south, east, north], with the altitudes after south and north in
+// three dimensions. When the geometry crosses the antimeridian, west is greater than east.
+func (el *geoJSonShapeStt) boundingBox(dimensions int, crossAntimeridian bool, precision int) []float64 {
+	var minimum = [3]float64{math.MaxFloat64, math.MaxFloat64, math.MaxFloat64}
+	var maximum = [3]float64{-math.MaxFloat64, -math.MaxFloat64, -math.MaxFloat64}
+	var found = false
+
+	el.eachPosition(func(position *[3]float64) {
+		found = true
+		for k := 0; k != 3; k += 1 {
+			minimum[k] = math.Min(minimum[k], position[k])
+			maximum[k] = math.Max(maximum[k], position[k])
+		}
+	})
+
+	if found == false {
+		return nil
+	}
+
+	if crossAntimeridian {
+		minimum[0], maximum[0] = el.longitudeRange()
+	}
+
+	var ret []float64
+	if dimensions == 3 {
+		ret = []float64{minimum[0], minimum[1], minimum[2], maximum[0], maximum[1], maximum[2]}
+	} else {
+		ret = []float64{minimum[0], minimum[1], maximum[0], maximum[1]}
+	}
+
+	for k := range ret {
+		ret[k] = geoJSonRound(ret[k], precision)
+	}
+
+	return ret
+}
+
+// longitudeRange returns the west and east longitudes of the smallest range that contains all pieces of the
+// geometry, the complement of the largest gap between them around the globe.
+func (el *geoJSonShapeStt) longitudeRange() (float64, float64) {
+	var intervals = make([][2]float64, 0)
+	var add = func(list [][3]float64) {
+		var interval = [2]float64{math.MaxFloat64, -math.MaxFloat64}
+		for _, position := range list {
+			interval[0] = math.Min(interval[0], position[0])
+			interval[1] = math.Max(interval[1], position[0])
+		}
+		if len(list) != 0 {
+			intervals = append(intervals, interval)
+		}
+	}
+
+	for _, position := range el.positions {
+		add([][3]float64{position})
+	}
+	for _, line := range el.lines {
+		add(line)
+	}
+	for _, rings := range el.polygons {
+		if len(rings) != 0 {
+			add(rings[0])
+		}
+	}
+
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i][0] < intervals[j][0] })
+
+	// the gap across the antimeridian, from the east end of the last interval to the west end of the first one
+	var west = intervals[0][0]
+	var east = -math.MaxFloat64
+	for _, interval := range intervals {
+		east = math.Max(east, interval[1])
+	}
+	var largestGap = west + 360 - east
+	var reach = intervals[0][1]
+
+	for _, interval := range intervals[1:] {
+		if interval[0]-reach > largestGap {
+			largestGap = interval[0] - reach
+			west = interval[0]
+			east = reach
+		}
+		reach = math.Max(reach, interval[1])
+	}
+
+	return west, east
+}
+
+// rightHandRule turns the outer rings counterclockwise and the holes clockwise, as RFC 7946 section 3.1.6.
+func (el *geoJSonShapeStt) rightHandRule() {
+	for k := range el.polygons {
+		for r := range el.polygons[k] {
+			var area = geoJSonSignedArea(el.polygons[k][r])
+			if (r == 0 && area < 0) || (r != 0 && area > 0) {
+				geoJSonReverse(el.polygons[k][r])
+			}
+		}
+	}
+}
+
+func geoJSonSignedArea(ring [][3]float64) float64 {
+	var area float64
+
+	for i := 0; i < len(ring); i += 1 {
+		var j = (i + 1) % len(ring)
+		area += ring[i][0]*ring[j][1] - ring[j][0]*ring[i][1]
+	}
+
+	return area / 2
+}
+
+func geoJSonReverse(ring [][3]float64) {
+	for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+		ring[i], ring[j] = ring[j], ring[i]
+	}
+}
+
+// geoJSonLongitude puts the longitude between -180 and 180.
+func geoJSonLongitude(longitude float64) float64 {
+	if longitude >= -180 && longitude <= 180 {
+		return longitude
+	}
+
+	longitude = math.Mod(longitude+180, 360)
+	if longitude < 0 {
+		longitude += 360
+	}
+
+	return longitude - 180
+}
+
+// geoJSonUnwrap makes the longitudes continuous, so an edge from 179 to -179 becomes an edge from 179 to 181. The
+// edges are taken as the shortest way between their points.
+func geoJSonUnwrap(list [][3]float64) [][3]float64 {
+	var ret = make([][3]float64, len(list))
+
+	for k := range list {
+		ret[k] = list[k]
+		if k == 0 {
+			ret[k][0] = geoJSonLongitude(ret[k][0])
+			continue
+		}
+
+		var delta = list[k][0] - list[k-1][0]
+		delta = math.Mod(delta+180, 360)
+		if delta < 0 {
+			delta += 360
+		}
+		ret[k][0] = ret[k-1][0] + delta - 180
+	}
+
+	return ret
+}
+
+// splitAntimeridian splits the lines and polygons that cross ±180°, as RFC 7946 section 3.1.9. LineString and Polygon
+// become MultiLineString and MultiPolygon when split.
+func (el *geoJSonShapeStt) splitAntimeridian() bool {
+	var crossed = false
+
+	for k := range el.positions {
+		el.positions[k][0] = geoJSonLongitude(el.positions[k][0])
+	}
+
+	if len(el.lines) != 0 {
+		var lines = make([][][3]float64, 0, len(el.lines))
+		for k := range el.lines {
+			var pieces = geoJSonSplitLine(el.lines[k])
+			crossed = crossed || len(pieces) > 1
+			lines = append(lines, pieces...)
+		}
+		el.lines = lines
+
+		if el.typeConst == GEOJSON_LINE_STRING && len(el.lines) > 1 {
+			el.typeConst = GEOJSON_MULTI_LINE_STRING
+		}
+	}
+
+	if len(el.polygons) != 0 {
+		var polygons = make([][][][3]float64, 0, len(el.polygons))
+		for k := range el.polygons {
+			var pieces = geoJSonSplitPolygon(el.polygons[k])
+			crossed = crossed || len(pieces) > 1
+			polygons = append(polygons, pieces...)
+		}
+		el.polygons = polygons
+
+		if el.typeConst == GEOJSON_POLYGON && len(el.polygons) > 1 {
+			el.typeConst = GEOJSON_MULTI_POLYGON
+		}
+	}
+
+	return crossed
+}
+
+func geoJSonSplitLine(line [][3]float64) [][][3]float64 {
+	if len(line) == 0 {
+		return [][][3]float64{line}
+	}
+
+	var unwrapped = geoJSonUnwrap(line)
+	var ret = make([][][3]float64, 0)
+	var piece = [][3]float64{unwrapped[0]}
+	var band = 0.0
+
+	for k := 1; k < len(unwrapped); k += 1 {
+		var a = unwrapped[k-1]
+		var b = unwrapped[k]
+
+		for b[0] > 180+360*band || b[0] < -180+360*band {
+			var boundary = -180 + 360*band
+			if b[0] > a[0] {
+				boundary = 180 + 360*band
+			}
+
+			var t = (boundary - a[0]) / (b[0] - a[0])
+			var cross = [3]float64{boundary, a[1] + t*(b[1]-a[1]), a[2] + t*(b[2]-a[2])}
+
+			piece = append(piece, [3]float64{cross[0] - 360*band, cross[1], cross[2]})
+			if len(piece) > 1 && piece[0] != piece[len(piece)-1] {
+				ret = append(ret, piece)
+			}
+
+			if b[0] > a[0] {
+				band += 1
+			} else {
+				band -= 1
+			}
+
+			piece = [][3]float64{{cross[0] - 360*band, cross[1], cross[2]}}
+			a = cross
+		}
+
+		piece = append(piece, [3]float64{b[0] - 360*band, b[1], b[2]})
+	}
+
+	if len(piece) > 1 && piece[0] != piece[len(piece)-1] || len(ret) == 0 {
+		ret = append(ret, piece)
+	}
+
+	return ret
+}
+
+// geoJSonSplitPolygon cuts the polygon in the bands of 360° around each multiple of 360°, with the
+// Sutherland-Hodgman algorithm, and moves each piece back to -180..180. Each hole goes with the pieces of the same
+// band. Rings around a pole, that do not close after unwrapped, are not split.
+func geoJSonSplitPolygon(rings [][][3]float64) [][][][3]float64 {
+	if len(rings) == 0 || len(rings[0]) == 0 {
+		return [][][][3]float64{rings}
+	}
+
+	var unwrapped = make([][][3]float64, len(rings))
+	for r := range rings {
+		unwrapped[r] = geoJSonUnwrap(rings[r])
+
+		var length = len(unwrapped[r])
+		if math.Abs(unwrapped[r][0][0]-unwrapped[r][length-1][0]) > 1e-9 {
+			return [][][][3]float64{rings}
+		}
+
+		// the holes must be in the same longitudes as the outer ring
+		if r != 0 {
+			var shift = 360 * math.Round((unwrapped[0][0][0]-unwrapped[r][0][0])/360)
+			for p := range unwrapped[r] {
+				unwrapped[r][p][0] += shift
+			}
+		}
+	}
+
+	var minimum, maximum = math.MaxFloat64, -math.MaxFloat64
+	for _, position := range unwrapped[0] {
+		minimum = math.Min(minimum, position[0])
+		maximum = math.Max(maximum, position[0])
+	}
+
+	if minimum >= -180 && maximum <= 180 {
+		for r := range unwrapped {
+			for p := range unwrapped[r] {
+				unwrapped[r][p][0] = geoJSonLongitude(unwrapped[r][p][0])
+			}
+		}
+		return [][][][3]float64{unwrapped}
+	}
+
+	var ret = make([][][][3]float64, 0)
+	for band := math.Floor((minimum + 180) / 360); band <= math.Ceil((maximum-180)/360); band += 1 {
+		var west = -180 + 360*band
+		var east = 180 + 360*band
+		var piece = make([][][3]float64, 0)
+
+		for r := range unwrapped {
+			var ring = geoJSonClip(unwrapped[r], west, true)
+			ring = geoJSonClip(ring, east, false)
+
+			if len(ring) < 3 || geoJSonSignedArea(ring) == 0 {
+				if r == 0 {
+					break
+				}
+				continue
+			}
+
+			for p := range ring {
+				ring[p][0] -= 360 * band
+			}
+			ring = append(ring, ring[0])
+
+			piece = append(piece, ring)
+		}
+
+		if len(piece) != 0 {
+			ret = append(ret, piece)
+		}
+	}
+
+	return ret
+}
+
+// geoJSonClip keeps the part of the ring at the east of the longitude, when keepEast is true, or at the west. The
+// ring returned is open, the last point is not the first one.
+func geoJSonClip(ring [][3]float64, longitude float64, keepEast bool) [][3]float64 {
+	var inside = func(position [3]float64) bool {
+		if keepEast {
+			return position[0] >= longitude
+		}
+		return position[0] <= longitude
+	}
+
+	var length = len(ring)
+	if length > 1 && ring[0] == ring[length-1] {
+		length -= 1
+	}
+
+	var ret = make([][3]float64, 0, length+2)
+	for k := 0; k != length; k += 1 {
+		var current = ring[k]
+		var previous = ring[(k+length-1)%length]
+
+		if inside(current) != inside(previous) {
+			var t = (longitude - previous[0]) / (current[0] - previous[0])
+			ret = append(ret, [3]float64{longitude, previous[1] + t*(current[1]-previous[1]), previous[2] + t*(current[2]-previous[2])})
+		}
+
+		if inside(current) {
+			ret = append(ret, current)
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,42 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+func ExampleGeoJSon_String() {
+	var geoJSon = GeoJSon{}
+	geoJSon.Init()
+	geoJSon.Rfc7946 = true
+	geoJSon.Precision = 3
+
+	// clockwise outer ring, written counterclockwise
+	geoJSon.NewFeature("1", GEOJSON_POLYGON)
+	for _, loc := range [][2]float64{{0, 0}, {0, 1}, {1, 1}, {1, 0}} {
+		geoJSon.AddLngLat(loc[0], loc[1])
+	}
+	geoJSon.ClosePolygon()
+	geoJSon.MakeBoundingBox()
+
+	// line from Fiji to Samoa, crossing the antimeridian
+	geoJSon.NewFeature("2", GEOJSON_LINE_STRING)
+	geoJSon.AddLngLat(178.4417, -18.1416)
+	geoJSon.AddLngLat(-171.7514, -13.8333)
+	geoJSon.MakeBoundingBox()
+
+	// polygon crossing the antimeridian, with altitude
+	geoJSon.NewFeature("3", GEOJSON_POLYGON)
+	for _, loc := range [][2]float64{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}} {
+		geoJSon.AddLngLatAlt(loc[0], loc[1], 100)
+	}
+	geoJSon.ClosePolygon()
+	geoJSon.MakeBoundingBox()
+
+	output, err := geoJSon.String()
+	fmt.Printf("error: %v\n", err)
+	fmt.Println(output)
+
+	// Output:
+	// error: <nil>
+	// {"type":"FeatureCollection","features":[{"type":"Feature","id":"1","properties":null,"geometry":{"type":"Polygon","bbox":[0,0,1,1],"coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}},{"type":"Feature","id":"2","properties":null,"geometry":{"type":"MultiLineString","bbox":[178.442,-18.142,-171.751,-13.833],"coordinates":[[[178.442,-18.142],[180,-17.457]],[[-180,-17.457],[-171.751,-13.833]]]}},{"type":"Feature","id":"3","properties":null,"geometry":{"type":"MultiPolygon","bbox":[170,-10,100,-170,10,100],"coordinates":[[[[170,-10,100],[180,-10,100],[180,10,100],[170,10,100],[170,-10,100]]],[[[-180,-10,100],[-170,-10,100],[-170,10,100],[-180,10,100],[-180,-10,100]]]]}}]}
+}
@@ -11,3 +11,27 @@ func RadiansToDegrees(a float64) float64 { return 180.0 * a / math.Pi }
 func Pythagoras(x1, y1, x2, y2 float64) float64 {
 	return math.Sqrt(math.Pow(x2-x1, 2.0) + math.Pow(y2-y1, 2.0))
 }
+
+// segmentsIntersect is true when the segments a and b, in the plane, have at least one point in common.
+func segmentsIntersect(a1, a2, b1, b2 [2]float64) bool {
+	var orientation = func(p, q, r [2]float64) float64 {
+		return (q[0]-p[0])*(r[1]-p[1]) - (q[1]-p[1])*(r[0]-p[0])
+	}
+
+	var onSegment = func(p, q, r [2]float64) bool {
+		return math.Min(p[0], q[0]) <= r[0] && r[0] <= math.Max(p[0], q[0]) &&
+			math.Min(p[1], q[1]) <= r[1] && r[1] <= math.Max(p[1], q[1])
+	}
+
+	var d1 = orientation(b1, b2, a1)
+	var d2 = orientation(b1, b2, a2)
+	var d3 = orientation(a1, a2, b1)
+	var d4 = orientation(a1, a2, b2)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	return (d1 == 0 && onSegment(b1, b2, a1)) || (d2 == 0 && onSegment(b1, b2, a2)) ||
+		(d3 == 0 && onSegment(a1, a2, b1)) || (d4 == 0 && onSegment(a1, a2, b2))
+}
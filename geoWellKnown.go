@@ -0,0 +1,596 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+// wellKnownStt is a geometry read from or written to WKT and WKB. All types are kept as a list of polygons, made of
+// rings, made of positions: a point is polygons[0][0][0], a line string is polygons[0][0] and a polygon is
+// polygons[0].
+type wellKnownStt struct {
+	typeConst GeoJSonType
+	hasZ      bool
+	srid      uint32
+	polygons  [][][][3]float64
+}
+
+func (el *wellKnownStt) name() string {
+	switch el.typeConst {
+	case GEOJSON_POINT:
+		return "POINT"
+	case GEOJSON_LINE_STRING:
+		return "LINESTRING"
+	case GEOJSON_POLYGON:
+		return "POLYGON"
+	case GEOJSON_MULTI_POLYGON:
+		return "MULTIPOLYGON"
+	}
+
+	return "GEOMETRY"
+}
+
+func (el *wellKnownStt) isEmpty() bool {
+	return len(el.polygons) == 0
+}
+
+func wellKnownFromPoint(point *PointStt) wellKnownStt {
+	return wellKnownStt{
+		typeConst: GEOJSON_POINT,
+		hasZ:      point.Alt != 0,
+		polygons:  [][][][3]float64{{{{point.Loc[0], point.Loc[1], point.Alt}}}},
+	}
+}
+
+func wellKnownFromWay(way *WayStt) (wellKnownStt, error) {
+	var ret = wellKnownStt{
+		typeConst: GEOJSON_LINE_STRING,
+		hasZ:      len(way.Loc) != 0 && len(way.Alt) == len(way.Loc),
+	}
+
+	if len(way.Loc) == 0 {
+		return ret, nil
+	}
+
+	if len(way.Loc) < 2 {
+		return ret, fmt.Errorf("way %v: a line string must have at least two points", way.Id)
+	}
+
+	var line = make([][3]float64, len(way.Loc))
+	for k, loc := range way.Loc {
+		line[k] = [3]float64{loc[0], loc[1], 0}
+		if ret.hasZ {
+			line[k][2] = way.Alt[k]
+		}
+	}
+	ret.polygons = [][][][3]float64{{line}}
+
+	return ret, nil
+}
+
+// wellKnownRings returns the outer ring and the holes of the polygon, closed.
+func wellKnownRings(polygon *PolygonStt) ([][][3]float64, bool, error) {
+	var rings = make([][][3]float64, 0, 1+len(polygon.Inner))
+	var hasZ = false
+
+	for k, pointsList := range append([][]PointStt{polygon.PointsList}, polygonInnerPoints(polygon)...) {
+		var ring = make([][3]float64, 0, len(pointsList)+1)
+		for _, point := range pointsList {
+			ring = append(ring, [3]float64{point.Loc[0], point.Loc[1], point.Alt})
+			hasZ = hasZ || point.Alt != 0
+		}
+
+		if len(ring) != 0 && (ring[0][0] != ring[len(ring)-1][0] || ring[0][1] != ring[len(ring)-1][1]) {
+			ring = append(ring, ring[0])
+		}
+
+		if len(ring) < 4 {
+			return nil, false, fmt.Errorf("polygon %v: ring %v must have at least three distinct points", polygon.Id, k)
+		}
+
+		rings = append(rings, ring)
+	}
+
+	return rings, hasZ, nil
+}
+
+func polygonInnerPoints(polygon *PolygonStt) [][]PointStt {
+	var ret = make([][]PointStt, len(polygon.Inner))
+	for k := range polygon.Inner {
+		ret[k] = polygon.Inner[k].PointsList
+	}
+
+	return ret
+}
+
+func wellKnownFromPolygon(polygon *PolygonStt) (wellKnownStt, error) {
+	var ret = wellKnownStt{typeConst: GEOJSON_POLYGON}
+
+	if len(polygon.PointsList) == 0 {
+		return ret, nil
+	}
+
+	rings, hasZ, err := wellKnownRings(polygon)
+	if err != nil {
+		return ret, err
+	}
+
+	ret.hasZ = hasZ
+	ret.polygons = [][][][3]float64{rings}
+
+	return ret, nil
+}
+
+func wellKnownFromPolygonList(polygonList *PolygonListStt) (wellKnownStt, error) {
+	var ret = wellKnownStt{typeConst: GEOJSON_MULTI_POLYGON}
+
+	for k := range polygonList.List {
+		rings, hasZ, err := wellKnownRings(&polygonList.List[k])
+		if err != nil {
+			return ret, err
+		}
+
+		ret.hasZ = ret.hasZ || hasZ
+		ret.polygons = append(ret.polygons, rings)
+	}
+
+	return ret, nil
+}
+
+// wellKnownPoint converts a position read from WKT or WKB into a point, in degrees.
+func wellKnownPoint(position [3]float64, hasZ bool) (PointStt, error) {
+	var point = PointStt{}
+
+	if position[0] < -180 || position[0] > 180 || position[1] < -90 || position[1] > 90 {
+		return point, fmt.Errorf("longitude must be between -180 and 180 and latitude between -90 and 90, found %v", position[:2])
+	}
+
+	err := point.SetLngLatDegrees(position[0], position[1])
+	if err != nil {
+		return point, err
+	}
+
+	if hasZ {
+		point.Alt = position[2]
+	}
+
+	return point, nil
+}
+
+// wellKnownPolygon converts the rings read from WKT or WKB into a polygon with its holes in Inner.
+func wellKnownPolygon(id int64, rings [][][3]float64, hasZ bool) (PolygonStt, error) {
+	var polygon = PolygonStt{Id: id}
+
+	for key, positionList := range rings {
+		var ring = PolygonStt{Id: id}
+
+		if len(positionList) < 4 {
+			return polygon, fmt.Errorf("ring %v: a linear ring must have at least four positions", key)
+		}
+
+		var first = positionList[0]
+		var last = positionList[len(positionList)-1]
+		if first[0] != last[0] || first[1] != last[1] {
+			return polygon, fmt.Errorf("ring %v: the first and the last positions of a linear ring must be the same", key)
+		}
+
+		for _, position := range positionList {
+			point, err := wellKnownPoint(position, hasZ)
+			if err != nil {
+				return polygon, fmt.Errorf("ring %v: %v", key, err)
+			}
+			ring.PointsList = append(ring.PointsList, point)
+		}
+
+		if key == 0 {
+			polygon = ring
+		} else {
+			polygon.Inner = append(polygon.Inner, ring)
+		}
+	}
+
+	return polygon, nil
+}
+
+func (el *wellKnownStt) expect(typeConst ...GeoJSonType) error {
+	for _, accepted := range typeConst {
+		if el.typeConst == accepted {
+			return nil
+		}
+	}
+
+	var expected = wellKnownStt{typeConst: typeConst[0]}
+	return fmt.Errorf("expected %v, found %v", expected.name(), el.name())
+}
+
+func (el *PointStt) setWellKnown(geometry wellKnownStt) error {
+	err := geometry.expect(GEOJSON_POINT)
+	if err != nil {
+		return err
+	}
+
+	if geometry.isEmpty() {
+		return fmt.Errorf("empty point")
+	}
+
+	point, err := wellKnownPoint(geometry.polygons[0][0][0], geometry.hasZ)
+	if err != nil {
+		return err
+	}
+
+	el.Loc = point.Loc
+	el.Rad = point.Rad
+	el.Alt = point.Alt
+
+	return nil
+}
+
+func (el *WayStt) setWellKnown(geometry wellKnownStt) error {
+	err := geometry.expect(GEOJSON_LINE_STRING)
+	if err != nil {
+		return err
+	}
+
+	el.Loc = nil
+	el.Rad = nil
+	el.Alt = nil
+	el.IdNode = nil
+
+	if geometry.isEmpty() {
+		return nil
+	}
+
+	var positionList = geometry.polygons[0][0]
+	if len(positionList) < 2 {
+		return fmt.Errorf("a line string must have at least two positions")
+	}
+
+	for key, position := range positionList {
+		point, err := wellKnownPoint(position, geometry.hasZ)
+		if err != nil {
+			return fmt.Errorf("position %v: %v", key, err)
+		}
+
+		el.Loc = append(el.Loc, point.Loc)
+		el.Rad = append(el.Rad, point.Rad)
+		if geometry.hasZ {
+			el.Alt = append(el.Alt, point.Alt)
+		}
+	}
+
+	return el.Init()
+}
+
+func (el *PolygonStt) setWellKnown(geometry wellKnownStt) error {
+	err := geometry.expect(GEOJSON_POLYGON)
+	if err != nil {
+		return err
+	}
+
+	el.PointsList = nil
+	el.Inner = nil
+	el.tmp = nil
+
+	if geometry.isEmpty() {
+		return nil
+	}
+
+	polygon, err := wellKnownPolygon(el.Id, geometry.polygons[0], geometry.hasZ)
+	if err != nil {
+		return err
+	}
+
+	el.PointsList = polygon.PointsList
+	el.Inner = polygon.Inner
+
+	return el.Init()
+}
+
+func (el *PolygonListStt) setWellKnown(geometry wellKnownStt) error {
+	err := geometry.expect(GEOJSON_MULTI_POLYGON, GEOJSON_POLYGON)
+	if err != nil {
+		return err
+	}
+
+	el.List = nil
+	el.IdPolygon = nil
+	el.idPolygonUnique = nil
+
+	for key, rings := range geometry.polygons {
+		polygon, err := wellKnownPolygon(el.Id, rings, geometry.hasZ)
+		if err == nil {
+			err = polygon.Init()
+		}
+		if err != nil {
+			return fmt.Errorf("polygon %v: %v", key, err)
+		}
+
+		el.AddPolygon(&polygon)
+	}
+
+	el.Initialize()
+
+	return nil
+}
+
+// English: Writes the point as WKT, as "POINT (-46.63 -23.55)", or "POINT Z (-46.63 -23.55 760)" when it has
+// altitude.
+//
+// Português: Escreve o ponto como WKT, como "POINT (-46.63 -23.55)", ou "POINT Z (-46.63 -23.55 760)" quando tem
+// altitude.
+func (el *PointStt) MarshalWKT() (string, error) {
+	var geometry = wellKnownFromPoint(el)
+	return geometry.marshalWKT(), nil
+}
+
+// English: Reads the point from WKT or EWKT, as "SRID=4326;POINT(-46.63 -23.55)". The SRID is ignored.
+//
+// Português: Lê o ponto a partir de WKT ou EWKT, como "SRID=4326;POINT(-46.63 -23.55)". O SRID é ignorado.
+func (el *PointStt) UnmarshalWKT(text string) error {
+	geometry, err := parseWKT(text)
+	if err != nil {
+		return err
+	}
+
+	return wellKnownError("wkt", el.setWellKnown(geometry))
+}
+
+// English: Writes the point as ISO WKB, little endian.
+//
+// Português: Escreve o ponto como WKB ISO, little endian.
+func (el *PointStt) MarshalWKB() ([]byte, error) {
+	var geometry = wellKnownFromPoint(el)
+	return geometry.marshalWKB(false), nil
+}
+
+// English: Writes the point as PostGIS EWKB, with the SRID when it is not zero.
+//
+// Português: Escreve o ponto como EWKB do PostGIS, com o SRID quando não é zero.
+func (el *PointStt) MarshalEWKB(srid uint32) ([]byte, error) {
+	var geometry = wellKnownFromPoint(el)
+	geometry.srid = srid
+	return geometry.marshalWKB(true), nil
+}
+
+// English: Reads the point from WKB or EWKB. Hexadecimal text, as returned by PostGIS, must be decoded first.
+//
+// Português: Lê o ponto a partir de WKB ou EWKB. Texto hexadecimal, como retornado pelo PostGIS, deve ser decodificado
+// antes.
+func (el *PointStt) UnmarshalWKB(data []byte) error {
+	_, err := el.UnmarshalEWKB(data)
+	return err
+}
+
+// English: Reads the point from WKB or EWKB and returns the SRID, or zero when the data has none.
+//
+// Português: Lê o ponto a partir de WKB ou EWKB e devolve o SRID, ou zero quando os dados não o têm.
+func (el *PointStt) UnmarshalEWKB(data []byte) (uint32, error) {
+	geometry, err := parseWKB(data)
+	if err != nil {
+		return 0, err
+	}
+
+	return geometry.srid, wellKnownError("wkb", el.setWellKnown(geometry))
+}
+
+// English: Writes the way as a WKT LINESTRING, with Z when Alt has one altitude for each point.
+//
+// Português: Escreve o way como um LINESTRING WKT, com Z quando Alt tem uma altitude para cada ponto.
+func (el *WayStt) MarshalWKT() (string, error) {
+	geometry, err := wellKnownFromWay(el)
+	if err != nil {
+		return "", err
+	}
+
+	return geometry.marshalWKT(), nil
+}
+
+// English: Reads the way from a WKT or EWKT LINESTRING and calls Init(). The SRID is ignored.
+//
+// Português: Lê o way a partir de um LINESTRING WKT ou EWKT e chama Init(). O SRID é ignorado.
+func (el *WayStt) UnmarshalWKT(text string) error {
+	geometry, err := parseWKT(text)
+	if err != nil {
+		return err
+	}
+
+	return wellKnownError("wkt", el.setWellKnown(geometry))
+}
+
+// English: Writes the way as an ISO WKB LINESTRING, little endian.
+//
+// Português: Escreve o way como um LINESTRING WKB ISO, little endian.
+func (el *WayStt) MarshalWKB() ([]byte, error) {
+	geometry, err := wellKnownFromWay(el)
+	if err != nil {
+		return nil, err
+	}
+
+	return geometry.marshalWKB(false), nil
+}
+
+// English: Writes the way as a PostGIS EWKB LINESTRING, with the SRID when it is not zero.
+//
+// Português: Escreve o way como um LINESTRING EWKB do PostGIS, com o SRID quando não é zero.
+func (el *WayStt) MarshalEWKB(srid uint32) ([]byte, error) {
+	geometry, err := wellKnownFromWay(el)
+	if err != nil {
+		return nil, err
+	}
+
+	geometry.srid = srid
+	return geometry.marshalWKB(true), nil
+}
+
+// English: Reads the way from a WKB or EWKB LINESTRING and calls Init().
+//
+// Português: Lê o way a partir de um LINESTRING WKB ou EWKB e chama Init().
+func (el *WayStt) UnmarshalWKB(data []byte) error {
+	_, err := el.UnmarshalEWKB(data)
+	return err
+}
+
+// English: Reads the way from a WKB or EWKB LINESTRING, calls Init() and returns the SRID, or zero when the data has
+// none.
+//
+// Português: Lê o way a partir de um LINESTRING WKB ou EWKB, chama Init() e devolve o SRID, ou zero quando os dados
+// não o têm.
+func (el *WayStt) UnmarshalEWKB(data []byte) (uint32, error) {
+	geometry, err := parseWKB(data)
+	if err != nil {
+		return 0, err
+	}
+
+	return geometry.srid, wellKnownError("wkb", el.setWellKnown(geometry))
+}
+
+// English: Writes the polygon as a WKT POLYGON, the outer ring followed by the Inner rings. Rings are closed on
+// output.
+//
+// Português: Escreve o polígono como um POLYGON WKT, o anel externo seguido dos anéis Inner. Os anéis são fechados na
+// saída.
+func (el *PolygonStt) MarshalWKT() (string, error) {
+	geometry, err := wellKnownFromPolygon(el)
+	if err != nil {
+		return "", err
+	}
+
+	return geometry.marshalWKT(), nil
+}
+
+// English: Reads the polygon from a WKT or EWKT POLYGON, the rings after the first one into Inner, and calls Init().
+//
+// Português: Lê o polígono a partir de um POLYGON WKT ou EWKT, os anéis depois do primeiro em Inner, e chama Init().
+func (el *PolygonStt) UnmarshalWKT(text string) error {
+	geometry, err := parseWKT(text)
+	if err != nil {
+		return err
+	}
+
+	return wellKnownError("wkt", el.setWellKnown(geometry))
+}
+
+// English: Writes the polygon as an ISO WKB POLYGON, little endian.
+//
+// Português: Escreve o polígono como um POLYGON WKB ISO, little endian.
+func (el *PolygonStt) MarshalWKB() ([]byte, error) {
+	geometry, err := wellKnownFromPolygon(el)
+	if err != nil {
+		return nil, err
+	}
+
+	return geometry.marshalWKB(false), nil
+}
+
+// English: Writes the polygon as a PostGIS EWKB POLYGON, with the SRID when it is not zero.
+//
+// Português: Escreve o polígono como um POLYGON EWKB do PostGIS, com o SRID quando não é zero.
+func (el *PolygonStt) MarshalEWKB(srid uint32) ([]byte, error) {
+	geometry, err := wellKnownFromPolygon(el)
+	if err != nil {
+		return nil, err
+	}
+
+	geometry.srid = srid
+	return geometry.marshalWKB(true), nil
+}
+
+// English: Reads the polygon from a WKB or EWKB POLYGON and calls Init().
+//
+// Português: Lê o polígono a partir de um POLYGON WKB ou EWKB e chama Init().
+func (el *PolygonStt) UnmarshalWKB(data []byte) error {
+	_, err := el.UnmarshalEWKB(data)
+	return err
+}
+
+// English: Reads the polygon from a WKB or EWKB POLYGON, calls Init() and returns the SRID, or zero when the data has
+// none.
+//
+// Português: Lê o polígono a partir de um POLYGON WKB ou EWKB, chama Init() e devolve o SRID, ou zero quando os dados
+// não o têm.
+func (el *PolygonStt) UnmarshalEWKB(data []byte) (uint32, error) {
+	geometry, err := parseWKB(data)
+	if err != nil {
+		return 0, err
+	}
+
+	return geometry.srid, wellKnownError("wkb", el.setWellKnown(geometry))
+}
+
+// English: Writes the list as a WKT MULTIPOLYGON, one polygon for each element of List.
+//
+// Português: Escreve a lista como um MULTIPOLYGON WKT, um polígono para cada elemento de List.
+func (el *PolygonListStt) MarshalWKT() (string, error) {
+	geometry, err := wellKnownFromPolygonList(el)
+	if err != nil {
+		return "", err
+	}
+
+	return geometry.marshalWKT(), nil
+}
+
+// English: Reads the list from a WKT or EWKT MULTIPOLYGON, or POLYGON, initializing each polygon.
+//
+// Português: Lê a lista a partir de um MULTIPOLYGON, ou POLYGON, WKT ou EWKT, inicializando cada polígono.
+func (el *PolygonListStt) UnmarshalWKT(text string) error {
+	geometry, err := parseWKT(text)
+	if err != nil {
+		return err
+	}
+
+	return wellKnownError("wkt", el.setWellKnown(geometry))
+}
+
+// English: Writes the list as an ISO WKB MULTIPOLYGON, little endian.
+//
+// Português: Escreve a lista como um MULTIPOLYGON WKB ISO, little endian.
+func (el *PolygonListStt) MarshalWKB() ([]byte, error) {
+	geometry, err := wellKnownFromPolygonList(el)
+	if err != nil {
+		return nil, err
+	}
+
+	return geometry.marshalWKB(false), nil
+}
+
+// English: Writes the list as a PostGIS EWKB MULTIPOLYGON, with the SRID when it is not zero.
+//
+// Português: Escreve a lista como um MULTIPOLYGON EWKB do PostGIS, com o SRID quando não é zero.
+func (el *PolygonListStt) MarshalEWKB(srid uint32) ([]byte, error) {
+	geometry, err := wellKnownFromPolygonList(el)
+	if err != nil {
+		return nil, err
+	}
+
+	geometry.srid = srid
+	return geometry.marshalWKB(true), nil
+}
+
+// English: Reads the list from a WKB or EWKB MULTIPOLYGON, or POLYGON, initializing each polygon.
+//
+// Português: Lê a lista a partir de um MULTIPOLYGON, ou POLYGON, WKB ou EWKB, inicializando cada polígono.
+func (el *PolygonListStt) UnmarshalWKB(data []byte) error {
+	_, err := el.UnmarshalEWKB(data)
+	return err
+}
+
+// English: Reads the list from a WKB or EWKB MULTIPOLYGON, or POLYGON, initializing each polygon, and returns the
+// SRID, or zero when the data has none.
+//
+// Português: Lê a lista a partir de um MULTIPOLYGON, ou POLYGON, WKB ou EWKB, inicializando cada polígono, e devolve
+// o SRID, ou zero quando os dados não o têm.
+func (el *PolygonListStt) UnmarshalEWKB(data []byte) (uint32, error) {
+	geometry, err := parseWKB(data)
+	if err != nil {
+		return 0, err
+	}
+
+	return geometry.srid, wellKnownError("wkb", el.setWellKnown(geometry))
+}
+
+func wellKnownError(format string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%v: %v", format, err)
+}
@@ -0,0 +1,119 @@
+package iotmaker_geo_osm
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+)
+
+func ExamplePointStt_MarshalWKT() {
+	var point = PointStt{}
+	point.SetLngLatDegrees(-46.6333, -23.5505)
+	point.Alt = 760
+
+	text, err := point.MarshalWKT()
+	fmt.Printf("error: %v\n", err)
+	fmt.Println(text)
+
+	var decoded = PointStt{}
+	err = decoded.UnmarshalWKT(text)
+	fmt.Printf("error: %v\n", err)
+	fmt.Printf("same loc: %v, alt: %v\n", decoded.Loc == point.Loc, decoded.Alt)
+
+	err = decoded.UnmarshalWKT("SRID=4326;POINTM(10 20 5)")
+	fmt.Printf("error: %v, loc: %v, alt: %v\n", err, decoded.Loc, decoded.Alt)
+
+	err = decoded.UnmarshalWKT("LINESTRING (0 0, 1 1)")
+	fmt.Printf("error: %v\n", err)
+
+	// Output:
+	// error: <nil>
+	// POINT Z (-46.6333 -23.5505 760)
+	// error: <nil>
+	// same loc: true, alt: 760
+	// error: <nil>, loc: [10 20], alt: 0
+	// error: wkt: expected POINT, found LINESTRING
+}
+
+func ExampleWayStt_MarshalEWKB() {
+	var way = WayStt{}
+	way.AddLngLatDegrees(-46.6333, -23.5505)
+	way.AddLngLatDegrees(-43.1729, -22.9068)
+
+	data, err := way.MarshalEWKB(4326)
+	fmt.Printf("error: %v\n", err)
+	fmt.Println(hex.EncodeToString(data[:9]))
+
+	var decoded = WayStt{}
+	srid, err := decoded.UnmarshalEWKB(data)
+	fmt.Printf("error: %v, srid: %v\n", err, srid)
+	fmt.Printf("same loc: %v\n", reflect.DeepEqual(decoded.Loc, way.Loc))
+
+	data, _ = way.MarshalWKB()
+	err = decoded.UnmarshalWKB(data)
+	fmt.Printf("error: %v, same loc: %v\n", err, reflect.DeepEqual(decoded.Loc, way.Loc))
+
+	// Output:
+	// error: <nil>
+	// 0102000020e6100000
+	// error: <nil>, srid: 4326
+	// same loc: true
+	// error: <nil>, same loc: true
+}
+
+func ExamplePolygonStt_UnmarshalWKT() {
+	var polygon = PolygonStt{}
+	err := polygon.UnmarshalWKT("POLYGON ((0 0, 4 0, 4 4, 0 4, 0 0), (1 1, 1 2, 2 2, 2 1, 1 1))")
+	fmt.Printf("error: %v\n", err)
+	fmt.Printf("points: %v, holes: %v\n", len(polygon.PointsList), len(polygon.Inner))
+
+	text, err := polygon.MarshalWKT()
+	fmt.Printf("error: %v\n", err)
+	fmt.Println(text)
+
+	data, _ := polygon.MarshalWKB()
+	var decoded = PolygonStt{}
+	err = decoded.UnmarshalWKB(data)
+	fmt.Printf("error: %v\n", err)
+	for k := range decoded.PointsList {
+		if decoded.PointsList[k].Loc != polygon.PointsList[k].Loc {
+			fmt.Printf("point %v: %v != %v\n", k, decoded.PointsList[k].Loc, polygon.PointsList[k].Loc)
+		}
+	}
+	fmt.Printf("hole: %v\n", decoded.Inner[0].PointsList[2].Loc)
+
+	err = polygon.UnmarshalWKT("POLYGON ((0 0, 4 0, 4 4, 0 4))")
+	fmt.Printf("error: %v\n", err)
+
+	// Output:
+	// error: <nil>
+	// points: 5, holes: 1
+	// error: <nil>
+	// POLYGON ((0 0, 4 0, 4 4, 0 4, 0 0), (1 1, 1 2, 2 2, 2 1, 1 1))
+	// error: <nil>
+	// hole: [2 2]
+	// error: wkt: ring 0: the first and the last positions of a linear ring must be the same
+}
+
+func ExamplePolygonListStt_MarshalWKT() {
+	var list = PolygonListStt{}
+	err := list.UnmarshalWKT("MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((10 10, 12 10, 12 12, 10 10), (10.5 10.2, 11.5 10.2, 11.5 11.2, 10.5 10.2)))")
+	fmt.Printf("error: %v, polygons: %v\n", err, len(list.List))
+
+	text, err := list.MarshalWKT()
+	fmt.Printf("error: %v\n", err)
+	fmt.Println(text)
+
+	data, _ := list.MarshalEWKB(4674)
+	var decoded = PolygonListStt{}
+	srid, err := decoded.UnmarshalEWKB(data)
+	fmt.Printf("error: %v, srid: %v, polygons: %v\n", err, srid, len(decoded.List))
+	fmt.Printf("hole: %v\n", decoded.List[1].Inner[0].PointsList[1].Loc)
+
+	// Output:
+	// error: <nil>, polygons: 2
+	// error: <nil>
+	// MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((10 10, 12 10, 12 12, 10 10), (10.5 10.2, 11.5 10.2, 11.5 11.2, 10.5 10.2)))
+	// error: <nil>, srid: 4674, polygons: 2
+	// hole: [11.5 10.2]
+}
@@ -0,0 +1,369 @@
+package iotmaker_geo_osm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+const (
+	wkbPoint        uint32 = 1
+	wkbLineString   uint32 = 2
+	wkbPolygon      uint32 = 3
+	wkbMultiPolygon uint32 = 6
+
+	// flags of the type in PostGIS EWKB
+	ewkbZ    uint32 = 0x80000000
+	ewkbM    uint32 = 0x40000000
+	ewkbSRID uint32 = 0x20000000
+)
+
+var wkbTypes = map[uint32]GeoJSonType{
+	wkbPoint:        GEOJSON_POINT,
+	wkbLineString:   GEOJSON_LINE_STRING,
+	wkbPolygon:      GEOJSON_POLYGON,
+	wkbMultiPolygon: GEOJSON_MULTI_POLYGON,
+}
+
+func (el *wellKnownStt) wkbType() uint32 {
+	for code, typeConst := range wkbTypes {
+		if typeConst == el.typeConst {
+			return code
+		}
+	}
+
+	return 0
+}
+
+// marshalWKB writes little endian ISO WKB or, when extended is true, PostGIS EWKB.
+func (el *wellKnownStt) marshalWKB(extended bool) []byte {
+	var buffer = &bytes.Buffer{}
+
+	var header = func(code uint32, srid uint32) {
+		if el.hasZ && extended {
+			code |= ewkbZ
+		} else if el.hasZ {
+			code += 1000
+		}
+
+		if srid != 0 {
+			code |= ewkbSRID
+		}
+
+		buffer.WriteByte(1)
+		binary.Write(buffer, binary.LittleEndian, code)
+		if srid != 0 {
+			binary.Write(buffer, binary.LittleEndian, srid)
+		}
+	}
+
+	var srid uint32 = 0
+	if extended {
+		srid = el.srid
+	}
+	header(el.wkbType(), srid)
+
+	if el.typeConst == GEOJSON_POINT {
+		var position = [3]float64{math.NaN(), math.NaN(), math.NaN()}
+		if el.isEmpty() == false {
+			position = el.polygons[0][0][0]
+		}
+		el.writeWKBPosition(buffer, position)
+		return buffer.Bytes()
+	}
+
+	if el.isEmpty() {
+		binary.Write(buffer, binary.LittleEndian, uint32(0))
+		return buffer.Bytes()
+	}
+
+	switch el.typeConst {
+	case GEOJSON_LINE_STRING:
+		el.writeWKBPositionList(buffer, el.polygons[0][0])
+
+	case GEOJSON_POLYGON:
+		el.writeWKBRingList(buffer, el.polygons[0])
+
+	case GEOJSON_MULTI_POLYGON:
+		binary.Write(buffer, binary.LittleEndian, uint32(len(el.polygons)))
+		for _, rings := range el.polygons {
+			header(wkbPolygon, 0)
+			el.writeWKBRingList(buffer, rings)
+		}
+	}
+
+	return buffer.Bytes()
+}
+
+func (el *wellKnownStt) writeWKBPosition(buffer *bytes.Buffer, position [3]float64) {
+	binary.Write(buffer, binary.LittleEndian, position[0])
+	binary.Write(buffer, binary.LittleEndian, position[1])
+	if el.hasZ {
+		binary.Write(buffer, binary.LittleEndian, position[2])
+	}
+}
+
+func (el *wellKnownStt) writeWKBPositionList(buffer *bytes.Buffer, positionList [][3]float64) {
+	binary.Write(buffer, binary.LittleEndian, uint32(len(positionList)))
+	for _, position := range positionList {
+		el.writeWKBPosition(buffer, position)
+	}
+}
+
+func (el *wellKnownStt) writeWKBRingList(buffer *bytes.Buffer, rings [][][3]float64) {
+	binary.Write(buffer, binary.LittleEndian, uint32(len(rings)))
+	for _, ring := range rings {
+		el.writeWKBPositionList(buffer, ring)
+	}
+}
+
+// wkbReaderStt reads WKB and EWKB, in both byte orders.
+type wkbReaderStt struct {
+	data      []byte
+	position  int
+	byteOrder binary.ByteOrder
+
+	// number of values of each position, two to four
+	dimensions int
+	hasZ       bool
+}
+
+func (el *wkbReaderStt) error(message string) error {
+	return fmt.Errorf("wkb: byte %v: %v", el.position, message)
+}
+
+func (el *wkbReaderStt) uint32() (uint32, error) {
+	if len(el.data)-el.position < 4 {
+		return 0, el.error("unexpected end of data")
+	}
+
+	var ret = el.byteOrder.Uint32(el.data[el.position:])
+	el.position += 4
+
+	return ret, nil
+}
+
+func (el *wkbReaderStt) float64() (float64, error) {
+	if len(el.data)-el.position < 8 {
+		return 0, el.error("unexpected end of data")
+	}
+
+	var ret = math.Float64frombits(el.byteOrder.Uint64(el.data[el.position:]))
+	el.position += 8
+
+	return ret, nil
+}
+
+// count reads the number of items of a list and checks that the data has room for them.
+func (el *wkbReaderStt) count(minimalSize int) (int, error) {
+	count, err := el.uint32()
+	if err != nil {
+		return 0, err
+	}
+
+	if int64(count)*int64(minimalSize) > int64(len(el.data)-el.position) {
+		return 0, el.error(fmt.Sprintf("%v items do not fit in the data", count))
+	}
+
+	return int(count), nil
+}
+
+// header reads the byte order and the type of a geometry, and the SRID of EWKB.
+func (el *wkbReaderStt) header() (uint32, uint32, error) {
+	var srid uint32 = 0
+
+	if el.position >= len(el.data) {
+		return 0, 0, el.error("unexpected end of data")
+	}
+
+	switch el.data[el.position] {
+	case 0:
+		el.byteOrder = binary.BigEndian
+	case 1:
+		el.byteOrder = binary.LittleEndian
+	default:
+		return 0, 0, el.error(fmt.Sprintf("unknown byte order %v", el.data[el.position]))
+	}
+	el.position += 1
+
+	code, err := el.uint32()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var hasZ = code&ewkbZ != 0
+	var hasM = code&ewkbM != 0
+
+	if code&ewkbSRID != 0 {
+		srid, err = el.uint32()
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	code &^= ewkbZ | ewkbM | ewkbSRID
+	switch code / 1000 {
+	case 1:
+		hasZ = true
+	case 2:
+		hasM = true
+	case 3:
+		hasZ = true
+		hasM = true
+	}
+	code %= 1000
+
+	var dimensions = 2
+	if hasZ {
+		dimensions += 1
+	}
+	if hasM {
+		dimensions += 1
+	}
+
+	if el.dimensions != 0 && el.dimensions != dimensions {
+		return 0, 0, el.error("all geometries must have the same dimensions")
+	}
+
+	el.dimensions = dimensions
+	el.hasZ = hasZ
+
+	return code, srid, nil
+}
+
+func (el *wkbReaderStt) position3() ([3]float64, error) {
+	var ret [3]float64
+
+	for k := 0; k != el.dimensions; k += 1 {
+		value, err := el.float64()
+		if err != nil {
+			return ret, err
+		}
+
+		// the value after x and y is z, or m when there is no z, which is ignored
+		if k < 2 || (k == 2 && el.hasZ) {
+			ret[k] = value
+		}
+	}
+
+	return ret, nil
+}
+
+func (el *wkbReaderStt) positionList() ([][3]float64, error) {
+	count, err := el.count(8 * el.dimensions)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret = make([][3]float64, count)
+	for k := range ret {
+		ret[k], err = el.position3()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+func (el *wkbReaderStt) ringList() ([][][3]float64, error) {
+	count, err := el.count(4)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret = make([][][3]float64, count)
+	for k := range ret {
+		ret[k], err = el.positionList()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+// parseWKB reads ISO WKB, or PostGIS EWKB, with the types POINT, LINESTRING, POLYGON and MULTIPOLYGON. Z, M and ZM
+// are accepted, and M values are ignored.
+func parseWKB(data []byte) (wellKnownStt, error) {
+	var reader = wkbReaderStt{data: data}
+	var ret = wellKnownStt{}
+
+	code, srid, err := reader.header()
+	if err != nil {
+		return ret, err
+	}
+
+	typeConst, found := wkbTypes[code]
+	if !found {
+		return ret, reader.error(fmt.Sprintf("unknown geometry type %v", code))
+	}
+
+	ret.typeConst = typeConst
+	ret.srid = srid
+	ret.hasZ = reader.hasZ
+
+	switch typeConst {
+	case GEOJSON_POINT:
+		position, err := reader.position3()
+		if err != nil {
+			return ret, err
+		}
+
+		// an empty point is written with NaN coordinates
+		if !math.IsNaN(position[0]) || !math.IsNaN(position[1]) {
+			ret.polygons = [][][][3]float64{{{position}}}
+		}
+
+	case GEOJSON_LINE_STRING:
+		positionList, err := reader.positionList()
+		if err != nil {
+			return ret, err
+		}
+
+		if len(positionList) != 0 {
+			ret.polygons = [][][][3]float64{{positionList}}
+		}
+
+	case GEOJSON_POLYGON:
+		rings, err := reader.ringList()
+		if err != nil {
+			return ret, err
+		}
+
+		if len(rings) != 0 {
+			ret.polygons = [][][][3]float64{rings}
+		}
+
+	case GEOJSON_MULTI_POLYGON:
+		count, err := reader.count(9)
+		if err != nil {
+			return ret, err
+		}
+
+		for k := 0; k != count; k += 1 {
+			code, _, err := reader.header()
+			if err != nil {
+				return ret, err
+			}
+
+			if code != wkbPolygon {
+				return ret, reader.error(fmt.Sprintf("a multipolygon must contain polygons, found type %v", code))
+			}
+
+			rings, err := reader.ringList()
+			if err != nil {
+				return ret, err
+			}
+
+			ret.polygons = append(ret.polygons, rings)
+		}
+	}
+
+	if reader.position != len(data) {
+		return ret, reader.error(fmt.Sprintf("%v bytes left after the geometry", len(data)-reader.position))
+	}
+
+	return ret, nil
+}
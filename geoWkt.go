@@ -0,0 +1,328 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var wellKnownTypes = map[string]GeoJSonType{
+	"POINT":        GEOJSON_POINT,
+	"LINESTRING":   GEOJSON_LINE_STRING,
+	"POLYGON":      GEOJSON_POLYGON,
+	"MULTIPOLYGON": GEOJSON_MULTI_POLYGON,
+}
+
+func (el *wellKnownStt) marshalWKT() string {
+	var buffer strings.Builder
+
+	buffer.WriteString(el.name())
+	if el.hasZ {
+		buffer.WriteString(" Z")
+	}
+
+	if el.isEmpty() {
+		buffer.WriteString(" EMPTY")
+		return buffer.String()
+	}
+
+	buffer.WriteString(" ")
+
+	switch el.typeConst {
+	case GEOJSON_POINT:
+		el.writeWKTPositionList(&buffer, el.polygons[0][0])
+	case GEOJSON_LINE_STRING:
+		el.writeWKTPositionList(&buffer, el.polygons[0][0])
+	case GEOJSON_POLYGON:
+		el.writeWKTRingList(&buffer, el.polygons[0])
+	case GEOJSON_MULTI_POLYGON:
+		buffer.WriteString("(")
+		for k, rings := range el.polygons {
+			if k != 0 {
+				buffer.WriteString(", ")
+			}
+			el.writeWKTRingList(&buffer, rings)
+		}
+		buffer.WriteString(")")
+	}
+
+	return buffer.String()
+}
+
+func (el *wellKnownStt) writeWKTRingList(buffer *strings.Builder, rings [][][3]float64) {
+	buffer.WriteString("(")
+	for k, ring := range rings {
+		if k != 0 {
+			buffer.WriteString(", ")
+		}
+		el.writeWKTPositionList(buffer, ring)
+	}
+	buffer.WriteString(")")
+}
+
+func (el *wellKnownStt) writeWKTPositionList(buffer *strings.Builder, positionList [][3]float64) {
+	buffer.WriteString("(")
+	for k, position := range positionList {
+		if k != 0 {
+			buffer.WriteString(", ")
+		}
+
+		buffer.WriteString(strconv.FormatFloat(position[0], 'f', -1, 64))
+		buffer.WriteString(" ")
+		buffer.WriteString(strconv.FormatFloat(position[1], 'f', -1, 64))
+		if el.hasZ {
+			buffer.WriteString(" ")
+			buffer.WriteString(strconv.FormatFloat(position[2], 'f', -1, 64))
+		}
+	}
+	buffer.WriteString(")")
+}
+
+// wktReaderStt splits WKT into words, numbers and the characters "(", ")", "," and ";".
+type wktReaderStt struct {
+	text     string
+	position int
+
+	// number of values of each position, two to four, or zero before the first position when the type does not tell
+	dimensions int
+	hasZ       bool
+	hasM       bool
+}
+
+func (el *wktReaderStt) next() string {
+	for el.position < len(el.text) && strings.IndexByte(" \t\r\n", el.text[el.position]) != -1 {
+		el.position += 1
+	}
+
+	if el.position == len(el.text) {
+		return ""
+	}
+
+	var start = el.position
+	if strings.IndexByte("(),;", el.text[el.position]) != -1 {
+		el.position += 1
+		return el.text[start:el.position]
+	}
+
+	for el.position < len(el.text) && strings.IndexByte(" \t\r\n(),;", el.text[el.position]) == -1 {
+		el.position += 1
+	}
+
+	return el.text[start:el.position]
+}
+
+func (el *wktReaderStt) peek() string {
+	var position = el.position
+	var token = el.next()
+	el.position = position
+
+	return token
+}
+
+func (el *wktReaderStt) expect(token string) error {
+	var start = el.position
+	var found = el.next()
+	if found != token {
+		return el.error(start, fmt.Sprintf("expected '%v', found '%v'", token, found))
+	}
+
+	return nil
+}
+
+func (el *wktReaderStt) error(position int, message string) error {
+	return fmt.Errorf("wkt: position %v: %v", position, message)
+}
+
+// parseWKT reads WKT, or PostGIS EWKT, with the types POINT, LINESTRING, POLYGON and MULTIPOLYGON. Z, M and ZM are
+// accepted, and M values are ignored.
+func parseWKT(text string) (wellKnownStt, error) {
+	var reader = wktReaderStt{text: text}
+	var ret = wellKnownStt{}
+	var err error
+
+	var token = reader.next()
+	if strings.HasPrefix(strings.ToUpper(token), "SRID=") {
+		srid, err := strconv.ParseUint(token[len("SRID="):], 10, 32)
+		if err != nil {
+			return ret, reader.error(0, fmt.Sprintf("malformed SRID '%v'", token))
+		}
+		ret.srid = uint32(srid)
+
+		err = reader.expect(";")
+		if err != nil {
+			return ret, err
+		}
+
+		token = reader.next()
+	}
+
+	// PostGIS writes the dimensions together with the type, as POINTM
+	var name = strings.ToUpper(token)
+	var dimensions = ""
+	for _, suffix := range []string{"ZM", "Z", "M"} {
+		if _, found := wellKnownTypes[name]; !found && strings.HasSuffix(name, suffix) {
+			name = strings.TrimSuffix(name, suffix)
+			dimensions = suffix
+		}
+	}
+
+	typeConst, found := wellKnownTypes[name]
+	if !found {
+		return ret, reader.error(0, fmt.Sprintf("unknown geometry type '%v'", token))
+	}
+	ret.typeConst = typeConst
+
+	if dimensions == "" {
+		switch strings.ToUpper(reader.peek()) {
+		case "Z", "M", "ZM":
+			dimensions = strings.ToUpper(reader.next())
+		}
+	}
+
+	if dimensions != "" {
+		reader.hasZ = strings.Contains(dimensions, "Z")
+		reader.hasM = strings.Contains(dimensions, "M")
+		reader.dimensions = len(dimensions) + 2
+	}
+
+	if strings.ToUpper(reader.peek()) == "EMPTY" {
+		reader.next()
+	} else {
+		switch typeConst {
+		case GEOJSON_POINT, GEOJSON_LINE_STRING:
+			var positionList [][3]float64
+			positionList, err = reader.positionList()
+			ret.polygons = [][][][3]float64{{positionList}}
+		case GEOJSON_POLYGON:
+			var rings [][][3]float64
+			rings, err = reader.ringList()
+			ret.polygons = [][][][3]float64{rings}
+		case GEOJSON_MULTI_POLYGON:
+			ret.polygons, err = reader.polygonList()
+		}
+
+		if err != nil {
+			return ret, err
+		}
+
+		if typeConst == GEOJSON_POINT && len(ret.polygons[0][0]) != 1 {
+			return ret, reader.error(0, "a point must have one position")
+		}
+	}
+
+	var start = reader.position
+	if token = reader.next(); token != "" {
+		return ret, reader.error(start, fmt.Sprintf("unexpected '%v' after the geometry", token))
+	}
+
+	ret.hasZ = reader.hasZ
+
+	return ret, nil
+}
+
+func (el *wktReaderStt) position3() ([3]float64, error) {
+	var ret [3]float64
+	var values = make([]float64, 0, 4)
+	var start = el.position
+
+	for {
+		var token = el.peek()
+		if token == "," || token == ")" || token == "" {
+			break
+		}
+
+		var tokenStart = el.position
+		value, err := strconv.ParseFloat(el.next(), 64)
+		if err != nil {
+			return ret, el.error(tokenStart, fmt.Sprintf("malformed number '%v'", token))
+		}
+		values = append(values, value)
+	}
+
+	if el.dimensions == 0 {
+		if len(values) < 2 || len(values) > 4 {
+			return ret, el.error(start, fmt.Sprintf("a position must have two to four values, found %v", len(values)))
+		}
+
+		el.dimensions = len(values)
+		el.hasZ = len(values) > 2
+		el.hasM = len(values) == 4
+	}
+
+	if len(values) != el.dimensions {
+		return ret, el.error(start, fmt.Sprintf("a position must have %v values, found %v", el.dimensions, len(values)))
+	}
+
+	ret[0] = values[0]
+	ret[1] = values[1]
+	if el.hasZ {
+		ret[2] = values[2]
+	}
+
+	return ret, nil
+}
+
+// list reads "(" item {"," item} ")", or EMPTY.
+func (el *wktReaderStt) list(item func() error) error {
+	if strings.ToUpper(el.peek()) == "EMPTY" {
+		el.next()
+		return nil
+	}
+
+	err := el.expect("(")
+	if err != nil {
+		return err
+	}
+
+	for {
+		err = item()
+		if err != nil {
+			return err
+		}
+
+		var start = el.position
+		switch token := el.next(); token {
+		case ",":
+		case ")":
+			return nil
+		default:
+			return el.error(start, fmt.Sprintf("expected ',' or ')', found '%v'", token))
+		}
+	}
+}
+
+func (el *wktReaderStt) positionList() ([][3]float64, error) {
+	var ret = make([][3]float64, 0)
+
+	err := el.list(func() error {
+		position, err := el.position3()
+		ret = append(ret, position)
+		return err
+	})
+
+	return ret, err
+}
+
+func (el *wktReaderStt) ringList() ([][][3]float64, error) {
+	var ret = make([][][3]float64, 0)
+
+	err := el.list(func() error {
+		ring, err := el.positionList()
+		ret = append(ret, ring)
+		return err
+	})
+
+	return ret, err
+}
+
+func (el *wktReaderStt) polygonList() ([][][][3]float64, error) {
+	var ret = make([][][][3]float64, 0)
+
+	err := el.list(func() error {
+		rings, err := el.ringList()
+		ret = append(ret, rings)
+		return err
+	})
+
+	return ret, err
+}
@@ -0,0 +1,171 @@
+package iotmaker_geo_osm
+
+import (
+	"math"
+)
+
+// geodesicTransit is +1 or -1 when the edge crosses the meridian of 0° to the east or to the west, counting how many
+// times the ring goes around a pole.
+func geodesicTransit(lon1, lon2 float64) int {
+	lon12, _ := geodesicAngDiff(lon1, lon2)
+	lon1 = geodesicAngNormalize(lon1)
+	lon2 = geodesicAngNormalize(lon2)
+
+	if lon12 > 0 && ((lon1 < 0 && lon2 >= 0) || (lon1 > 0 && lon2 == 0)) {
+		return 1
+	}
+
+	if lon12 < 0 && lon1 >= 0 && lon2 < 0 {
+		return -1
+	}
+
+	return 0
+}
+
+// ringArea is the area, in square meters, positive for counterclockwise rings, and the perimeter, in meters, of the
+// ring of geodesics. The ring may be closed or not.
+func (el *geodesicStt) ringArea(pointsList []PointStt) (float64, float64) {
+	var length = len(pointsList)
+	if length > 1 && pointsList[0].Loc == pointsList[length-1].Loc {
+		length -= 1
+	}
+
+	if length < 3 {
+		return 0, 0
+	}
+
+	var area, perimeter = 0.0, 0.0
+	var crossings = 0
+
+	for i := 0; i != length; i += 1 {
+		var pointA = pointsList[i].Loc
+		var pointB = pointsList[(i+1)%length].Loc
+
+		s12, _, _, s12Area := el.inverseArea(pointA[1], pointA[0], pointB[1], pointB[0], true)
+		perimeter += s12
+		area += s12Area
+		crossings += geodesicTransit(pointA[0], pointB[0])
+	}
+
+	// the area of the whole ellipsoid
+	var area0 = 4 * math.Pi * el.c2
+
+	area = math.Remainder(area, area0)
+	if crossings%2 != 0 {
+		if area < 0 {
+			area += area0 / 2
+		} else {
+			area -= area0 / 2
+		}
+	}
+
+	area = -area
+	if area > area0/2 {
+		area -= area0
+	} else if area <= -area0/2 {
+		area += area0
+	}
+
+	return area + 0, perimeter
+}
+
+// polygonArea is the area of the polygon, with the holes subtracted and the sign of the outer ring, and the perimeter
+// of all the rings.
+func polygonArea(solver *geodesicStt, polygon *PolygonStt) (AreaStt, DistanceStt) {
+	var area AreaStt
+	var perimeter DistanceStt
+
+	outer, length := solver.ringArea(polygon.PointsList)
+	var holes = 0.0
+
+	for k := range polygon.Inner {
+		innerArea, innerLength := solver.ringArea(polygon.Inner[k].PointsList)
+		holes += math.Abs(innerArea)
+		length += innerLength
+	}
+
+	if outer < 0 {
+		area.SetSquareMeters(outer + holes)
+	} else {
+		area.SetSquareMeters(outer - holes)
+	}
+	perimeter.SetMeters(length)
+
+	return area, perimeter
+}
+
+// sphereGeodesic is the solver on the sphere with the same area of the ellipsoid of the selected datum.
+func sphereGeodesic() *geodesicStt {
+	var authalic = EllipsoidStt{Major: math.Sqrt(GetDatum().Ellipsoid.geodesic().c2)}
+
+	return authalic.geodesic()
+}
+
+// English: Area, in square meters, and perimeter of the polygon, with the edges as geodesics on the ellipsoid of the
+// datum selected by SetDatum(), with the algorithm of Karney. The area of the holes is subtracted and the area is
+// positive when the outer ring is counterclockwise and negative when it is clockwise. The perimeter includes the
+// holes. Polygons around a pole are supported.
+//
+// Português: Área, em metros quadrados, e perímetro do polígono, com as arestas como geodésicas no elipsoide do datum
+// selecionado por SetDatum(), com o algoritmo de Karney. A área dos buracos é subtraída e a área é positiva quando o
+// anel externo é anti-horário e negativa quando é horário. O perímetro inclui os buracos. Polígonos em volta de um
+// polo são suportados.
+func (el *PolygonStt) GeodesicAreaKarney() (AreaStt, DistanceStt) {
+	return polygonArea(GetDatum().Ellipsoid.geodesic(), el)
+}
+
+// English: Same as PolygonStt.GeodesicAreaKarney(), on the ellipsoid.
+//
+// Português: O mesmo que PolygonStt.GeodesicAreaKarney(), no elipsoide.
+func (el EllipsoidStt) PolygonAreaKarney(polygon *PolygonStt) (AreaStt, DistanceStt) {
+	return polygonArea(el.geodesic(), polygon)
+}
+
+// English: Same as GeodesicAreaKarney(), by the spherical excess on the sphere with the same area of the ellipsoid of
+// the datum selected by SetDatum(). The difference to the ellipsoid reaches about 1%.
+//
+// Português: O mesmo que GeodesicAreaKarney(), pelo excesso esférico na esfera com a mesma área do elipsoide do datum
+// selecionado por SetDatum(). A diferença para o elipsoide chega a cerca de 1%.
+func (el *PolygonStt) GeodesicAreaSpherical() (AreaStt, DistanceStt) {
+	return polygonArea(sphereGeodesic(), el)
+}
+
+// polygonListArea is the sum of the absolute areas and of the perimeters of the polygons.
+func polygonListArea(solver *geodesicStt, list []PolygonStt) (AreaStt, DistanceStt) {
+	var area AreaStt
+	var perimeter DistanceStt
+	perimeter.SetMeters(0)
+
+	for k := range list {
+		polygonAreaStt, polygonPerimeter := polygonArea(solver, &list[k])
+		area.AddSquareMeters(math.Abs(polygonAreaStt.GetSquareMeters()))
+		perimeter.AddMeters(polygonPerimeter.GetMeters())
+	}
+
+	return area, perimeter
+}
+
+// English: Sum of the areas, always positive, and of the perimeters of the polygons, as calculated by
+// PolygonStt.GeodesicAreaKarney().
+//
+// Português: Soma das áreas, sempre positivas, e dos perímetros dos polígonos, como calculados por
+// PolygonStt.GeodesicAreaKarney().
+func (el *PolygonListStt) GeodesicAreaKarney() (AreaStt, DistanceStt) {
+	return polygonListArea(GetDatum().Ellipsoid.geodesic(), el.List)
+}
+
+// English: Same as PolygonListStt.GeodesicAreaKarney(), on the ellipsoid.
+//
+// Português: O mesmo que PolygonListStt.GeodesicAreaKarney(), no elipsoide.
+func (el EllipsoidStt) PolygonListAreaKarney(list *PolygonListStt) (AreaStt, DistanceStt) {
+	return polygonListArea(el.geodesic(), list.List)
+}
+
+// English: Sum of the areas, always positive, and of the perimeters of the polygons, as calculated by
+// PolygonStt.GeodesicAreaSpherical().
+//
+// Português: Soma das áreas, sempre positivas, e dos perímetros dos polígonos, como calculados por
+// PolygonStt.GeodesicAreaSpherical().
+func (el *PolygonListStt) GeodesicAreaSpherical() (AreaStt, DistanceStt) {
+	return polygonListArea(sphereGeodesic(), el.List)
+}
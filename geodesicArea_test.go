@@ -0,0 +1,76 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+func ExamplePolygonStt_GeodesicAreaKarney() {
+	// the Antarctica of the documentation of GeographicLib, as latitude and longitude, around the south pole
+	var antarctica = [][2]float64{
+		{-63.1, -58}, {-72.9, -74}, {-71.9, -102}, {-74.9, -102}, {-74.3, -131}, {-77.5, -163}, {-77.4, 163},
+		{-71.7, 172}, {-65.9, 140}, {-65.7, 113}, {-66.6, 88}, {-66.9, 59}, {-69.8, 25}, {-70.0, -4}, {-71.0, -14},
+		{-77.3, -33}, {-77.9, -46}, {-74.7, -61},
+	}
+
+	var polygon PolygonStt
+	for _, point := range antarctica {
+		polygon.AddLngLatDegrees(point[1], point[0])
+	}
+
+	area, perimeter := polygon.GeodesicAreaKarney()
+	fmt.Printf("karney: %.1f m², %.3f m\n", area.GetSquareMeters(), perimeter.GetMeters())
+
+	area, perimeter = polygon.GeodesicAreaSpherical()
+	fmt.Printf("spherical: %.0f km², %.3f km\n", area.GetSquareKilometers(), perimeter.GetKilometers())
+
+	// a clockwise parcel of one degree with a hole of a quarter of degree
+	var parcel, hole PolygonStt
+	parcel.AddLngLatDegrees(-48, -16)
+	parcel.AddLngLatDegrees(-48, -15)
+	parcel.AddLngLatDegrees(-47, -15)
+	parcel.AddLngLatDegrees(-47, -16)
+
+	hole.AddLngLatDegrees(-47.75, -15.75)
+	hole.AddLngLatDegrees(-47.5, -15.75)
+	hole.AddLngLatDegrees(-47.5, -15.5)
+	hole.AddLngLatDegrees(-47.75, -15.5)
+	parcel.Inner = append(parcel.Inner, hole)
+
+	area, perimeter = parcel.GeodesicAreaKarney()
+	fmt.Printf("parcel: %.2f ha, %.3f km\n", area.GetHectares(), perimeter.GetKilometers())
+
+	var list PolygonListStt
+	list.AddPolygon(&polygon)
+	list.AddPolygon(&parcel)
+
+	area, _ = list.GeodesicAreaKarney()
+	fmt.Printf("list: %.0f km²\n", area.GetSquareKilometers())
+
+	// Output:
+	// karney: 13662703680020.1 m², 16831067.893 m
+	// spherical: 13552518 km², 16765.657 km
+	// parcel: -1113122.97 ha, 544.835 km
+	// list: 13673835 km²
+}
+
+func ExampleEllipsoidStt_PolygonAreaKarney() {
+	// the same parcel on the ellipsoids of two datums, without changing the datum selected
+	var parcel PolygonStt
+	parcel.AddLngLatDegrees(-48, -16)
+	parcel.AddLngLatDegrees(-47, -16)
+	parcel.AddLngLatDegrees(-47, -15)
+	parcel.AddLngLatDegrees(-48, -15)
+
+	var list PolygonListStt
+	list.List = append(list.List, parcel)
+
+	for _, datum := range []DatumStt{DATUM_WGS84, DATUM_SAD69} {
+		area, perimeter := datum.Ellipsoid.PolygonAreaKarney(&parcel)
+		listArea, _ := datum.Ellipsoid.PolygonListAreaKarney(&list)
+		fmt.Printf("%v: %.2f ha, %.3f km, list %.2f ha\n", datum.Name, area.GetHectares(), perimeter.GetKilometers(), listArea.GetHectares())
+	}
+
+	// Output:
+	// WGS84: 1187283.72 ha, 435.892 km, list 1187283.72 ha
+	// SAD69: 1187292.12 ha, 435.894 km, list 1187292.12 ha
+}
@@ -0,0 +1,1045 @@
+package iotmaker_geo_osm
+
+import (
+	"math"
+)
+
+// Order of the series used by the geodesic algorithms, as in GeographicLib.
+const (
+	geodesicOrder = 6
+	geodesicNC3x  = geodesicOrder * (geodesicOrder - 1) / 2
+	geodesicNC4x  = geodesicOrder * (geodesicOrder + 1) / 2
+
+	geodesicMaxIt1 = 20
+	geodesicMaxIt2 = geodesicMaxIt1 + 53 + 10
+)
+
+var (
+	geodesicTiny    = math.Sqrt(math.SmallestNonzeroFloat64 * (1 << 52))
+	geodesicTol0    = math.Nextafter(1, 2) - 1
+	geodesicTol1    = 200 * geodesicTol0
+	geodesicTol2    = math.Sqrt(geodesicTol0)
+	geodesicTolB    = geodesicTol0 * geodesicTol2
+	geodesicXThresh = 1000 * geodesicTol2
+)
+
+// geodesicStt solves the geodesic problems on an ellipsoid with the algorithms of C. F. F. Karney, "Algorithms for
+// geodesics", J. Geodesy 87, 43–55 (2013), the same used by GeographicLib. The error is about 15 nanometers and the
+// inverse problem converges for all pairs of points, antipodal ones included.
+type geodesicStt struct {
+	a     float64
+	f     float64
+	f1    float64
+	e2    float64
+	ep2   float64
+	n     float64
+	b     float64
+	etol2 float64
+	a3x   [geodesicOrder]float64
+	c3x   [geodesicNC3x]float64
+	c4x   [geodesicNC4x]float64
+
+	// square of the authalic radius
+	c2 float64
+}
+
+func newGeodesic(a, f float64) *geodesicStt {
+	var el = &geodesicStt{a: a, f: f}
+
+	el.f1 = 1 - f
+	el.e2 = f * (2 - f)
+	el.ep2 = el.e2 / (el.f1 * el.f1)
+	el.n = f / (2 - f)
+	el.b = a * el.f1
+	el.etol2 = 0.1 * geodesicTol2 / math.Sqrt(math.Max(0.001, math.Abs(f))*math.Min(1.0, 1-f/2)/2)
+
+	el.c2 = a * a
+	if el.e2 > 0 {
+		el.c2 = (a*a + el.b*el.b*math.Atanh(math.Sqrt(el.e2))/math.Sqrt(el.e2)) / 2
+	} else if el.e2 < 0 {
+		el.c2 = (a*a + el.b*el.b*math.Atan(math.Sqrt(-el.e2))/math.Sqrt(-el.e2)) / 2
+	}
+
+	el.a3coeff()
+	el.c3coeff()
+	el.c4coeff()
+
+	return el
+}
+
+func geodesicPolyval(n int, p []float64, s int, x float64) float64 {
+	var y = 0.0
+	if n >= 0 {
+		y = p[s]
+	}
+
+	for n > 0 {
+		n -= 1
+		s += 1
+		y = y*x + p[s]
+	}
+
+	return y
+}
+
+func geodesicNorm(x, y float64) (float64, float64) {
+	var r = math.Hypot(x, y)
+	return x / r, y / r
+}
+
+func geodesicSum(u, v float64) (float64, float64) {
+	var s = u + v
+	var up = s - v
+	var vpp = s - up
+	up -= u
+	vpp -= v
+
+	if s == 0 {
+		return s, s
+	}
+
+	return s, -(up + vpp)
+}
+
+// geodesicAngNormalize reduces the angle to the range (-180, 180], in degrees.
+func geodesicAngNormalize(x float64) float64 {
+	var y = math.Remainder(x, 360)
+	if y == -180 {
+		return 180
+	}
+
+	return y
+}
+
+func geodesicAngDiff(x, y float64) (float64, float64) {
+	d, t := geodesicSum(math.Remainder(-x, 360), math.Remainder(y, 360))
+	d, t = geodesicSum(math.Remainder(d, 360), t)
+
+	if d == 0 || math.Abs(d) == 180 {
+		if t == 0 {
+			d = math.Copysign(d, y-x)
+		} else {
+			d = math.Copysign(d, -t)
+		}
+	}
+
+	return d, t
+}
+
+// geodesicAngRound rounds tiny angles so that the results are symmetric.
+func geodesicAngRound(x float64) float64 {
+	const z = 1.0 / 16.0
+	var y = math.Abs(x)
+	if y < z {
+		y = z - (z - y)
+	}
+
+	return math.Copysign(y, x)
+}
+
+// geodesicSinCosD is the sine and the cosine of an angle in degrees, exact for multiples of 90°.
+func geodesicSinCosD(x float64) (float64, float64) {
+	var r = math.Mod(x, 360)
+	var q = 0
+	if !math.IsNaN(r) {
+		q = int(math.RoundToEven(r / 90))
+	}
+
+	r -= 90 * float64(q)
+	r = r * math.Pi / 180
+
+	var s, c = math.Sin(r), math.Cos(r)
+
+	switch ((q % 4) + 4) % 4 {
+	case 1:
+		s, c = c, -s
+	case 2:
+		s, c = -s, -c
+	case 3:
+		s, c = -c, s
+	}
+
+	c += 0
+	if s == 0 {
+		s = math.Copysign(s, x)
+	}
+
+	return s, c
+}
+
+// geodesicAtan2D is atan2 in degrees, exact for multiples of 90°.
+func geodesicAtan2D(y, x float64) float64 {
+	var q = 0
+	if math.Abs(y) > math.Abs(x) {
+		q = 2
+		x, y = y, x
+	}
+
+	if x < 0 {
+		q += 1
+		x = -x
+	}
+
+	var ang = math.Atan2(y, x) * 180 / math.Pi
+	switch q {
+	case 1:
+		ang = math.Copysign(180, y) - ang
+	case 2:
+		ang = 90 - ang
+	case 3:
+		ang = -90 + ang
+	}
+
+	return ang
+}
+
+func geodesicSinCosSeries(sinp bool, sinx, cosx float64, c []float64) float64 {
+	var k = len(c)
+	var n = k
+	if sinp {
+		n -= 1
+	}
+
+	var ar = 2 * (cosx - sinx) * (cosx + sinx)
+	var y0, y1 = 0.0, 0.0
+
+	if n&1 != 0 {
+		k -= 1
+		y0 = c[k]
+	}
+
+	n /= 2
+	for n > 0 {
+		n -= 1
+		k -= 1
+		y1 = ar*y0 - y1 + c[k]
+		k -= 1
+		y0 = ar*y1 - y0 + c[k]
+	}
+
+	if sinp {
+		return 2 * sinx * cosx * y0
+	}
+
+	return cosx * (y0 - y1)
+}
+
+func geodesicAstroid(x, y float64) float64 {
+	var p = x * x
+	var q = y * y
+	var r = (p + q - 1) / 6
+
+	if q == 0 && r <= 0 {
+		return 0
+	}
+
+	var s = p * q / 4
+	var r2 = r * r
+	var r3 = r * r2
+	var disc = s * (s + 2*r3)
+	var u = r
+
+	if disc >= 0 {
+		var t3 = s + r3
+		if t3 < 0 {
+			t3 -= math.Sqrt(disc)
+		} else {
+			t3 += math.Sqrt(disc)
+		}
+
+		var t = math.Cbrt(t3)
+		u += t
+		if t != 0 {
+			u += r2 / t
+		}
+	} else {
+		var ang = math.Atan2(math.Sqrt(-disc), -(s + r3))
+		u += 2 * r * math.Cos(ang/3)
+	}
+
+	var v = math.Sqrt(u*u + q)
+	// u + v, without the loss of accuracy when u is negative
+	var uv = u + v
+	if u < 0 {
+		uv = q / (v - u)
+	}
+
+	var w = (uv - q) / (2 * v)
+
+	return uv / (math.Sqrt(uv+w*w) + w)
+}
+
+func geodesicA1m1f(eps float64) float64 {
+	var coeff = []float64{1, 4, 64, 0, 256}
+	var m = geodesicOrder / 2
+	var t = geodesicPolyval(m, coeff, 0, eps*eps) / coeff[m+1]
+
+	return (t + eps) / (1 - eps)
+}
+
+func geodesicCf(coeff []float64, eps float64, c []float64) {
+	var eps2 = eps * eps
+	var d = eps
+	var o = 0
+
+	for l := 1; l <= geodesicOrder; l += 1 {
+		var m = (geodesicOrder - l) / 2
+		c[l] = d * geodesicPolyval(m, coeff, o, eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+func geodesicC1f(eps float64, c []float64) {
+	geodesicCf([]float64{
+		-1, 6, -16, 32,
+		-9, 64, -128, 2048,
+		9, -16, 768,
+		3, -5, 512,
+		-7, 1280,
+		-7, 2048,
+	}, eps, c)
+}
+
+func geodesicC1pf(eps float64, c []float64) {
+	geodesicCf([]float64{
+		205, -432, 768, 1536,
+		4005, -4736, 3840, 12288,
+		-225, 116, 384,
+		-7173, 2695, 7680,
+		3467, 7680,
+		38081, 61440,
+	}, eps, c)
+}
+
+func geodesicA2m1f(eps float64) float64 {
+	var coeff = []float64{-11, -28, -192, 0, 256}
+	var m = geodesicOrder / 2
+	var t = geodesicPolyval(m, coeff, 0, eps*eps) / coeff[m+1]
+
+	return (t - eps) / (1 + eps)
+}
+
+func geodesicC2f(eps float64, c []float64) {
+	geodesicCf([]float64{
+		1, 2, 16, 32,
+		35, 64, 384, 2048,
+		15, 80, 768,
+		7, 35, 512,
+		63, 1280,
+		77, 2048,
+	}, eps, c)
+}
+
+func (el *geodesicStt) a3coeff() {
+	var coeff = []float64{
+		-3, 128,
+		-2, -3, 64,
+		-1, -3, -1, 16,
+		3, -1, -2, 8,
+		1, -1, 2,
+		1, 1,
+	}
+
+	var o, k = 0, 0
+	for j := geodesicOrder - 1; j >= 0; j -= 1 {
+		var m = geodesicOrder - j - 1
+		if j < m {
+			m = j
+		}
+		el.a3x[k] = geodesicPolyval(m, coeff, o, el.n) / coeff[o+m+1]
+		k += 1
+		o += m + 2
+	}
+}
+
+func (el *geodesicStt) c3coeff() {
+	var coeff = []float64{
+		3, 128,
+		2, 5, 128,
+		-1, 3, 3, 64,
+		-1, 0, 1, 8,
+		-1, 1, 4,
+		5, 256,
+		1, 3, 128,
+		-3, -2, 3, 64,
+		1, -3, 2, 32,
+		7, 512,
+		-10, 9, 384,
+		5, -9, 5, 192,
+		7, 512,
+		-14, 7, 512,
+		21, 2560,
+	}
+
+	var o, k = 0, 0
+	for l := 1; l < geodesicOrder; l += 1 {
+		for j := geodesicOrder - 1; j >= l; j -= 1 {
+			var m = geodesicOrder - j - 1
+			if j < m {
+				m = j
+			}
+			el.c3x[k] = geodesicPolyval(m, coeff, o, el.n) / coeff[o+m+1]
+			k += 1
+			o += m + 2
+		}
+	}
+}
+
+func (el *geodesicStt) c4coeff() {
+	var coeff = []float64{
+		97, 15015,
+		1088, 156, 45045,
+		-224, -4784, 1573, 45045,
+		-10656, 14144, -4576, -858, 45045,
+		64, 624, -4576, 6864, -3003, 15015,
+		100, 208, 572, 3432, -12012, 30030, 45045,
+		1, 9009,
+		-2944, 468, 135135,
+		5792, 1040, -1287, 135135,
+		5952, -11648, 9152, -2574, 135135,
+		-64, -624, 4576, -6864, 3003, 135135,
+		8, 10725,
+		1856, -936, 225225,
+		-8448, 4992, -1144, 225225,
+		-1440, 4160, -4576, 1716, 225225,
+		-136, 63063,
+		1024, -208, 105105,
+		3584, -3328, 1144, 315315,
+		-128, 135135,
+		-2560, 832, 405405,
+		128, 99099,
+	}
+
+	var o, k = 0, 0
+	for l := 0; l < geodesicOrder; l += 1 {
+		for j := geodesicOrder - 1; j >= l; j -= 1 {
+			var m = geodesicOrder - j - 1
+			el.c4x[k] = geodesicPolyval(m, coeff, o, el.n) / coeff[o+m+1]
+			k += 1
+			o += m + 2
+		}
+	}
+}
+
+func (el *geodesicStt) a3f(eps float64) float64 {
+	return geodesicPolyval(geodesicOrder-1, el.a3x[:], 0, eps)
+}
+
+func (el *geodesicStt) c3f(eps float64, c []float64) {
+	var mult = 1.0
+	var o = 0
+
+	for l := 1; l < geodesicOrder; l += 1 {
+		var m = geodesicOrder - l - 1
+		mult *= eps
+		c[l] = mult * geodesicPolyval(m, el.c3x[:], o, eps)
+		o += m + 1
+	}
+}
+
+func (el *geodesicStt) c4f(eps float64, c []float64) {
+	var mult = 1.0
+	var o = 0
+
+	for l := 0; l < geodesicOrder; l += 1 {
+		var m = geodesicOrder - l - 1
+		c[l] = mult * geodesicPolyval(m, el.c4x[:], o, eps)
+		o += m + 1
+		mult *= eps
+	}
+}
+
+// lengths returns the distance and the reduced length, both divided by b. The reduced length is only calculated
+// when reduced is true.
+func (el *geodesicStt) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2 float64, distance, reduced bool, c1a, c2a []float64) (float64, float64) {
+	var s12b, m12b = math.NaN(), math.NaN()
+	var a1, a2, m0x, j12 float64
+
+	a1 = geodesicA1m1f(eps)
+	geodesicC1f(eps, c1a)
+	if reduced {
+		a2 = geodesicA2m1f(eps)
+		geodesicC2f(eps, c2a)
+		m0x = a1 - a2
+		a2 = 1 + a2
+	}
+	a1 = 1 + a1
+
+	if distance {
+		var b1 = geodesicSinCosSeries(true, ssig2, csig2, c1a) - geodesicSinCosSeries(true, ssig1, csig1, c1a)
+		s12b = a1 * (sig12 + b1)
+
+		if reduced {
+			var b2 = geodesicSinCosSeries(true, ssig2, csig2, c2a) - geodesicSinCosSeries(true, ssig1, csig1, c2a)
+			j12 = m0x*sig12 + (a1*b1 - a2*b2)
+		}
+	} else if reduced {
+		for l := 1; l <= geodesicOrder; l += 1 {
+			c2a[l] = a1*c1a[l] - a2*c2a[l]
+		}
+		j12 = m0x*sig12 + (geodesicSinCosSeries(true, ssig2, csig2, c2a) - geodesicSinCosSeries(true, ssig1, csig1, c2a))
+	}
+
+	if reduced {
+		m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*j12
+	}
+
+	return s12b, m12b
+}
+
+// inverseStart is the first guess of the azimuth at the first point. A non negative sig12 means that the problem
+// was solved for short lines.
+func (el *geodesicStt) inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12 float64, c1a, c2a []float64) (sig12, salp1, calp1, salp2, calp2, dnm float64) {
+	sig12 = -1
+	salp2, calp2, dnm = math.NaN(), math.NaN(), math.NaN()
+
+	var sbet12 = sbet2*cbet1 - cbet2*sbet1
+	var cbet12 = cbet2*cbet1 + sbet2*sbet1
+	var sbet12a = sbet2*cbet1 + cbet2*sbet1
+	var shortline = cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+	var somg12, comg12 float64
+
+	if shortline {
+		var sbetm2 = (sbet1 + sbet2) * (sbet1 + sbet2)
+		sbetm2 /= sbetm2 + (cbet1+cbet2)*(cbet1+cbet2)
+		dnm = math.Sqrt(1 + el.ep2*sbetm2)
+		var omg12 = lam12 / (el.f1 * dnm)
+		somg12, comg12 = math.Sin(omg12), math.Cos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*somg12*somg12/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+	}
+
+	var ssig12 = math.Hypot(salp1, calp1)
+	var csig12 = sbet1*sbet2 + cbet1*cbet2*comg12
+
+	if shortline && ssig12 < el.etol2 {
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*(somg12*somg12/(1+comg12))
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = geodesicNorm(salp2, calp2)
+		sig12 = math.Atan2(ssig12, csig12)
+	} else if math.Abs(el.n) >= 0.1 || csig12 >= 0 || ssig12 >= 6*math.Abs(el.n)*math.Pi*cbet1*cbet1 {
+		// nothing to do, zeroth order spherical approximation is fine
+	} else {
+		// nearly antipodal points, the astroid problem
+		var x, y, lamscale, betscale float64
+		var lam12x = math.Atan2(-slam12, -clam12)
+
+		if el.f >= 0 {
+			var k2 = sbet1 * sbet1 * el.ep2
+			var eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			lamscale = el.f * cbet1 * el.a3f(eps) * math.Pi
+			betscale = lamscale * cbet1
+			x = lam12x / lamscale
+			y = sbet12a / betscale
+		} else {
+			var cbet12a = cbet2*cbet1 - sbet2*sbet1
+			var bet12a = math.Atan2(sbet12a, cbet12a)
+			var m12b, m0 float64
+			_, m12b = el.lengths(el.n, math.Pi+bet12a, sbet1, -cbet1, dn1, sbet2, cbet2, dn2, false, true, c1a, c2a)
+			m0 = geodesicA1m1f(el.n) - geodesicA2m1f(el.n)
+			x = -1 + m12b/(cbet1*cbet2*m0*math.Pi)
+			if x < -0.01 {
+				betscale = sbet12a / x
+			} else {
+				betscale = -el.f * cbet1 * cbet1 * math.Pi
+			}
+			lamscale = betscale / cbet1
+			y = lam12x / lamscale
+		}
+
+		if y > -geodesicTol1 && x > -1-geodesicXThresh {
+			if el.f >= 0 {
+				salp1 = math.Min(1.0, -x)
+				calp1 = -math.Sqrt(1 - salp1*salp1)
+			} else {
+				if x > -geodesicTol1 {
+					calp1 = 0
+				} else {
+					calp1 = math.Max(-1.0, x)
+				}
+				salp1 = math.Sqrt(1 - calp1*calp1)
+			}
+		} else {
+			var k = geodesicAstroid(x, y)
+			var omg12a float64
+			if el.f >= 0 {
+				omg12a = lamscale * (-x * k / (1 + k))
+			} else {
+				omg12a = lamscale * (-y * (1 + k) / k)
+			}
+			somg12, comg12 = math.Sin(omg12a), -math.Cos(omg12a)
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+		}
+	}
+
+	if !(salp1 <= 0) {
+		salp1, calp1 = geodesicNorm(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+
+	return
+}
+
+// geodesicLambdaStt is the result of lambda12, the longitude difference for a given azimuth at the first point.
+type geodesicLambdaStt struct {
+	lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12 float64
+}
+
+func (el *geodesicStt) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam120, clam120 float64, diffp bool, c1a, c2a, c3a []float64) geodesicLambdaStt {
+	var ret geodesicLambdaStt
+
+	if sbet1 == 0 && calp1 == 0 {
+		calp1 = -geodesicTiny
+	}
+
+	var salp0 = salp1 * cbet1
+	var calp0 = math.Hypot(calp1, salp1*sbet1)
+
+	var somg1 = salp0 * sbet1
+	var comg1 = calp1 * cbet1
+	ret.ssig1, ret.csig1 = geodesicNorm(sbet1, comg1)
+
+	if cbet2 != cbet1 {
+		ret.salp2 = salp0 / cbet2
+	} else {
+		ret.salp2 = salp1
+	}
+
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var t float64
+		if cbet1 < -sbet1 {
+			t = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			t = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		ret.calp2 = math.Sqrt((calp1*cbet1)*(calp1*cbet1)+t) / cbet2
+	} else {
+		ret.calp2 = math.Abs(calp1)
+	}
+
+	var somg2 = salp0 * sbet2
+	var comg2 = ret.calp2 * cbet2
+	ret.ssig2, ret.csig2 = geodesicNorm(sbet2, comg2)
+
+	ret.sig12 = math.Atan2(math.Max(0.0, ret.csig1*ret.ssig2-ret.ssig1*ret.csig2), ret.csig1*ret.csig2+ret.ssig1*ret.ssig2)
+
+	var somg12 = math.Max(0.0, comg1*somg2-somg1*comg2)
+	var comg12 = comg1*comg2 + somg1*somg2
+	var eta = math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+
+	var k2 = calp0 * calp0 * el.ep2
+	ret.eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	el.c3f(ret.eps, c3a)
+
+	var b312 = geodesicSinCosSeries(true, ret.ssig2, ret.csig2, c3a) - geodesicSinCosSeries(true, ret.ssig1, ret.csig1, c3a)
+	ret.domg12 = -el.f * el.a3f(ret.eps) * salp0 * (ret.sig12 + b312)
+	ret.lam12 = eta + ret.domg12
+
+	ret.dlam12 = math.NaN()
+	if diffp {
+		if ret.calp2 == 0 {
+			ret.dlam12 = -2 * el.f1 * dn1 / sbet1
+		} else {
+			_, ret.dlam12 = el.lengths(ret.eps, ret.sig12, ret.ssig1, ret.csig1, dn1, ret.ssig2, ret.csig2, dn2, false, true, c1a, c2a)
+			ret.dlam12 *= el.f1 / (ret.calp2 * cbet2)
+		}
+	}
+
+	return ret
+}
+
+// inverse solves the inverse problem and returns the distance, in meters, and the azimuths at both points, in
+// degrees.
+func (el *geodesicStt) inverse(lat1, lon1, lat2, lon2 float64) (s12, azi1, azi2 float64) {
+	s12, azi1, azi2, _ = el.inverseArea(lat1, lon1, lat2, lon2, false)
+	return
+}
+
+// inverseArea is inverse, also returning, when area is true, the area between the geodesic and the equator, in
+// square meters.
+func (el *geodesicStt) inverseArea(lat1, lon1, lat2, lon2 float64, area bool) (s12, azi1, azi2, s12Area float64) {
+	lon12, lon12s := geodesicAngDiff(lon1, lon2)
+	var lonsign = math.Copysign(1, lon12)
+	lon12 = lonsign * geodesicAngRound(lon12)
+	lon12s = geodesicAngRound((180 - lon12) - lonsign*lon12s)
+	var lam12 = lon12 * math.Pi / 180
+
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = geodesicSinCosD(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = geodesicSinCosD(lon12)
+	}
+
+	lat1 = geodesicAngRound(lat1)
+	lat2 = geodesicAngRound(lat2)
+
+	var swapp = 1.0
+	if math.Abs(lat1) < math.Abs(lat2) {
+		swapp = -1
+		lonsign *= -1
+		lat1, lat2 = lat2, lat1
+	}
+
+	var latsign = math.Copysign(1, -lat1)
+	lat1 *= latsign
+	lat2 *= latsign
+
+	sbet1, cbet1 := geodesicSinCosD(lat1)
+	sbet1 *= el.f1
+	sbet1, cbet1 = geodesicNorm(sbet1, cbet1)
+	cbet1 = math.Max(geodesicTiny, cbet1)
+
+	sbet2, cbet2 := geodesicSinCosD(lat2)
+	sbet2 *= el.f1
+	sbet2, cbet2 = geodesicNorm(sbet2, cbet2)
+	cbet2 = math.Max(geodesicTiny, cbet2)
+
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else {
+		if math.Abs(sbet2) == -sbet1 {
+			cbet2 = cbet1
+		}
+	}
+
+	var dn1 = math.Sqrt(1 + el.ep2*sbet1*sbet1)
+	var dn2 = math.Sqrt(1 + el.ep2*sbet2*sbet2)
+
+	var c1a = make([]float64, geodesicOrder+1)
+	var c2a = make([]float64, geodesicOrder+1)
+	var c3a = make([]float64, geodesicOrder)
+
+	var salp1, calp1, salp2, calp2, sig12, s12x, m12x float64
+
+	// sine and cosine of the longitude difference on the auxiliary sphere, 2 while unknown
+	var somg12, comg12 = 2.0, 0.0
+
+	var meridian = lat1 == -90 || slam12 == 0
+	if meridian {
+		// along a meridian, also the case of a pole
+		calp1, salp1 = clam12, slam12
+		calp2, salp2 = 1.0, 0.0
+
+		var ssig1, csig1 = sbet1, calp1 * cbet1
+		var ssig2, csig2 = sbet2, calp2 * cbet2
+
+		sig12 = math.Atan2(math.Max(0.0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+		s12x, m12x = el.lengths(el.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, true, true, c1a, c2a)
+
+		if sig12 < 1 || m12x >= 0 {
+			if sig12 < 3*geodesicTiny || (sig12 < geodesicTol0 && (s12x < 0 || m12x < 0)) {
+				sig12, m12x, s12x = 0, 0, 0
+			}
+			s12x *= el.b
+		} else {
+			meridian = false
+		}
+	}
+
+	if !meridian && sbet1 == 0 && (el.f <= 0 || lon12s >= el.f*180) {
+		// along the equator
+		calp1, calp2 = 0, 0
+		salp1, salp2 = 1, 1
+		s12x = el.a * lam12
+		somg12, comg12 = math.Sin(lam12/el.f1), math.Cos(lam12/el.f1)
+	} else if !meridian {
+		var dnm float64
+		sig12, salp1, calp1, salp2, calp2, dnm = el.inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12, c1a, c2a)
+
+		if sig12 >= 0 {
+			s12x = sig12 * el.b * dnm
+			somg12, comg12 = math.Sin(lam12/(el.f1*dnm)), math.Cos(lam12/(el.f1*dnm))
+		} else {
+			// Newton's method on the azimuth at the first point, with bisection as a safeguard
+			var lambda geodesicLambdaStt
+			var numit = 0
+			var tripn, tripb = false, false
+			var salp1a, calp1a = geodesicTiny, 1.0
+			var salp1b, calp1b = geodesicTiny, -1.0
+
+			for numit < geodesicMaxIt2 {
+				lambda = el.lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam12, clam12, numit < geodesicMaxIt1, c1a, c2a, c3a)
+				var v = lambda.lam12
+				salp2, calp2 = lambda.salp2, lambda.calp2
+
+				var tolerance = 1.0
+				if tripn {
+					tolerance = 8
+				}
+				if tripb || !(math.Abs(v) >= tolerance*geodesicTol0) {
+					break
+				}
+
+				if v > 0 && (numit > geodesicMaxIt1 || calp1/salp1 > calp1b/salp1b) {
+					salp1b, calp1b = salp1, calp1
+				} else if v < 0 && (numit > geodesicMaxIt1 || calp1/salp1 < calp1a/salp1a) {
+					salp1a, calp1a = salp1, calp1
+				}
+
+				numit += 1
+
+				if numit < geodesicMaxIt1 && lambda.dlam12 > 0 {
+					var dalp1 = -v / lambda.dlam12
+					if math.Abs(dalp1) < math.Pi {
+						var sdalp1, cdalp1 = math.Sin(dalp1), math.Cos(dalp1)
+						var nsalp1 = salp1*cdalp1 + calp1*sdalp1
+						if nsalp1 > 0 {
+							calp1 = calp1*cdalp1 - salp1*sdalp1
+							salp1 = nsalp1
+							salp1, calp1 = geodesicNorm(salp1, calp1)
+							tripn = math.Abs(v) <= 16*geodesicTol0
+							continue
+						}
+					}
+				}
+
+				salp1, calp1 = geodesicNorm((salp1a+salp1b)/2, (calp1a+calp1b)/2)
+				tripn = false
+				tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < geodesicTolB || math.Abs(salp1-salp1b)+(calp1-calp1b) < geodesicTolB
+			}
+
+			s12x, _ = el.lengths(lambda.eps, lambda.sig12, lambda.ssig1, lambda.csig1, dn1, lambda.ssig2, lambda.csig2, dn2, true, false, c1a, c2a)
+			s12x *= el.b
+
+			var sdomg12, cdomg12 = math.Sin(lambda.domg12), math.Cos(lambda.domg12)
+			somg12 = slam12*cdomg12 - clam12*sdomg12
+			comg12 = clam12*cdomg12 + slam12*sdomg12
+		}
+	}
+
+	s12 = 0 + s12x
+
+	if area {
+		s12Area = el.area(sbet1, cbet1, sbet2, cbet2, salp1, calp1, salp2, calp2, somg12, comg12, meridian)
+		s12Area *= swapp * lonsign * latsign
+		s12Area += 0
+	}
+
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+	}
+
+	salp1 *= swapp * lonsign
+	calp1 *= swapp * latsign
+	salp2 *= swapp * lonsign
+	calp2 *= swapp * latsign
+
+	azi1 = geodesicAtan2D(salp1, calp1)
+	azi2 = geodesicAtan2D(salp2, calp2)
+
+	return
+}
+
+// area is the area between the geodesic and the equator, with the values of the inverse problem before the points
+// are swapped back.
+func (el *geodesicStt) area(sbet1, cbet1, sbet2, cbet2, salp1, calp1, salp2, calp2, somg12, comg12 float64, meridian bool) float64 {
+	var s12Area = 0.0
+
+	var salp0 = salp1 * cbet1
+	var calp0 = math.Hypot(calp1, salp1*sbet1)
+
+	if calp0 != 0 && salp0 != 0 {
+		var ssig1, csig1 = geodesicNorm(sbet1, calp1*cbet1)
+		var ssig2, csig2 = geodesicNorm(sbet2, calp2*cbet2)
+
+		var k2 = calp0 * calp0 * el.ep2
+		var eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		var a4 = el.a * el.a * calp0 * salp0 * el.e2
+
+		var c4a = make([]float64, geodesicOrder)
+		el.c4f(eps, c4a)
+
+		s12Area = a4 * (geodesicSinCosSeries(false, ssig2, csig2, c4a) - geodesicSinCosSeries(false, ssig1, csig1, c4a))
+	}
+
+	var alp12 float64
+	if !meridian && comg12 > -0.7071 && sbet2-sbet1 < 1.75 {
+		var domg12 = 1 + comg12
+		var dbet1 = 1 + cbet1
+		var dbet2 = 1 + cbet2
+		alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1), domg12*(sbet1*sbet2+dbet1*dbet2))
+	} else {
+		var salp12 = salp2*calp1 - calp2*salp1
+		var calp12 = calp2*calp1 + salp2*salp1
+		if salp12 == 0 && calp12 < 0 {
+			salp12 = geodesicTiny * calp1
+			calp12 = -1
+		}
+		alp12 = math.Atan2(salp12, calp12)
+	}
+
+	return s12Area + el.c2*alp12
+}
+
+// direct solves the direct problem and returns the latitude and the longitude of the destination and the azimuth at
+// it, in degrees.
+func (el *geodesicStt) direct(lat1, lon1, azi1, s12 float64) (lat2, lon2, azi2 float64) {
+	var salp1, calp1 = geodesicSinCosD(geodesicAngRound(azi1))
+
+	sbet1, cbet1 := geodesicSinCosD(geodesicAngRound(lat1))
+	sbet1 *= el.f1
+	sbet1, cbet1 = geodesicNorm(sbet1, cbet1)
+	cbet1 = math.Max(geodesicTiny, cbet1)
+
+	var salp0 = salp1 * cbet1
+	var calp0 = math.Hypot(calp1, salp1*sbet1)
+
+	var somg1 = salp0 * sbet1
+	var csig1 = 1.0
+	if sbet1 != 0 || calp1 != 0 {
+		csig1 = cbet1 * calp1
+	}
+	var comg1 = csig1
+	ssig1, csig1 := geodesicNorm(sbet1, csig1)
+
+	var k2 = calp0 * calp0 * el.ep2
+	var eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+
+	var a1m1 = geodesicA1m1f(eps)
+	var c1a = make([]float64, geodesicOrder+1)
+	geodesicC1f(eps, c1a)
+	var b11 = geodesicSinCosSeries(true, ssig1, csig1, c1a)
+	var s, c = math.Sin(b11), math.Cos(b11)
+	var stau1 = ssig1*c + csig1*s
+	var ctau1 = csig1*c - ssig1*s
+
+	var c1pa = make([]float64, geodesicOrder+1)
+	geodesicC1pf(eps, c1pa)
+
+	var c3a = make([]float64, geodesicOrder)
+	el.c3f(eps, c3a)
+	var a3c = -el.f * salp0 * el.a3f(eps)
+	var b31 = geodesicSinCosSeries(true, ssig1, csig1, c3a)
+
+	var tau12 = s12 / (el.b * (1 + a1m1))
+	s, c = math.Sin(tau12), math.Cos(tau12)
+	var b12 = -geodesicSinCosSeries(true, stau1*c+ctau1*s, ctau1*c-stau1*s, c1pa)
+	var sig12 = tau12 - (b12 - b11)
+	var ssig12, csig12 = math.Sin(sig12), math.Cos(sig12)
+
+	if math.Abs(el.f) > 0.01 {
+		// one step of Newton's method for very flat ellipsoids
+		var ssig2 = ssig1*csig12 + csig1*ssig12
+		var csig2 = csig1*csig12 - ssig1*ssig12
+		b12 = geodesicSinCosSeries(true, ssig2, csig2, c1a)
+		var serr = (1+a1m1)*(sig12+(b12-b11)) - s12/el.b
+		sig12 = sig12 - serr/math.Sqrt(1+k2*ssig2*ssig2)
+		ssig12, csig12 = math.Sin(sig12), math.Cos(sig12)
+	}
+
+	var ssig2 = ssig1*csig12 + csig1*ssig12
+	var csig2 = csig1*csig12 - ssig1*ssig12
+
+	var sbet2 = calp0 * ssig2
+	var cbet2 = math.Hypot(salp0, calp0*csig2)
+	if cbet2 == 0 {
+		cbet2 = geodesicTiny
+		csig2 = geodesicTiny
+	}
+
+	var salp2 = salp0
+	var calp2 = calp0 * csig2
+
+	var somg2 = salp0 * ssig2
+	var comg2 = csig2
+	var omg12 = math.Atan2(somg2*comg1-comg2*somg1, comg2*comg1+somg2*somg1)
+	var lam12 = omg12 + a3c*(sig12+(geodesicSinCosSeries(true, ssig2, csig2, c3a)-b31))
+	var lon12 = lam12 * 180 / math.Pi
+
+	lon2 = geodesicAngNormalize(geodesicAngNormalize(lon1) + geodesicAngNormalize(lon12))
+	lat2 = geodesicAtan2D(sbet2, el.f1*cbet2)
+	azi2 = geodesicAtan2D(salp2, calp2)
+
+	return
+}
+
+// English: Distance between two points along the geodesic of the ellipsoid of the datum selected by SetDatum(), with
+// the algorithm of Karney, accurate to about 15 nanometers for any pair of points, antipodal ones included. Also
+// returns the azimuth at point A towards B and the back azimuth, at point B towards A, in degrees between 0 and 360.
+//
+// Português: Distância entre dois pontos ao longo da geodésica do elipsoide do datum selecionado por SetDatum(), com o
+// algoritmo de Karney, com precisão de cerca de 15 nanômetros para qualquer par de pontos, inclusive antípodas. Também
+// devolve o azimute no ponto A em direção a B e o azimute reverso, no ponto B em direção a A, em graus entre 0 e 360.
+func GeodesicInverseKarney(pointAAStt, pointBAStt PointStt) (DistanceStt, AngleStt, AngleStt) {
+	return GetDatum().Ellipsoid.GeodesicInverseKarney(pointAAStt, pointBAStt)
+}
+
+// English: Point at the given distance and azimuth from point A along the geodesic of the ellipsoid of the datum
+// selected by SetDatum(), with the algorithm of Karney. Also returns the back azimuth, at the destination towards A,
+// in degrees between 0 and 360.
+//
+// Português: Ponto na distância e azimute dados a partir do ponto A ao longo da geodésica do elipsoide do datum
+// selecionado por SetDatum(), com o algoritmo de Karney. Também devolve o azimute reverso, no destino em direção a A,
+// em graus entre 0 e 360.
+func GeodesicDirectKarney(pointAStt PointStt, distanceAStt DistanceStt, angleAStt AngleStt) (PointStt, AngleStt) {
+	return GetDatum().Ellipsoid.GeodesicDirectKarney(pointAStt, distanceAStt, angleAStt)
+}
+
+// English: Same as GeodesicInverseKarney(), on this ellipsoid.
+//
+// Português: O mesmo que GeodesicInverseKarney(), neste elipsoide.
+func (el EllipsoidStt) GeodesicInverseKarney(pointAAStt, pointBAStt PointStt) (DistanceStt, AngleStt, AngleStt) {
+	s12, azi1, azi2 := el.geodesic().inverse(pointAAStt.Loc[1], pointAAStt.Loc[0], pointBAStt.Loc[1], pointBAStt.Loc[0])
+
+	return geodesicResult(s12, azi1, azi2)
+}
+
+// English: Same as GeodesicDirectKarney(), on this ellipsoid.
+//
+// Português: O mesmo que GeodesicDirectKarney(), neste elipsoide.
+func (el EllipsoidStt) GeodesicDirectKarney(pointAStt PointStt, distanceAStt DistanceStt, angleAStt AngleStt) (PointStt, AngleStt) {
+	var returnLStt PointStt
+
+	lat2, lon2, azi2 := el.geodesic().direct(pointAStt.Loc[1], pointAStt.Loc[0], angleAStt.GetAsDegrees(), distanceAStt.GetMeters())
+	returnLStt.SetLngLatDegrees(lon2, lat2)
+
+	return returnLStt, geodesicBackAzimuth(azi2)
+}
+
+// geodesicResult converts the result of an inverse problem, with the azimuths as calculated at each point.
+func geodesicResult(s12, azi1, azi2 float64) (DistanceStt, AngleStt, AngleStt) {
+	var distance DistanceStt
+	distance.SetMeters(s12)
+
+	var azimuth AngleStt
+	azimuth.SetDegrees(geodesicAzimuth(azi1))
+
+	return distance, azimuth, geodesicBackAzimuth(azi2)
+}
+
+// geodesicAzimuth is the azimuth in degrees, between 0 and 360.
+func geodesicAzimuth(azimuth float64) float64 {
+	azimuth = math.Mod(azimuth, 360)
+	if azimuth < 0 {
+		azimuth += 360
+	}
+
+	return azimuth
+}
+
+// geodesicBackAzimuth turns the forward azimuth at the end of a geodesic into the direction back to its start.
+func geodesicBackAzimuth(azimuth float64) AngleStt {
+	var ret AngleStt
+	ret.SetDegrees(geodesicAzimuth(azimuth + 180))
+
+	return ret
+}
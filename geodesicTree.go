@@ -0,0 +1,244 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// English: Point found by GeodesicTreeStt, with the distance along the geodesic and the azimuth, at the point of the
+// search, towards the point found.
+//
+// Português: Ponto achado pelo GeodesicTreeStt, com a distância ao longo da geodésica e o azimute, no ponto da busca,
+// em direção ao ponto achado.
+type GeodesicNeighborStt struct {
+	Point    *PointStt
+	Distance DistanceStt
+	Bearing  AngleStt
+}
+
+// rounding, in meters, allowed between the straight line and the geodesic, which is never shorter
+const geodesicTreeTolerance = 1e-6
+
+// geodesicTreeItemStt is the point in earth-centered, earth-fixed coordinates and the axis that divides the items
+// around it
+type geodesicTreeItemStt struct {
+	cartesian [3]float64
+	axis      int
+	point     *PointStt
+}
+
+// English: KD-tree of points for the search of the nearest points and of the points within a distance, along the
+// geodesic of the ellipsoid of the datum selected by SetDatum() when Load() is called.
+//
+// The points are kept in earth-centered, earth-fixed coordinates, where the straight line between two points is never
+// longer than the geodesic, so the antimeridian and the poles are not special cases. The tree does not change after
+// Load(), many goroutines can search it at the same time.
+//
+// Português: KD-tree de pontos para a busca dos pontos mais próximos e dos pontos dentro de uma distância, ao longo da
+// geodésica do elipsoide do datum selecionado por SetDatum() quando Load() é chamado.
+//
+// Os pontos são guardados em coordenadas cartesianas centradas na Terra, onde a reta entre dois pontos nunca é mais
+// longa que a geodésica, por isto o antimeridiano e os polos não são casos especiais. A árvore não muda depois de
+// Load(), muitas goroutines podem buscar nela ao mesmo tempo.
+type GeodesicTreeStt struct {
+	mutex     sync.RWMutex
+	ellipsoid EllipsoidStt
+	items     []geodesicTreeItemStt
+}
+
+// English: Replaces the content of the tree by the points. The altitude of the points is not used, the distances are
+// on the surface of the ellipsoid.
+//
+// Português: Substitui o conteúdo da árvore pelos pontos. A altitude dos pontos não é usada, as distâncias são na
+// superfície do elipsoide.
+func (el *GeodesicTreeStt) Load(points []*PointStt) error {
+	return el.LoadOnEllipsoid(GetDatum().Ellipsoid, points)
+}
+
+// English: Same as Load(), with the distances along the geodesic of the ellipsoid.
+//
+// Português: O mesmo que Load(), com as distâncias ao longo da geodésica do elipsoide.
+func (el *GeodesicTreeStt) LoadOnEllipsoid(ellipsoid EllipsoidStt, points []*PointStt) error {
+	var items = make([]geodesicTreeItemStt, len(points))
+
+	for k, point := range points {
+		if point == nil {
+			return fmt.Errorf("geodesic tree: the point %v is nil", k)
+		}
+
+		if math.IsNaN(point.Loc[0]) || math.IsNaN(point.Loc[1]) || math.Abs(point.Loc[1]) > 90 {
+			return fmt.Errorf("geodesic tree: the point %v is not a valid location", k)
+		}
+
+		items[k] = geodesicTreeItemStt{cartesian: ellipsoid.toCartesian(point.Loc[1], point.Loc[0], 0), point: point}
+	}
+
+	geodesicTreeBuild(items)
+
+	el.mutex.Lock()
+	defer el.mutex.Unlock()
+
+	el.ellipsoid = ellipsoid
+	el.items = items
+
+	return nil
+}
+
+// geodesicTreeBuild puts the median of the longest axis in the middle of the items, the smaller ones before and the
+// greater ones after, and does the same on each side
+func geodesicTreeBuild(items []geodesicTreeItemStt) {
+	if len(items) < 2 {
+		return
+	}
+
+	var minimum = items[0].cartesian
+	var maximum = items[0].cartesian
+	for k := range items {
+		for axis := 0; axis != 3; axis += 1 {
+			minimum[axis] = math.Min(minimum[axis], items[k].cartesian[axis])
+			maximum[axis] = math.Max(maximum[axis], items[k].cartesian[axis])
+		}
+	}
+
+	var axis = 0
+	for k := 1; k != 3; k += 1 {
+		if maximum[k]-minimum[k] > maximum[axis]-minimum[axis] {
+			axis = k
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].cartesian[axis] < items[j].cartesian[axis] })
+
+	var middle = len(items) / 2
+	items[middle].axis = axis
+
+	geodesicTreeBuild(items[:middle])
+	geodesicTreeBuild(items[middle+1:])
+}
+
+// geodesicTreeSearch visits the items of the tree, the side of the point first, and skips the sides farther than the
+// limit, in meters, which may shrink during the search
+func geodesicTreeSearch(items []geodesicTreeItemStt, cartesian [3]float64, limit func() float64, visit func(item *geodesicTreeItemStt)) {
+	if len(items) == 0 {
+		return
+	}
+
+	var middle = len(items) / 2
+	var item = &items[middle]
+
+	var chord = math.Sqrt(
+		(item.cartesian[0]-cartesian[0])*(item.cartesian[0]-cartesian[0]) +
+			(item.cartesian[1]-cartesian[1])*(item.cartesian[1]-cartesian[1]) +
+			(item.cartesian[2]-cartesian[2])*(item.cartesian[2]-cartesian[2]))
+
+	if chord <= limit()+geodesicTreeTolerance {
+		visit(item)
+	}
+
+	if len(items) == 1 {
+		return
+	}
+
+	var difference = cartesian[item.axis] - item.cartesian[item.axis]
+	var near, far = items[:middle], items[middle+1:]
+	if difference > 0 {
+		near, far = far, near
+	}
+
+	geodesicTreeSearch(near, cartesian, limit, visit)
+
+	if math.Abs(difference) <= limit()+geodesicTreeTolerance {
+		geodesicTreeSearch(far, cartesian, limit, visit)
+	}
+}
+
+// neighbor measures the geodesic from the point of the search to the item
+func (el *GeodesicTreeStt) neighbor(point PointStt, item *geodesicTreeItemStt) GeodesicNeighborStt {
+	distance, bearing, _ := el.ellipsoid.GeodesicInverseKarney(point, *item.point)
+
+	return GeodesicNeighborStt{Point: item.point, Distance: distance, Bearing: bearing}
+}
+
+// English: The k points nearest to the point, from the nearest, with the distance along the geodesic.
+//
+// Português: Os k pontos mais próximos do ponto, a partir do mais próximo, com a distância ao longo da geodésica.
+func (el *GeodesicTreeStt) Nearest(point PointStt, k int) ([]GeodesicNeighborStt, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("geodesic tree: the number of points must be at least one")
+	}
+
+	el.mutex.RLock()
+	defer el.mutex.RUnlock()
+
+	var neighbors = make([]GeodesicNeighborStt, 0, k+1)
+	var limit = func() float64 {
+		if len(neighbors) < k {
+			return math.Inf(1)
+		}
+
+		return neighbors[len(neighbors)-1].Distance.GetMeters()
+	}
+
+	var cartesian = el.ellipsoid.toCartesian(point.Loc[1], point.Loc[0], 0)
+	geodesicTreeSearch(el.items, cartesian, limit, func(item *geodesicTreeItemStt) {
+		var neighbor = el.neighbor(point, item)
+		var meters = neighbor.Distance.GetMeters()
+
+		var index = sort.Search(len(neighbors), func(i int) bool { return neighbors[i].Distance.GetMeters() > meters })
+		if index == k {
+			return
+		}
+
+		neighbors = append(neighbors, GeodesicNeighborStt{})
+		copy(neighbors[index+1:], neighbors[index:])
+		neighbors[index] = neighbor
+
+		if len(neighbors) > k {
+			neighbors = neighbors[:k]
+		}
+	})
+
+	return neighbors, nil
+}
+
+// English: The points within the distance of the point, from the nearest, with the distance along the geodesic.
+//
+// Português: Os pontos dentro da distância do ponto, a partir do mais próximo, com a distância ao longo da geodésica.
+func (el *GeodesicTreeStt) WithinDistance(point PointStt, distance DistanceStt) ([]GeodesicNeighborStt, error) {
+	var radius = distance.GetMeters()
+	if radius < 0 || math.IsNaN(radius) {
+		return nil, fmt.Errorf("geodesic tree: the distance must not be negative")
+	}
+
+	el.mutex.RLock()
+	defer el.mutex.RUnlock()
+
+	var neighbors = make([]GeodesicNeighborStt, 0)
+	var limit = func() float64 { return radius }
+
+	var cartesian = el.ellipsoid.toCartesian(point.Loc[1], point.Loc[0], 0)
+	geodesicTreeSearch(el.items, cartesian, limit, func(item *geodesicTreeItemStt) {
+		var neighbor = el.neighbor(point, item)
+		if neighbor.Distance.GetMeters() <= radius {
+			neighbors = append(neighbors, neighbor)
+		}
+	})
+
+	sort.SliceStable(neighbors, func(i, j int) bool {
+		return neighbors[i].Distance.GetMeters() < neighbors[j].Distance.GetMeters()
+	})
+
+	return neighbors, nil
+}
+
+// English: Number of points in the tree.
+//
+// Português: Quantidade de pontos na árvore.
+func (el *GeodesicTreeStt) Len() int {
+	el.mutex.RLock()
+	defer el.mutex.RUnlock()
+
+	return len(el.items)
+}
@@ -0,0 +1,44 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+func ExampleGeodesicTreeStt() {
+	var station = func(name string, longitude, latitude float64) *PointStt {
+		var point = &PointStt{Tag: map[string]string{"name": name}}
+		point.SetLngLatDegrees(longitude, latitude)
+		return point
+	}
+
+	// stations on both sides of the antimeridian and near the north pole
+	var tree GeodesicTreeStt
+	_ = tree.Load([]*PointStt{
+		station("east", 179.9, -17),
+		station("west", -179.9, -17),
+		station("far", 170, -17),
+		station("alert", -62.3, 82.5),
+		station("nord", -16.7, 81.6),
+	})
+
+	var point PointStt
+	point.SetLngLatDegrees(-179.95, -17)
+	nearest, _ := tree.Nearest(point, 2)
+	for _, neighbor := range nearest {
+		fmt.Printf("%v: %.1fm %.1f°\n", neighbor.Point.Tag["name"], neighbor.Distance.GetMeters(), neighbor.Bearing.GetAsDegrees())
+	}
+
+	point.SetLngLatDegrees(120, 89.9)
+	var radius DistanceStt
+	radius.SetKilometers(1000)
+	within, _ := tree.WithinDistance(point, radius)
+	for _, neighbor := range within {
+		fmt.Printf("%v: %.1fkm\n", neighbor.Point.Tag["name"], neighbor.Distance.GetKilometers())
+	}
+
+	// Output:
+	// west: 5324.3m 90.0°
+	// east: 15972.9m 270.0°
+	// alert: 848.8km
+	// nord: 946.3km
+}
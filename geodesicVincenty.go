@@ -0,0 +1,181 @@
+package iotmaker_geo_osm
+
+import (
+	"errors"
+	"math"
+)
+
+// number of iterations before Vincenty's formulae are considered not to converge
+const vincentyMaxIterations = 200
+
+// English: error returned when Vincenty's formulae do not converge, as for nearly antipodal points
+//
+// Português: erro devolvido quando as fórmulas de Vincenty não convergem, como para pontos quase antípodas
+var ErrVincentyNotConverged = errors.New("vincenty: the formula did not converge, the points are nearly antipodal")
+
+// English: Distance between two points along the geodesic of the ellipsoid of the datum selected by SetDatum(), with
+// the inverse formula of Vincenty, accurate to about 0.5 mm. Also returns the azimuth at point A towards B and the back azimuth, at point B
+// towards A, in degrees between 0 and 360.
+//
+// The formula does not converge for nearly antipodal points, when ErrVincentyNotConverged is returned; use
+// GeodesicInverseKarney() in this case.
+//
+// Português: Distância entre dois pontos ao longo da geodésica do elipsoide do datum selecionado por SetDatum(), com a
+// fórmula inversa de Vincenty, com precisão de cerca de 0,5 mm. Também devolve o azimute no ponto A em direção a B e o azimute reverso, no ponto
+// B em direção a A, em graus entre 0 e 360.
+//
+// A fórmula não converge para pontos quase antípodas, quando ErrVincentyNotConverged é devolvido; use
+// GeodesicInverseKarney() neste caso.
+func GeodesicInverseVincenty(pointAAStt, pointBAStt PointStt) (DistanceStt, AngleStt, AngleStt, error) {
+	return GetDatum().Ellipsoid.GeodesicInverseVincenty(pointAAStt, pointBAStt)
+}
+
+// English: Same as GeodesicInverseVincenty(), on this ellipsoid.
+//
+// Português: O mesmo que GeodesicInverseVincenty(), neste elipsoide.
+func (el EllipsoidStt) GeodesicInverseVincenty(pointAAStt, pointBAStt PointStt) (DistanceStt, AngleStt, AngleStt, error) {
+	var a = el.Major
+	var b = el.Minor()
+	var f = el.Flattening
+
+	var lambdaL = DegreesToRadians(geodesicAngNormalize(pointBAStt.Loc[0] - pointAAStt.Loc[0]))
+	var tanU1 = (1 - f) * math.Tan(DegreesToRadians(pointAAStt.Loc[1]))
+	var tanU2 = (1 - f) * math.Tan(DegreesToRadians(pointBAStt.Loc[1]))
+	var cosU1 = 1 / math.Sqrt(1+tanU1*tanU1)
+	var sinU1 = tanU1 * cosU1
+	var cosU2 = 1 / math.Sqrt(1+tanU2*tanU2)
+	var sinU2 = tanU2 * cosU2
+
+	var lambda = lambdaL
+	var sinLambda, cosLambda, sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+	var converged = false
+
+	for i := 0; i != vincentyMaxIterations; i += 1 {
+		sinLambda, cosLambda = math.Sin(lambda), math.Cos(lambda)
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+
+		if sinSigma == 0 {
+			// coincident points
+			distance, azimuth, backAzimuth := geodesicResult(0, 0, 180)
+			return distance, azimuth, backAzimuth, nil
+		}
+
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+
+		var sinAlpha = cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+
+		// on the equator cos²α is zero
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+
+		var c = f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+		var lambdaP = lambda
+		lambda = lambdaL + (1-c)*f*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+		if math.Abs(lambda) > math.Pi {
+			break
+		}
+
+		if math.Abs(lambda-lambdaP) < 1e-12 {
+			converged = true
+			break
+		}
+	}
+
+	if converged == false {
+		var distance DistanceStt
+		var angle AngleStt
+		return distance, angle, angle, ErrVincentyNotConverged
+	}
+
+	var uSq = cosSqAlpha * (a*a - b*b) / (b * b)
+	var A = 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	var B = uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	var deltaSigma = B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+	var s = b * A * (sigma - deltaSigma)
+	var alpha1 = math.Atan2(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+	var alpha2 = math.Atan2(cosU1*sinLambda, -sinU1*cosU2+cosU1*sinU2*cosLambda)
+
+	distance, azimuth, backAzimuth := geodesicResult(s, RadiansToDegrees(alpha1), RadiansToDegrees(alpha2))
+
+	return distance, azimuth, backAzimuth, nil
+}
+
+// English: Point at the given distance and azimuth from point A along the geodesic of the ellipsoid of the datum
+// selected by SetDatum(), with the direct formula of Vincenty. Also returns the back azimuth, at the destination towards A, in degrees between 0 and
+// 360.
+//
+// Português: Ponto na distância e azimute dados a partir do ponto A ao longo da geodésica do elipsoide do datum
+// selecionado por SetDatum(), com a fórmula direta de Vincenty. Também devolve o azimute reverso, no destino em direção a A, em graus entre 0 e 360.
+func GeodesicDirectVincenty(pointAStt PointStt, distanceAStt DistanceStt, angleAStt AngleStt) (PointStt, AngleStt, error) {
+	return GetDatum().Ellipsoid.GeodesicDirectVincenty(pointAStt, distanceAStt, angleAStt)
+}
+
+// English: Same as GeodesicDirectVincenty(), on this ellipsoid.
+//
+// Português: O mesmo que GeodesicDirectVincenty(), neste elipsoide.
+func (el EllipsoidStt) GeodesicDirectVincenty(pointAStt PointStt, distanceAStt DistanceStt, angleAStt AngleStt) (PointStt, AngleStt, error) {
+	var returnLStt PointStt
+	var backAzimuth AngleStt
+
+	var a = el.Major
+	var b = el.Minor()
+	var f = el.Flattening
+	var s = distanceAStt.GetMeters()
+
+	var sinAlpha1, cosAlpha1 = math.Sin(angleAStt.GetAsRadians()), math.Cos(angleAStt.GetAsRadians())
+	var tanU1 = (1 - f) * math.Tan(DegreesToRadians(pointAStt.Loc[1]))
+	var cosU1 = 1 / math.Sqrt(1+tanU1*tanU1)
+	var sinU1 = tanU1 * cosU1
+
+	var sigma1 = math.Atan2(tanU1, cosAlpha1)
+	var sinAlpha = cosU1 * sinAlpha1
+	var cosSqAlpha = 1 - sinAlpha*sinAlpha
+	var uSq = cosSqAlpha * (a*a - b*b) / (b * b)
+	var A = 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	var B = uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+
+	var sigma = s / (b * A)
+	var sinSigma, cosSigma, cos2SigmaM float64
+	var converged = false
+
+	for i := 0; i != vincentyMaxIterations; i += 1 {
+		cos2SigmaM = math.Cos(2*sigma1 + sigma)
+		sinSigma, cosSigma = math.Sin(sigma), math.Cos(sigma)
+
+		var deltaSigma = B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+			B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+		var sigmaP = sigma
+		sigma = s/(b*A) + deltaSigma
+
+		if math.Abs(sigma-sigmaP) < 1e-12 {
+			converged = true
+			break
+		}
+	}
+
+	if converged == false {
+		return returnLStt, backAzimuth, ErrVincentyNotConverged
+	}
+
+	cos2SigmaM = math.Cos(2*sigma1 + sigma)
+	sinSigma, cosSigma = math.Sin(sigma), math.Cos(sigma)
+
+	var x = sinU1*sinSigma - cosU1*cosSigma*cosAlpha1
+	var latitude = math.Atan2(sinU1*cosSigma+cosU1*sinSigma*cosAlpha1, (1-f)*math.Hypot(sinAlpha, x))
+	var lambda = math.Atan2(sinSigma*sinAlpha1, cosU1*cosSigma-sinU1*sinSigma*cosAlpha1)
+	var c = f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+	var lambdaL = lambda - (1-c)*f*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+	var alpha2 = math.Atan2(sinAlpha, -x)
+
+	returnLStt.SetLngLatDegrees(geodesicAngNormalize(pointAStt.Loc[0]+RadiansToDegrees(lambdaL)), RadiansToDegrees(latitude))
+
+	return returnLStt, geodesicBackAzimuth(RadiansToDegrees(alpha2)), nil
+}
@@ -0,0 +1,81 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"sync"
+)
+
+func ExampleGeodesicInverseKarney() {
+	// Flinders Peak and Buninyong, the example of Vincenty's paper
+	var flindersPeak, buninyong PointStt
+	flindersPeak.SetLngLatDegrees(144+25/60.0+29.52440/3600, -(37 + 57/60.0 + 3.72030/3600))
+	buninyong.SetLngLatDegrees(143+55/60.0+35.38390/3600, -(37 + 39/60.0 + 10.15610/3600))
+
+	distance, azimuth, backAzimuth := GeodesicInverseKarney(flindersPeak, buninyong)
+	fmt.Printf("karney: %.3f m, %.5f°, %.5f°\n", distance.GetMeters(), azimuth.GetAsDegrees(), backAzimuth.GetAsDegrees())
+
+	distance, azimuth, backAzimuth, err := GeodesicInverseVincenty(flindersPeak, buninyong)
+	fmt.Printf("vincenty: %.3f m, %.5f°, %.5f°, error: %v\n", distance.GetMeters(), azimuth.GetAsDegrees(), backAzimuth.GetAsDegrees(), err)
+
+	destination, backAzimuth := GeodesicDirectKarney(flindersPeak, distance, azimuth)
+	fmt.Printf("destination: %.8f %.8f, %.5f°\n", destination.Loc[0], destination.Loc[1], backAzimuth.GetAsDegrees())
+
+	// nearly antipodal points, where Vincenty's formula does not converge
+	var pointA, pointB PointStt
+	pointA.SetLngLatDegrees(0, 0)
+	pointB.SetLngLatDegrees(179.7, 0.5)
+
+	distance, azimuth, _ = GeodesicInverseKarney(pointA, pointB)
+	fmt.Printf("karney: %.3f m, %.5f°\n", distance.GetMeters(), azimuth.GetAsDegrees())
+
+	_, _, _, err = GeodesicInverseVincenty(pointA, pointB)
+	fmt.Printf("vincenty: %v\n", err)
+
+	// Output:
+	// karney: 54972.271 m, 306.86816°, 127.17363°
+	// vincenty: 54972.271 m, 306.86816°, 127.17363°, error: <nil>
+	// destination: 143.92649553 -37.65282114, 127.17363°
+	// karney: 19944127.421 m, 15.55688°
+	// vincenty: vincenty: the formula did not converge, the points are nearly antipodal
+}
+
+func ExampleSetDistanceModel() {
+	SetDistanceModel(DISTANCE_MODEL_KARNEY)
+	defer SetDistanceModel(DISTANCE_MODEL_SPHERICAL)
+
+	// one degree of the equator
+	var way = WayStt{}
+	way.AddLngLatDegrees(0, 0)
+	way.AddLngLatDegrees(1, 0)
+	way.Init()
+
+	fmt.Printf("%v: %.3f m, %.1f°\n", GetDistanceModel(), way.DistanceTotal.GetMeters(), way.Angle[0].GetAsDegrees())
+
+	// Output:
+	// Karney: 111319.491 m, 90.0°
+}
+
+func ExampleGetDistanceModel() {
+	// the model can be selected while other goroutines measure the ways
+	var group sync.WaitGroup
+	for k := 0; k != 4; k += 1 {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			for i := 0; i != 100; i += 1 {
+				SetDistanceModel(DISTANCE_MODEL_SPHERICAL)
+
+				var way = WayStt{}
+				way.AddLngLatDegrees(0, 0)
+				way.AddLngLatDegrees(1, 0)
+				way.Init()
+			}
+		}()
+	}
+	group.Wait()
+
+	fmt.Printf("%v\n", GetDistanceModel())
+
+	// Output:
+	// Spherical
+}
@@ -0,0 +1,303 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	geohashAlphabet     = "0123456789bcdefghjkmnpqrstuvwxyz"
+	geohashMaxPrecision = 12
+)
+
+// English: Geohash of the point, with the number of characters given by the precision, between 1 and 12. Values out
+// of these limits are taken as the nearest limit.
+//
+// Português: Geohash do ponto, com o número de caracteres dado pela precisão, entre 1 e 12. Valores fora destes
+// limites são tomados como o limite mais próximo.
+func (el *PointStt) Geohash(precision int) string {
+	if precision < 1 {
+		precision = 1
+	} else if precision > geohashMaxPrecision {
+		precision = geohashMaxPrecision
+	}
+
+	var longitude = geodesicAngNormalize(el.Loc[0])
+	var latitude = el.Loc[1]
+	var lngMin, lngMax = -180.0, 180.0
+	var latMin, latMax = -90.0, 90.0
+
+	var hash = make([]byte, precision)
+	var even = true
+
+	for k := range hash {
+		var index = 0
+		for bit := 0; bit != 5; bit += 1 {
+			index <<= 1
+
+			if even {
+				var middle = (lngMin + lngMax) / 2
+				if longitude >= middle {
+					index |= 1
+					lngMin = middle
+				} else {
+					lngMax = middle
+				}
+			} else {
+				var middle = (latMin + latMax) / 2
+				if latitude >= middle {
+					index |= 1
+					latMin = middle
+				} else {
+					latMax = middle
+				}
+			}
+
+			even = !even
+		}
+
+		hash[k] = geohashAlphabet[index]
+	}
+
+	return string(hash)
+}
+
+// English: Box of the cell of the geohash, in degrees.
+//
+// Português: Caixa da célula do geohash, em graus.
+func GeohashDecode(hash string) (BoxStt, error) {
+	var box BoxStt
+	var lngMin, lngMax = -180.0, 180.0
+	var latMin, latMax = -90.0, 90.0
+
+	if len(hash) == 0 || len(hash) > geohashMaxPrecision {
+		return box, fmt.Errorf("geohash: '%v' must have between 1 and %v characters", hash, geohashMaxPrecision)
+	}
+
+	var even = true
+	for _, character := range strings.ToLower(hash) {
+		var index = strings.IndexRune(geohashAlphabet, character)
+		if index == -1 {
+			return box, fmt.Errorf("geohash: invalid character '%c' in '%v'", character, hash)
+		}
+
+		for bit := 4; bit >= 0; bit -= 1 {
+			var set = index>>uint(bit)&1 == 1
+
+			if even {
+				var middle = (lngMin + lngMax) / 2
+				if set {
+					lngMin = middle
+				} else {
+					lngMax = middle
+				}
+			} else {
+				var middle = (latMin + latMax) / 2
+				if set {
+					latMin = middle
+				} else {
+					latMax = middle
+				}
+			}
+
+			even = !even
+		}
+	}
+
+	box.BottomLeft.SetLngLatDegrees(lngMin, latMin)
+	box.UpperRight.SetLngLatDegrees(lngMax, latMax)
+
+	return box, nil
+}
+
+// English: Geohash of the same precision next to the geohash in the direction. The longitude goes around the
+// antimeridian and an empty string is returned beyond the poles.
+//
+// Português: Geohash da mesma precisão ao lado do geohash na direção. A longitude dá a volta no antimeridiano e uma
+// string vazia é devolvida além dos polos.
+func GeohashNeighbor(hash string, direction Direction) (string, error) {
+	box, err := GeohashDecode(hash)
+	if err != nil {
+		return "", err
+	}
+
+	if direction < DIRECTION_NORTH || direction > DIRECTION_NORTH_WEST {
+		return "", fmt.Errorf("geohash: invalid direction %v", int(direction))
+	}
+
+	var width = box.UpperRight.Loc[0] - box.BottomLeft.Loc[0]
+	var height = box.UpperRight.Loc[1] - box.BottomLeft.Loc[1]
+
+	// the center of the neighbor cell
+	var longitude = (box.BottomLeft.Loc[0]+box.UpperRight.Loc[0])/2 + directionSteps[direction][0]*width
+	var latitude = (box.BottomLeft.Loc[1]+box.UpperRight.Loc[1])/2 + directionSteps[direction][1]*height
+
+	if latitude > 90 || latitude < -90 {
+		return "", nil
+	}
+
+	var neighbor PointStt
+	neighbor.SetLngLatDegrees(geodesicAngNormalize(longitude), latitude)
+
+	return neighbor.Geohash(len(hash)), nil
+}
+
+// English: The eight geohashes around the geohash, in the order of the constants of Direction, from DIRECTION_NORTH
+// clockwise. Beyond the poles the geohash is an empty string.
+//
+// Português: Os oito geohashes em volta do geohash, na ordem das constantes de Direction, de DIRECTION_NORTH em
+// sentido horário. Além dos polos o geohash é uma string vazia.
+func GeohashNeighbors(hash string) ([8]string, error) {
+	var neighbors [8]string
+
+	for direction := DIRECTION_NORTH; direction <= DIRECTION_NORTH_WEST; direction += 1 {
+		neighbor, err := GeohashNeighbor(hash, direction)
+		if err != nil {
+			return neighbors, err
+		}
+
+		neighbors[direction] = neighbor
+	}
+
+	return neighbors, nil
+}
+
+// geohashOverlap is true when the interval a overlaps the interval b with a positive length, or contains b when b is
+// a single value.
+func geohashOverlap(aMin, aMax, bMin, bMax float64) bool {
+	if bMin == bMax {
+		return aMin <= bMin && bMin < aMax
+	}
+
+	return aMin < bMax && bMin < aMax
+}
+
+// geohashCover divides the cells that the classify function finds on the border of the shape until one more division
+// goes over maxCells. The cells inside the shape are not divided, so the cover is made of cells of several sizes.
+func geohashCover(maxCells int, classify func(box BoxStt) (intersects, contains bool)) ([]string, error) {
+	if maxCells < 1 {
+		return nil, fmt.Errorf("geohash: the cover must have at least one cell")
+	}
+
+	var cover []string
+	var inside = make([]string, 0)
+	var border = []string{""}
+
+	for precision := 1; precision <= geohashMaxPrecision && len(border) != 0; precision += 1 {
+		var nextInside = append(make([]string, 0, len(inside)), inside...)
+		var nextBorder = make([]string, 0)
+
+		for _, parent := range border {
+			for k := range geohashAlphabet {
+				var hash = parent + geohashAlphabet[k:k+1]
+
+				box, _ := GeohashDecode(hash)
+				intersects, contains := classify(box)
+				if contains {
+					nextInside = append(nextInside, hash)
+				} else if intersects {
+					nextBorder = append(nextBorder, hash)
+				}
+			}
+		}
+
+		if len(nextInside)+len(nextBorder) > maxCells {
+			break
+		}
+
+		inside, border = nextInside, nextBorder
+		cover = append(append(make([]string, 0, len(inside)+len(border)), inside...), border...)
+	}
+
+	if cover == nil {
+		return nil, fmt.Errorf("geohash: the cover needs more than %v cells at the precision of one character", maxCells)
+	}
+
+	sort.Strings(cover)
+
+	return cover, nil
+}
+
+// English: The smallest set of geohashes, of several precisions and at most maxCells, that covers the box. The box
+// crosses the antimeridian when the longitude of the bottom left corner is greater than the one of the upper right
+// corner.
+//
+// Português: O menor conjunto de geohashes, de várias precisões e no máximo maxCells, que cobre a caixa. A caixa
+// cruza o antimeridiano quando a longitude do canto inferior esquerdo é maior que a do canto superior direito.
+func GeohashCoverBox(box BoxStt, maxCells int) ([]string, error) {
+	var latMin, latMax = box.BottomLeft.Loc[1], box.UpperRight.Loc[1]
+	var longitudes = [][2]float64{{box.BottomLeft.Loc[0], box.UpperRight.Loc[0]}}
+	if box.BottomLeft.Loc[0] > box.UpperRight.Loc[0] {
+		longitudes = [][2]float64{{box.BottomLeft.Loc[0], 180}, {-180, box.UpperRight.Loc[0]}}
+	}
+
+	return geohashCover(maxCells, func(cell BoxStt) (bool, bool) {
+		if !geohashOverlap(cell.BottomLeft.Loc[1], cell.UpperRight.Loc[1], latMin, latMax) {
+			return false, false
+		}
+
+		for _, longitude := range longitudes {
+			if geohashOverlap(cell.BottomLeft.Loc[0], cell.UpperRight.Loc[0], longitude[0], longitude[1]) {
+				var contains = cell.BottomLeft.Loc[0] >= longitude[0] && cell.UpperRight.Loc[0] <= longitude[1] &&
+					cell.BottomLeft.Loc[1] >= latMin && cell.UpperRight.Loc[1] <= latMax
+
+				return true, contains
+			}
+		}
+
+		return false, false
+	})
+}
+
+// English: The smallest set of geohashes, of several precisions and at most maxCells, that covers the polygon, holes
+// included. The cells without a border of the polygon are tested with PointInPolygon().
+//
+// Português: O menor conjunto de geohashes, de várias precisões e no máximo maxCells, que cobre o polígono, buracos
+// incluídos. As células sem uma borda do polígono são testadas com PointInPolygon().
+func GeohashCoverPolygon(polygon *PolygonStt, maxCells int) ([]string, error) {
+	if len(polygon.PointsList) < 3 {
+		return nil, fmt.Errorf("geohash: the polygon must have at least three points")
+	}
+
+	var rings = [][]PointStt{polygon.PointsList}
+	for k := range polygon.Inner {
+		rings = append(rings, polygon.Inner[k].PointsList)
+	}
+
+	return geohashCover(maxCells, func(cell BoxStt) (bool, bool) {
+		var minimum = cell.BottomLeft.Loc
+		var maximum = cell.UpperRight.Loc
+		var corners = [4][2]float64{
+			{minimum[0], minimum[1]},
+			{maximum[0], minimum[1]},
+			{maximum[0], maximum[1]},
+			{minimum[0], maximum[1]},
+		}
+
+		for _, ring := range rings {
+			for i := range ring {
+				var pointA = ring[i].Loc
+				var pointB = ring[(i+1)%len(ring)].Loc
+
+				if pointA[0] > minimum[0] && pointA[0] < maximum[0] && pointA[1] > minimum[1] && pointA[1] < maximum[1] {
+					return true, false
+				}
+
+				for k := range corners {
+					if segmentsIntersect(pointA, pointB, corners[k], corners[(k+1)%4]) {
+						return true, false
+					}
+				}
+			}
+		}
+
+		// no border of the polygon inside the cell, the cell is all inside or all outside
+		var center PointStt
+		center.SetLngLatDegrees((minimum[0]+maximum[0])/2, (minimum[1]+maximum[1])/2)
+
+		var inside = polygon.PointInPolygon(center)
+
+		return inside, inside
+	})
+}
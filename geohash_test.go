@@ -0,0 +1,64 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+func ExamplePointStt_Geohash() {
+	var point PointStt
+	point.SetLngLatDegrees(10.40744, 57.64911)
+	fmt.Printf("geohash: %v\n", point.Geohash(11))
+
+	box, _ := GeohashDecode("ezs42")
+	fmt.Printf("box: %.5f %.5f, %.5f %.5f\n", box.BottomLeft.Loc[0], box.BottomLeft.Loc[1], box.UpperRight.Loc[0], box.UpperRight.Loc[1])
+
+	neighbors, _ := GeohashNeighbors("ezs42")
+	for direction, neighbor := range neighbors {
+		fmt.Printf("%v: %v\n", Direction(direction), neighbor)
+	}
+
+	// at the north pole there is no neighbor to the north
+	neighbors, _ = GeohashNeighbors("zzz")
+	fmt.Printf("pole: %q\n", neighbors)
+
+	// Output:
+	// geohash: u4pruydqqvj
+	// box: -5.62500 42.58301, -5.58105 42.62695
+	// N: ezs48
+	// NE: ezs49
+	// E: ezs43
+	// SE: ezs41
+	// S: ezs40
+	// SW: ezefp
+	// W: ezefr
+	// NW: ezefx
+	// pole: ["" "" "bpb" "bp8" "zzx" "zzw" "zzy" ""]
+}
+
+func ExampleGeohashCoverPolygon() {
+	var box BoxStt
+	box.BottomLeft.SetLngLatDegrees(-47.95, -15.85)
+	box.UpperRight.SetLngLatDegrees(-47.80, -15.70)
+
+	cover, _ := GeohashCoverBox(box, 16)
+	fmt.Printf("box: %v\n", cover)
+
+	// a triangle with a cover of several precisions
+	var polygon PolygonStt
+	polygon.AddLngLatDegrees(-48, -16)
+	polygon.AddLngLatDegrees(-47, -16)
+	polygon.AddLngLatDegrees(-47.5, -15)
+	polygon.Init()
+
+	cover, _ = GeohashCoverPolygon(&polygon, 1000)
+
+	var precisions = make(map[int]int)
+	for _, hash := range cover {
+		precisions[len(hash)] += 1
+	}
+	fmt.Printf("polygon: %v cells, %v\n", len(cover), precisions)
+
+	// Output:
+	// box: [6vjv 6vjy 6vnj 6vnn]
+	// polygon: 219 cells, map[4:3 5:216]
+}
@@ -0,0 +1,1056 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// English: Index of a cell of the hexagonal grid of H3, with the same numbers of the reference library of H3. The 122
+// cells of resolution 0, twelve of them pentagons, are divided by seven at each resolution, up to 15.
+//
+// Português: Índice de uma célula da grade hexagonal do H3, com os mesmos números da biblioteca de referência do H3.
+// As 122 células da resolução 0, doze delas pentágonos, são divididas por sete a cada resolução, até 15.
+type H3Index uint64
+
+const (
+	H3_MAX_RESOLUTION = 15
+
+	h3NumIcosaFaces   = 20
+	h3NumBaseCells    = 122
+	h3InvalidBaseCell = 127
+
+	h3ModeOffset     = 59
+	h3ResOffset      = 52
+	h3BaseCellOffset = 45
+	h3DigitBits      = 3
+	h3CellMode       = 1
+
+	// an index of resolution 0 with all the digits set to 7, unused
+	h3Init = 35184372088831
+
+	// directions of the digits
+	h3CenterDigit  = 0
+	h3KAxesDigit   = 1
+	h3JAxesDigit   = 2
+	h3JKAxesDigit  = 3
+	h3IAxesDigit   = 4
+	h3IKAxesDigit  = 5
+	h3IJAxesDigit  = 6
+	h3InvalidDigit = 7
+
+	// edges of the faces of the icosahedron
+	h3IJ = 1
+	h3KI = 2
+	h3JK = 3
+
+	h3Sqrt3By2           = 0.8660254037844386467637231707529361834714
+	h3Sqrt7              = 2.6457513110645905905016157536392604257102
+	h3Ap7RotRads         = 0.333473172251832115336090755351601070065900389
+	h3Res0UGnomonic      = 0.38196601125010500003
+	h3Epsilon            = 0.0000000000000001
+	h3EarthRadiusKm      = 6371.007180918475
+	h3FloatEpsilon       = 1.1920928955078125e-07
+	h3DoubleEpsilon      = 2.220446049250313e-16
+	h3NoOverage          = 0
+	h3FaceEdgeOverage    = 1
+	h3NewFaceOverage     = 2
+	h3HexagonVertices    = 6
+	h3PentagonVertices   = 5
+	h3MaxBaseCellIJK     = 2
+	h3SubstrateScale     = 3
+	h3PentagonLeading4   = 4
+	h3PentagonLeading5   = 5
+	h3PolarPentagonNorth = 4
+	h3PolarPentagonSouth = 117
+)
+
+// h3CoordIJKStt is a position in the coordinates of three axes, 120° apart, of the hexagons of a face.
+type h3CoordIJKStt struct {
+	i, j, k int
+}
+
+// h3FaceOrientIJKStt is a face and the translation and rotations to its coordinates from the ones of a neighbor face.
+type h3FaceOrientIJKStt struct {
+	face      int
+	translate h3CoordIJKStt
+	ccwRot60  int
+}
+
+type h3BaseCellRotationStt struct {
+	baseCell int
+	ccwRot60 int
+}
+
+type h3BaseCellDataStt struct {
+	face         int
+	coord        h3CoordIJKStt
+	pentagon     bool
+	cwOffsetPent [2]int
+}
+
+// unit vectors of the seven digits
+var h3UnitVectors = [7]h3CoordIJKStt{{0, 0, 0}, {0, 0, 1}, {0, 1, 0}, {0, 1, 1}, {1, 0, 0}, {1, 0, 1}, {1, 1, 0}}
+
+func h3IsClassIII(resolution int) bool {
+	return resolution%2 == 1
+}
+
+func (el *h3CoordIJKStt) add(other h3CoordIJKStt) {
+	el.i += other.i
+	el.j += other.j
+	el.k += other.k
+}
+
+func (el *h3CoordIJKStt) sub(other h3CoordIJKStt) {
+	el.i -= other.i
+	el.j -= other.j
+	el.k -= other.k
+}
+
+func (el *h3CoordIJKStt) scale(factor int) {
+	el.i *= factor
+	el.j *= factor
+	el.k *= factor
+}
+
+// normalize leaves the coordinates without negative values and with at least one zero.
+func (el *h3CoordIJKStt) normalize() {
+	if el.i < 0 {
+		el.j -= el.i
+		el.k -= el.i
+		el.i = 0
+	}
+
+	if el.j < 0 {
+		el.i -= el.j
+		el.k -= el.j
+		el.j = 0
+	}
+
+	if el.k < 0 {
+		el.i -= el.k
+		el.j -= el.k
+		el.k = 0
+	}
+
+	var minimum = el.i
+	if el.j < minimum {
+		minimum = el.j
+	}
+	if el.k < minimum {
+		minimum = el.k
+	}
+
+	if minimum > 0 {
+		el.i -= minimum
+		el.j -= minimum
+		el.k -= minimum
+	}
+}
+
+// transform replaces the coordinates by the sum of the vectors of the axes scaled by them.
+func (el *h3CoordIJKStt) transform(iVector, jVector, kVector h3CoordIJKStt) {
+	iVector.scale(el.i)
+	jVector.scale(el.j)
+	kVector.scale(el.k)
+
+	*el = iVector
+	el.add(jVector)
+	el.add(kVector)
+	el.normalize()
+}
+
+// upAp7 moves to the coordinates of the parent resolution of class II, rotated counterclockwise.
+func (el *h3CoordIJKStt) upAp7() {
+	var i = el.i - el.k
+	var j = el.j - el.k
+
+	*el = h3CoordIJKStt{int(math.Round(float64(3*i-j) / 7)), int(math.Round(float64(i+2*j) / 7)), 0}
+	el.normalize()
+}
+
+// upAp7r moves to the coordinates of the parent resolution of class III, rotated clockwise.
+func (el *h3CoordIJKStt) upAp7r() {
+	var i = el.i - el.k
+	var j = el.j - el.k
+
+	*el = h3CoordIJKStt{int(math.Round(float64(2*i+j) / 7)), int(math.Round(float64(3*j-i) / 7)), 0}
+	el.normalize()
+}
+
+func (el *h3CoordIJKStt) downAp7() {
+	el.transform(h3CoordIJKStt{3, 0, 1}, h3CoordIJKStt{1, 3, 0}, h3CoordIJKStt{0, 1, 3})
+}
+
+func (el *h3CoordIJKStt) downAp7r() {
+	el.transform(h3CoordIJKStt{3, 1, 0}, h3CoordIJKStt{0, 3, 1}, h3CoordIJKStt{1, 0, 3})
+}
+
+func (el *h3CoordIJKStt) downAp3() {
+	el.transform(h3CoordIJKStt{2, 0, 1}, h3CoordIJKStt{1, 2, 0}, h3CoordIJKStt{0, 1, 2})
+}
+
+func (el *h3CoordIJKStt) downAp3r() {
+	el.transform(h3CoordIJKStt{2, 1, 0}, h3CoordIJKStt{0, 2, 1}, h3CoordIJKStt{1, 0, 2})
+}
+
+func (el *h3CoordIJKStt) rotate60ccw() {
+	el.transform(h3CoordIJKStt{1, 1, 0}, h3CoordIJKStt{0, 1, 1}, h3CoordIJKStt{1, 0, 1})
+}
+
+func (el *h3CoordIJKStt) rotate60cw() {
+	el.transform(h3CoordIJKStt{1, 0, 1}, h3CoordIJKStt{1, 1, 0}, h3CoordIJKStt{0, 1, 1})
+}
+
+func (el *h3CoordIJKStt) neighbor(digit int) {
+	if digit > h3CenterDigit && digit < h3InvalidDigit {
+		el.add(h3UnitVectors[digit])
+		el.normalize()
+	}
+}
+
+// toDigit is the digit of the unit vector, or h3InvalidDigit.
+func (el h3CoordIJKStt) toDigit() int {
+	el.normalize()
+
+	for digit := h3CenterDigit; digit < h3InvalidDigit; digit += 1 {
+		if el == h3UnitVectors[digit] {
+			return digit
+		}
+	}
+
+	return h3InvalidDigit
+}
+
+func (el h3CoordIJKStt) toHex2d() (float64, float64) {
+	var i = el.i - el.k
+	var j = el.j - el.k
+
+	return float64(i) - 0.5*float64(j), float64(j) * h3Sqrt3By2
+}
+
+// h3Hex2dToCoordIJK is the hexagon that contains the position of the plane of the face.
+func h3Hex2dToCoordIJK(x, y float64) h3CoordIJKStt {
+	var h h3CoordIJKStt
+
+	var a1 = math.Abs(x)
+	var a2 = math.Abs(y)
+
+	// first do a reverse conversion
+	var x2 = a2 / h3Sqrt3By2
+	var x1 = a1 + x2/2
+
+	// check if we have the center of a hex
+	var m1 = int(x1)
+	var m2 = int(x2)
+
+	// otherwise round correctly
+	var r1 = x1 - float64(m1)
+	var r2 = x2 - float64(m2)
+
+	if r1 < 0.5 {
+		if r1 < 1.0/3.0 {
+			h.i = m1
+			h.j = m2
+			if r2 >= (1+r1)/2 {
+				h.j = m2 + 1
+			}
+		} else {
+			h.j = m2
+			if r2 >= 1-r1 {
+				h.j = m2 + 1
+			}
+
+			h.i = m1
+			if 1-r1 <= r2 && r2 < 2*r1 {
+				h.i = m1 + 1
+			}
+		}
+	} else {
+		if r1 < 2.0/3.0 {
+			h.j = m2
+			if r2 >= 1-r1 {
+				h.j = m2 + 1
+			}
+
+			h.i = m1 + 1
+			if 2*r1-1 < r2 && r2 < 1-r1 {
+				h.i = m1
+			}
+		} else {
+			h.i = m1 + 1
+			h.j = m2
+			if r2 >= r1/2 {
+				h.j = m2 + 1
+			}
+		}
+	}
+
+	// now fold across the axes if necessary
+	if x < 0 {
+		if h.j%2 == 0 {
+			var axis = h.j / 2
+			h.i = h.i - 2*(h.i-axis)
+		} else {
+			var axis = (h.j + 1) / 2
+			h.i = h.i - (2*(h.i-axis) + 1)
+		}
+	}
+
+	if y < 0 {
+		h.i = h.i - (2*h.j+1)/2
+		h.j = -h.j
+	}
+
+	h.normalize()
+
+	return h
+}
+
+// h3PositiveAngle is the angle, in radians, between 0 and 2π.
+func h3PositiveAngle(radians float64) float64 {
+	var angle = radians
+	if radians < 0 {
+		angle = radians + 2*math.Pi
+	}
+	if radians >= 2*math.Pi {
+		angle -= 2 * math.Pi
+	}
+
+	return angle
+}
+
+func h3Azimuth(latitude1, longitude1, latitude2, longitude2 float64) float64 {
+	return math.Atan2(math.Cos(latitude2)*math.Sin(longitude2-longitude1),
+		math.Cos(latitude1)*math.Sin(latitude2)-math.Sin(latitude1)*math.Cos(latitude2)*math.Cos(longitude2-longitude1))
+}
+
+func h3ConstrainLongitude(longitude float64) float64 {
+	for longitude > math.Pi {
+		longitude -= 2 * math.Pi
+	}
+	for longitude < -math.Pi {
+		longitude += 2 * math.Pi
+	}
+
+	return longitude
+}
+
+// h3AzimuthDistance is the point at the azimuth and the distance, in radians, from the point.
+func h3AzimuthDistance(latitude, longitude, azimuth, distance float64) (float64, float64) {
+	if distance < h3Epsilon {
+		return latitude, longitude
+	}
+
+	azimuth = h3PositiveAngle(azimuth)
+
+	var latitude2, longitude2 float64
+
+	// due north or south
+	if azimuth < h3Epsilon || math.Abs(azimuth-math.Pi) < h3Epsilon {
+		if azimuth < h3Epsilon {
+			latitude2 = latitude + distance
+		} else {
+			latitude2 = latitude - distance
+		}
+
+		if math.Abs(latitude2-math.Pi/2) < h3Epsilon {
+			return math.Pi / 2, 0
+		} else if math.Abs(latitude2+math.Pi/2) < h3Epsilon {
+			return -math.Pi / 2, 0
+		}
+
+		return latitude2, h3ConstrainLongitude(longitude)
+	}
+
+	var sinLatitude = math.Sin(latitude)*math.Cos(distance) + math.Cos(latitude)*math.Sin(distance)*math.Cos(azimuth)
+	sinLatitude = math.Max(-1, math.Min(1, sinLatitude))
+	latitude2 = math.Asin(sinLatitude)
+
+	if math.Abs(latitude2-math.Pi/2) < h3Epsilon {
+		return math.Pi / 2, 0
+	} else if math.Abs(latitude2+math.Pi/2) < h3Epsilon {
+		return -math.Pi / 2, 0
+	}
+
+	var sinLongitude = math.Sin(azimuth) * math.Sin(distance) / math.Cos(latitude2)
+	var cosLongitude = (math.Cos(distance) - math.Sin(latitude)*math.Sin(latitude2)) / math.Cos(latitude) / math.Cos(latitude2)
+	sinLongitude = math.Max(-1, math.Min(1, sinLongitude))
+	cosLongitude = math.Max(-1, math.Min(1, cosLongitude))
+	longitude2 = h3ConstrainLongitude(longitude + math.Atan2(sinLongitude, cosLongitude))
+
+	return latitude2, longitude2
+}
+
+// h3GeoToHex2d is the face of the icosahedron nearest to the point, in radians, and the position on the plane of the
+// face in units of the resolution.
+func h3GeoToHex2d(latitude, longitude float64, resolution int) (int, float64, float64) {
+	var cosLatitude = math.Cos(latitude)
+	var point = [3]float64{math.Cos(longitude) * cosLatitude, math.Sin(longitude) * cosLatitude, math.Sin(latitude)}
+
+	var face = 0
+	var squareDistance = 5.0
+	for k := range h3FaceCenterPoint {
+		var dx = h3FaceCenterPoint[k][0] - point[0]
+		var dy = h3FaceCenterPoint[k][1] - point[1]
+		var dz = h3FaceCenterPoint[k][2] - point[2]
+
+		if distance := dx*dx + dy*dy + dz*dz; distance < squareDistance {
+			face = k
+			squareDistance = distance
+		}
+	}
+
+	var r = math.Acos(1 - squareDistance/2)
+	if r < h3Epsilon {
+		return face, 0, 0
+	}
+
+	// the angle counterclockwise from the axis i of the face
+	var theta = h3PositiveAngle(h3FaceAxesAzRadsCII[face][0] -
+		h3PositiveAngle(h3Azimuth(h3FaceCenterGeo[face][0], h3FaceCenterGeo[face][1], latitude, longitude)))
+
+	if h3IsClassIII(resolution) {
+		theta = h3PositiveAngle(theta - h3Ap7RotRads)
+	}
+
+	// gnomonic projection, scaled to the resolution
+	r = math.Tan(r) / h3Res0UGnomonic
+	for k := 0; k < resolution; k += 1 {
+		r *= h3Sqrt7
+	}
+
+	return face, r * math.Cos(theta), r * math.Sin(theta)
+}
+
+// h3Hex2dToGeo is the point, in radians, of the position on the plane of the face. The substrate grid is the one of
+// the vertices, three times finer.
+func h3Hex2dToGeo(x, y float64, face, resolution int, substrate bool) (float64, float64) {
+	var r = math.Hypot(x, y)
+	if r < h3Epsilon {
+		return h3FaceCenterGeo[face][0], h3FaceCenterGeo[face][1]
+	}
+
+	var theta = math.Atan2(y, x)
+
+	for k := 0; k < resolution; k += 1 {
+		r /= h3Sqrt7
+	}
+
+	if substrate {
+		r /= 3
+		if h3IsClassIII(resolution) {
+			r /= h3Sqrt7
+		}
+	}
+
+	r = math.Atan(r * h3Res0UGnomonic)
+
+	if !substrate && h3IsClassIII(resolution) {
+		theta = h3PositiveAngle(theta + h3Ap7RotRads)
+	}
+
+	theta = h3PositiveAngle(h3FaceAxesAzRadsCII[face][0] - theta)
+
+	return h3AzimuthDistance(h3FaceCenterGeo[face][0], h3FaceCenterGeo[face][1], theta, r)
+}
+
+// maxDimension is the largest coordinate of the face in the resolution of class II.
+func h3MaxDimension(resolution int) int {
+	var dimension = 2
+	for k := 0; k < resolution; k += 2 {
+		dimension *= 7
+	}
+
+	return dimension
+}
+
+// unitScale is the length of the translation to the neighbor face in the resolution of class II.
+func h3UnitScale(resolution int) int {
+	var scale = 1
+	for k := 0; k < resolution; k += 2 {
+		scale *= 7
+	}
+
+	return scale
+}
+
+// adjustOverage moves the coordinates that go beyond the face to the neighbor face, in a resolution of class II.
+func h3AdjustOverage(face *int, coord *h3CoordIJKStt, resolution int, pentagonLeading4, substrate bool) int {
+	var overage = h3NoOverage
+
+	var maxDimension = h3MaxDimension(resolution)
+	if substrate {
+		maxDimension *= h3SubstrateScale
+	}
+
+	var sum = coord.i + coord.j + coord.k
+	if substrate && sum == maxDimension {
+		return h3FaceEdgeOverage
+	}
+
+	if sum <= maxDimension {
+		return overage
+	}
+
+	overage = h3NewFaceOverage
+
+	var orientation h3FaceOrientIJKStt
+	if coord.k > 0 {
+		if coord.j > 0 {
+			orientation = h3FaceNeighbors[*face][h3JK]
+		} else {
+			orientation = h3FaceNeighbors[*face][h3KI]
+
+			// adjust for the pentagonal missing sequence
+			if pentagonLeading4 {
+				var origin = h3CoordIJKStt{maxDimension, 0, 0}
+				var tmp = *coord
+				tmp.sub(origin)
+				tmp.rotate60cw()
+				tmp.add(origin)
+				*coord = tmp
+			}
+		}
+	} else {
+		orientation = h3FaceNeighbors[*face][h3IJ]
+	}
+
+	*face = orientation.face
+
+	for k := 0; k < orientation.ccwRot60; k += 1 {
+		coord.rotate60ccw()
+	}
+
+	var scale = h3UnitScale(resolution)
+	if substrate {
+		scale *= h3SubstrateScale
+	}
+
+	var translate = orientation.translate
+	translate.scale(scale)
+	coord.add(translate)
+	coord.normalize()
+
+	if substrate && coord.i+coord.j+coord.k == maxDimension {
+		overage = h3FaceEdgeOverage
+	}
+
+	return overage
+}
+
+// English: Index of the cell of the point at the resolution, between 0 and 15. Resolutions out of these limits are
+// taken as the nearest limit.
+//
+// Português: Índice da célula do ponto na resolução, entre 0 e 15. Resoluções fora destes limites são tomadas como o
+// limite mais próximo.
+func (el *PointStt) H3Index(resolution int) H3Index {
+	if resolution < 0 {
+		resolution = 0
+	} else if resolution > H3_MAX_RESOLUTION {
+		resolution = H3_MAX_RESOLUTION
+	}
+
+	face, x, y := h3GeoToHex2d(DegreesToRadians(el.Loc[1]), DegreesToRadians(el.Loc[0]), resolution)
+
+	return h3FaceIjkToIndex(face, h3Hex2dToCoordIJK(x, y), resolution)
+}
+
+// h3FaceIjkToIndex is the index of the hexagon of the coordinates of the face.
+func h3FaceIjkToIndex(face int, coord h3CoordIJKStt, resolution int) H3Index {
+	var index = H3Index(h3Init).setMode(h3CellMode).setResolution(resolution)
+
+	// the digits, from the finest resolution up
+	for r := resolution - 1; r >= 0; r -= 1 {
+		var last = coord
+		var center h3CoordIJKStt
+
+		if h3IsClassIII(r + 1) {
+			coord.upAp7()
+			center = coord
+			center.downAp7()
+		} else {
+			coord.upAp7r()
+			center = coord
+			center.downAp7r()
+		}
+
+		last.sub(center)
+		index = index.setDigit(r+1, last.toDigit())
+	}
+
+	// coord is now the base cell in the coordinates of the face
+	if coord.i > h3MaxBaseCellIJK || coord.j > h3MaxBaseCellIJK || coord.k > h3MaxBaseCellIJK {
+		return 0
+	}
+
+	var rotation = h3FaceIjkBaseCells[face][coord.i][coord.j][coord.k]
+	index = index.setBaseCell(rotation.baseCell)
+
+	// rotate to the orientation of the base cell
+	if h3BaseCellData[rotation.baseCell].pentagon {
+		// out of the missing sub-sequence of the axis k
+		if index.leadingNonZeroDigit() == h3KAxesDigit {
+			if h3BaseCellIsCwOffset(rotation.baseCell, face) {
+				index = index.rotate60cw()
+			} else {
+				index = index.rotate60ccw()
+			}
+		}
+
+		for k := 0; k < rotation.ccwRot60; k += 1 {
+			index = index.rotatePentagon60ccw()
+		}
+	} else {
+		for k := 0; k < rotation.ccwRot60; k += 1 {
+			index = index.rotate60ccw()
+		}
+	}
+
+	return index
+}
+
+func h3BaseCellIsCwOffset(baseCell, face int) bool {
+	return h3BaseCellData[baseCell].cwOffsetPent[0] == face || h3BaseCellData[baseCell].cwOffsetPent[1] == face
+}
+
+func (e H3Index) setMode(mode int) H3Index {
+	return e&^(15<<h3ModeOffset) | H3Index(mode)<<h3ModeOffset
+}
+
+func (e H3Index) setResolution(resolution int) H3Index {
+	return e&^(15<<h3ResOffset) | H3Index(resolution)<<h3ResOffset
+}
+
+func (e H3Index) setBaseCell(baseCell int) H3Index {
+	return e&^(127<<h3BaseCellOffset) | H3Index(baseCell)<<h3BaseCellOffset
+}
+
+func (e H3Index) digit(resolution int) int {
+	return int(e>>uint((H3_MAX_RESOLUTION-resolution)*h3DigitBits)) & 7
+}
+
+func (e H3Index) setDigit(resolution, digit int) H3Index {
+	var offset = uint((H3_MAX_RESOLUTION - resolution) * h3DigitBits)
+
+	return e&^(7<<offset) | H3Index(digit)<<offset
+}
+
+func (e H3Index) mode() int {
+	return int(e>>h3ModeOffset) & 15
+}
+
+// English: Resolution of the cell, from 0 to 15.
+//
+// Português: Resolução da célula, de 0 a 15.
+func (e H3Index) Resolution() int {
+	return int(e>>h3ResOffset) & 15
+}
+
+// English: Base cell of the cell, the one of resolution 0 that contains it, from 0 to 121.
+//
+// Português: Célula base da célula, a de resolução 0 que a contém, de 0 a 121.
+func (e H3Index) BaseCell() int {
+	return int(e>>h3BaseCellOffset) & 127
+}
+
+// English: True when the cell is one of the twelve pentagons of each resolution.
+//
+// Português: Verdadeiro quando a célula é um dos doze pentágonos de cada resolução.
+func (e H3Index) IsPentagon() bool {
+	return e.BaseCell() < h3NumBaseCells && h3BaseCellData[e.BaseCell()].pentagon && e.leadingNonZeroDigit() == h3CenterDigit
+}
+
+// English: True when the number is a valid index of a cell.
+//
+// Português: Verdadeiro quando o número é um índice válido de uma célula.
+func (e H3Index) IsValid() bool {
+	if e>>63 != 0 || e.mode() != h3CellMode || (e>>56)&7 != 0 || e.BaseCell() >= h3NumBaseCells {
+		return false
+	}
+
+	var resolution = e.Resolution()
+	var foundFirstNonZeroDigit = false
+
+	for r := 1; r <= H3_MAX_RESOLUTION; r += 1 {
+		var digit = e.digit(r)
+
+		if r > resolution {
+			if digit != h3InvalidDigit {
+				return false
+			}
+			continue
+		}
+
+		if digit == h3InvalidDigit {
+			return false
+		}
+
+		// the pentagons have no sub-sequence in the direction of the axis k
+		if !foundFirstNonZeroDigit && digit != h3CenterDigit {
+			foundFirstNonZeroDigit = true
+			if h3BaseCellData[e.BaseCell()].pentagon && digit == h3KAxesDigit {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// English: Index in the hexadecimal notation of H3.
+//
+// Português: Índice na notação hexadecimal do H3.
+func (e H3Index) String() string {
+	return strconv.FormatUint(uint64(e), 16)
+}
+
+// English: Index from the hexadecimal notation of H3.
+//
+// Português: Índice a partir da notação hexadecimal do H3.
+func H3IndexFromString(text string) (H3Index, error) {
+	value, err := strconv.ParseUint(text, 16, 64)
+	if err != nil || !H3Index(value).IsValid() {
+		return 0, fmt.Errorf("h3: invalid index '%v'", text)
+	}
+
+	return H3Index(value), nil
+}
+
+func (e H3Index) leadingNonZeroDigit() int {
+	for r := 1; r <= e.Resolution(); r += 1 {
+		if digit := e.digit(r); digit != h3CenterDigit {
+			return digit
+		}
+	}
+
+	return h3CenterDigit
+}
+
+// h3RotateDigit60ccw is the digit rotated by 60° counterclockwise.
+func h3RotateDigit60ccw(digit int) int {
+	switch digit {
+	case h3KAxesDigit:
+		return h3IKAxesDigit
+	case h3IKAxesDigit:
+		return h3IAxesDigit
+	case h3IAxesDigit:
+		return h3IJAxesDigit
+	case h3IJAxesDigit:
+		return h3JAxesDigit
+	case h3JAxesDigit:
+		return h3JKAxesDigit
+	case h3JKAxesDigit:
+		return h3KAxesDigit
+	}
+
+	return digit
+}
+
+// h3RotateDigit60cw is the digit rotated by 60° clockwise.
+func h3RotateDigit60cw(digit int) int {
+	switch digit {
+	case h3KAxesDigit:
+		return h3JKAxesDigit
+	case h3JKAxesDigit:
+		return h3JAxesDigit
+	case h3JAxesDigit:
+		return h3IJAxesDigit
+	case h3IJAxesDigit:
+		return h3IAxesDigit
+	case h3IAxesDigit:
+		return h3IKAxesDigit
+	case h3IKAxesDigit:
+		return h3KAxesDigit
+	}
+
+	return digit
+}
+
+func (e H3Index) rotate60ccw() H3Index {
+	for r := 1; r <= e.Resolution(); r += 1 {
+		e = e.setDigit(r, h3RotateDigit60ccw(e.digit(r)))
+	}
+
+	return e
+}
+
+func (e H3Index) rotate60cw() H3Index {
+	for r := 1; r <= e.Resolution(); r += 1 {
+		e = e.setDigit(r, h3RotateDigit60cw(e.digit(r)))
+	}
+
+	return e
+}
+
+// rotatePentagon60ccw rotates the index of a pentagon, skipping the missing sub-sequence of the axis k.
+func (e H3Index) rotatePentagon60ccw() H3Index {
+	var foundFirstNonZeroDigit = false
+
+	for r := 1; r <= e.Resolution(); r += 1 {
+		e = e.setDigit(r, h3RotateDigit60ccw(e.digit(r)))
+
+		if !foundFirstNonZeroDigit && e.digit(r) != h3CenterDigit {
+			foundFirstNonZeroDigit = true
+
+			if e.leadingNonZeroDigit() == h3KAxesDigit {
+				e = e.rotate60ccw()
+			}
+		}
+	}
+
+	return e
+}
+
+// toFaceIjk is the face and the coordinates of the cell, on the face where its center is.
+func (e H3Index) toFaceIjk() (int, h3CoordIJKStt) {
+	var baseCell = e.BaseCell()
+	var pentagon = h3BaseCellData[baseCell].pentagon
+
+	// all the sub-sequence 5 of a pentagon is rotated
+	if pentagon && e.leadingNonZeroDigit() == h3PentagonLeading5 {
+		e = e.rotate60cw()
+	}
+
+	var face = h3BaseCellData[baseCell].face
+	var coord = h3BaseCellData[baseCell].coord
+	var resolution = e.Resolution()
+
+	// the hierarchy of the center of a base cell is all on its face
+	var possibleOverage = pentagon || (resolution != 0 && coord != h3CoordIJKStt{})
+
+	for r := 1; r <= resolution; r += 1 {
+		if h3IsClassIII(r) {
+			coord.downAp7()
+		} else {
+			coord.downAp7r()
+		}
+
+		coord.neighbor(e.digit(r))
+	}
+
+	if !possibleOverage {
+		return face, coord
+	}
+
+	var original = coord
+
+	// class III goes to the next finer class II
+	var adjustedResolution = resolution
+	if h3IsClassIII(resolution) {
+		coord.downAp7r()
+		adjustedResolution += 1
+	}
+
+	var pentagonLeading4 = pentagon && e.leadingNonZeroDigit() == h3PentagonLeading4
+	if h3AdjustOverage(&face, &coord, adjustedResolution, pentagonLeading4, false) != h3NoOverage {
+		// a pentagon may have a second overage
+		if pentagon {
+			for h3AdjustOverage(&face, &coord, adjustedResolution, false, false) != h3NoOverage {
+			}
+		}
+
+		if adjustedResolution != resolution {
+			coord.upAp7r()
+		}
+	} else if adjustedResolution != resolution {
+		coord = original
+	}
+
+	return face, coord
+}
+
+// English: Center of the cell.
+//
+// Português: Centro da célula.
+func (e H3Index) Center() PointStt {
+	face, coord := e.toFaceIjk()
+	x, y := coord.toHex2d()
+	latitude, longitude := h3Hex2dToGeo(x, y, face, e.Resolution(), false)
+
+	var returnLStt PointStt
+	returnLStt.SetLngLatDegrees(RadiansToDegrees(longitude), RadiansToDegrees(latitude))
+
+	return returnLStt
+}
+
+// h3Vertices is the face, the resolution of class II and the coordinates of the vertices on the substrate grid of the
+// cell.
+func h3Vertices(face int, coord h3CoordIJKStt, resolution, count int) (int, []h3CoordIJKStt) {
+	// the vertices of a cell centered at the origin, counterclockwise from the axis i
+	var vertices = []h3CoordIJKStt{{2, 1, 0}, {1, 2, 0}, {0, 2, 1}, {0, 1, 2}, {1, 0, 2}, {2, 0, 1}}
+	if h3IsClassIII(resolution) {
+		vertices = []h3CoordIJKStt{{5, 4, 0}, {1, 5, 0}, {0, 5, 4}, {0, 1, 5}, {4, 0, 5}, {5, 0, 1}}
+	}
+
+	// the center in the substrate grid of aperture 3, 3r and, in class III, 7r
+	coord.downAp3()
+	coord.downAp3r()
+
+	if h3IsClassIII(resolution) {
+		coord.downAp7r()
+		resolution += 1
+	}
+
+	var returnList = make([]h3CoordIJKStt, count)
+	for k := range returnList {
+		returnList[k] = coord
+		returnList[k].add(vertices[k])
+		returnList[k].normalize()
+	}
+
+	return resolution, returnList
+}
+
+// h3EdgeVertices are the vertices of the edge of the face of the direction.
+func h3EdgeVertices(direction, resolution int) ([2]float64, [2]float64) {
+	var maxDimension = float64(h3MaxDimension(resolution))
+	var v0 = [2]float64{3 * maxDimension, 0}
+	var v1 = [2]float64{-1.5 * maxDimension, 3 * h3Sqrt3By2 * maxDimension}
+	var v2 = [2]float64{-1.5 * maxDimension, -3 * h3Sqrt3By2 * maxDimension}
+
+	switch direction {
+	case h3IJ:
+		return v0, v1
+	case h3JK:
+		return v1, v2
+	}
+
+	return v2, v0
+}
+
+// h3Intersect is the intersection of the lines of the points 0 and 1 and of the points 2 and 3.
+func h3Intersect(p0, p1, p2, p3 [2]float64) [2]float64 {
+	var s1 = [2]float64{p1[0] - p0[0], p1[1] - p0[1]}
+	var s2 = [2]float64{p3[0] - p2[0], p3[1] - p2[1]}
+
+	var t = (s2[0]*(p0[1]-p2[1]) - s2[1]*(p0[0]-p2[0])) / (-s2[0]*s1[1] + s1[0]*s2[1])
+
+	return [2]float64{p0[0] + t*s1[0], p0[1] + t*s1[1]}
+}
+
+func h3AlmostEqual(a, b [2]float64) bool {
+	return math.Abs(a[0]-b[0]) < h3FloatEpsilon && math.Abs(a[1]-b[1]) < h3FloatEpsilon
+}
+
+// boundary is the list of vertices, latitude and longitude in radians, of the cell, with the vertices added where
+// the edges cross the edges of the icosahedron.
+func (e H3Index) boundary() [][2]float64 {
+	face, coord := e.toFaceIjk()
+	var resolution = e.Resolution()
+	var returnList = make([][2]float64, 0, 10)
+
+	var addVertex = func(x, y float64, face, resolution int) {
+		latitude, longitude := h3Hex2dToGeo(x, y, face, resolution, true)
+		returnList = append(returnList, [2]float64{latitude, longitude})
+	}
+
+	if e.IsPentagon() {
+		adjustedResolution, vertices := h3Vertices(face, coord, resolution, h3PentagonVertices)
+
+		var lastFace int
+		var lastCoord h3CoordIJKStt
+
+		// one more iteration for the vertex of the crossing of the last edge
+		for vertex := 0; vertex < h3PentagonVertices+1; vertex += 1 {
+			var vertexFace = face
+			var vertexCoord = vertices[vertex%h3PentagonVertices]
+
+			for h3AdjustOverage(&vertexFace, &vertexCoord, adjustedResolution, false, true) == h3NewFaceOverage {
+			}
+
+			// all the edges of the pentagons of class III cross the edges of the icosahedron
+			if h3IsClassIII(resolution) && vertex > 0 {
+				x0, y0 := lastCoord.toHex2d()
+
+				var orientation = h3FaceNeighbors[vertexFace][h3AdjacentFaceDir[vertexFace][lastFace]]
+				var tmpCoord = vertexCoord
+				for k := 0; k < orientation.ccwRot60; k += 1 {
+					tmpCoord.rotate60ccw()
+				}
+
+				var translate = orientation.translate
+				translate.scale(h3UnitScale(adjustedResolution) * h3SubstrateScale)
+				tmpCoord.add(translate)
+				tmpCoord.normalize()
+
+				x1, y1 := tmpCoord.toHex2d()
+				edge0, edge1 := h3EdgeVertices(h3AdjacentFaceDir[orientation.face][vertexFace], adjustedResolution)
+
+				var intersection = h3Intersect([2]float64{x0, y0}, [2]float64{x1, y1}, edge0, edge1)
+				addVertex(intersection[0], intersection[1], orientation.face, adjustedResolution)
+			}
+
+			if vertex < h3PentagonVertices {
+				x, y := vertexCoord.toHex2d()
+				addVertex(x, y, vertexFace, adjustedResolution)
+			}
+
+			lastFace = vertexFace
+			lastCoord = vertexCoord
+		}
+
+		return returnList
+	}
+
+	adjustedResolution, vertices := h3Vertices(face, coord, resolution, h3HexagonVertices)
+
+	var lastFace = -1
+	var lastOverage = h3NoOverage
+
+	for vertex := 0; vertex < h3HexagonVertices+1; vertex += 1 {
+		var v = vertex % h3HexagonVertices
+		var vertexFace = face
+		var vertexCoord = vertices[v]
+
+		var overage = h3AdjustOverage(&vertexFace, &vertexCoord, adjustedResolution, false, true)
+
+		// the edge crosses an edge of the icosahedron, unless the crossing is on a vertex of the cell
+		if h3IsClassIII(resolution) && vertex > 0 && vertexFace != lastFace && lastOverage != h3FaceEdgeOverage {
+			x0, y0 := vertices[(v+5)%h3HexagonVertices].toHex2d()
+			x1, y1 := vertices[v].toHex2d()
+
+			var face2 = lastFace
+			if lastFace == face {
+				face2 = vertexFace
+			}
+
+			edge0, edge1 := h3EdgeVertices(h3AdjacentFaceDir[face][face2], adjustedResolution)
+
+			var intersection = h3Intersect([2]float64{x0, y0}, [2]float64{x1, y1}, edge0, edge1)
+			if !h3AlmostEqual([2]float64{x0, y0}, intersection) && !h3AlmostEqual([2]float64{x1, y1}, intersection) {
+				addVertex(intersection[0], intersection[1], face, adjustedResolution)
+			}
+		}
+
+		if vertex < h3HexagonVertices {
+			x, y := vertexCoord.toHex2d()
+			addVertex(x, y, vertexFace, adjustedResolution)
+		}
+
+		lastFace = vertexFace
+		lastOverage = overage
+	}
+
+	return returnList
+}
+
+// English: Polygon of the border of the cell, counterclockwise, with six vertices for the hexagons and five for the
+// pentagons, plus the vertices where the border crosses an edge of the icosahedron.
+//
+// Português: Polígono da borda da célula, em sentido anti-horário, com seis vértices para os hexágonos e cinco para os
+// pentágonos, mais os vértices onde a borda cruza uma aresta do icosaedro.
+func (e H3Index) Boundary() (PolygonStt, error) {
+	var polygon PolygonStt
+
+	if !e.IsValid() {
+		return polygon, fmt.Errorf("h3: invalid index '%v'", e)
+	}
+
+	for _, vertex := range e.boundary() {
+		polygon.AddLngLatDegrees(RadiansToDegrees(vertex[1]), RadiansToDegrees(vertex[0]))
+	}
+
+	return polygon, polygon.Init()
+}
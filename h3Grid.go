@@ -0,0 +1,439 @@
+package iotmaker_geo_osm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// the six directions of the neighbors
+var h3Directions = [6]int{h3JAxesDigit, h3JKAxesDigit, h3KAxesDigit, h3IKAxesDigit, h3IAxesDigit, h3IJAxesDigit}
+
+// new digit and adjustment to the parent digit when moving in a direction, by the old digit and the direction, in the
+// resolutions of class II and of class III
+var (
+	h3NewDigitII = [7][7]int{
+		{0, 1, 2, 3, 4, 5, 6},
+		{1, 4, 3, 6, 5, 2, 0},
+		{2, 3, 1, 4, 6, 0, 5},
+		{3, 6, 4, 5, 0, 1, 2},
+		{4, 5, 6, 0, 2, 3, 1},
+		{5, 2, 0, 1, 3, 6, 4},
+		{6, 0, 5, 2, 1, 4, 3},
+	}
+
+	h3NewAdjustmentII = [7][7]int{
+		{0, 0, 0, 0, 0, 0, 0},
+		{0, 1, 0, 1, 0, 5, 0},
+		{0, 0, 2, 3, 0, 0, 2},
+		{0, 1, 3, 3, 0, 0, 0},
+		{0, 0, 0, 0, 4, 4, 6},
+		{0, 5, 0, 0, 4, 5, 0},
+		{0, 0, 2, 0, 6, 0, 6},
+	}
+
+	h3NewDigitIII = [7][7]int{
+		{0, 1, 2, 3, 4, 5, 6},
+		{1, 2, 3, 4, 5, 6, 0},
+		{2, 3, 4, 5, 6, 0, 1},
+		{3, 4, 5, 6, 0, 1, 2},
+		{4, 5, 6, 0, 1, 2, 3},
+		{5, 6, 0, 1, 2, 3, 4},
+		{6, 0, 1, 2, 3, 4, 5},
+	}
+
+	h3NewAdjustmentIII = [7][7]int{
+		{0, 0, 0, 0, 0, 0, 0},
+		{0, 1, 0, 3, 0, 1, 0},
+		{0, 0, 2, 2, 0, 0, 6},
+		{0, 3, 2, 3, 0, 0, 0},
+		{0, 0, 0, 0, 4, 5, 4},
+		{0, 1, 0, 0, 5, 5, 0},
+		{0, 0, 6, 0, 4, 0, 6},
+	}
+)
+
+// errH3Pentagon is returned when the direction is the one deleted from a pentagon
+var errH3Pentagon = errors.New("h3: the direction is deleted from the pentagon")
+
+// neighbor is the cell next to the cell in the direction and the rotations of the coordinates between them, added to
+// the ones given.
+func (e H3Index) neighbor(direction, rotations int) (H3Index, int, error) {
+	var current = e
+
+	rotations = rotations % 6
+	for k := 0; k < rotations; k += 1 {
+		direction = h3RotateDigit60ccw(direction)
+	}
+
+	var newRotations = 0
+	var oldBaseCell = current.BaseCell()
+	var oldLeadingDigit = current.leadingNonZeroDigit()
+
+	// the digits and, if needed, the base cell
+	for r := current.Resolution() - 1; ; {
+		if r == -1 {
+			current = current.setBaseCell(h3BaseCellNeighbors[oldBaseCell][direction])
+			newRotations = h3BaseCellNeighbor60CCWRots[oldBaseCell][direction]
+
+			if current.BaseCell() == h3InvalidBaseCell {
+				// the deleted vertex k of the base cell borders another neighbor
+				current = current.setBaseCell(h3BaseCellNeighbors[oldBaseCell][h3IKAxesDigit])
+				newRotations = h3BaseCellNeighbor60CCWRots[oldBaseCell][h3IKAxesDigit]
+
+				current = current.rotate60ccw()
+				rotations += 1
+			}
+
+			break
+		}
+
+		var oldDigit = current.digit(r + 1)
+		var nextDirection int
+
+		if oldDigit == h3InvalidDigit {
+			return 0, 0, fmt.Errorf("h3: invalid index '%v'", e)
+		} else if h3IsClassIII(r + 1) {
+			current = current.setDigit(r+1, h3NewDigitII[oldDigit][direction])
+			nextDirection = h3NewAdjustmentII[oldDigit][direction]
+		} else {
+			current = current.setDigit(r+1, h3NewDigitIII[oldDigit][direction])
+			nextDirection = h3NewAdjustmentIII[oldDigit][direction]
+		}
+
+		if nextDirection == h3CenterDigit {
+			break
+		}
+
+		direction = nextDirection
+		r -= 1
+	}
+
+	var newBaseCell = current.BaseCell()
+	if !h3BaseCellData[newBaseCell].pentagon {
+		for k := 0; k < newRotations; k += 1 {
+			current = current.rotate60ccw()
+		}
+
+		return current, (rotations + newRotations) % 6, nil
+	}
+
+	var alreadyAdjustedKSubsequence = false
+
+	// out of the missing sub-sequence of the axis k
+	if current.leadingNonZeroDigit() == h3KAxesDigit {
+		if oldBaseCell != newBaseCell {
+			if h3BaseCellIsCwOffset(newBaseCell, h3BaseCellData[oldBaseCell].face) {
+				current = current.rotate60cw()
+			} else {
+				current = current.rotate60ccw()
+			}
+			alreadyAdjustedKSubsequence = true
+		} else {
+			switch oldLeadingDigit {
+			case h3CenterDigit:
+				return 0, 0, errH3Pentagon
+			case h3JKAxesDigit:
+				current = current.rotate60ccw()
+				rotations += 1
+			case h3IKAxesDigit:
+				current = current.rotate60cw()
+				rotations += 5
+			default:
+				return 0, 0, fmt.Errorf("h3: invalid index '%v'", e)
+			}
+		}
+	}
+
+	for k := 0; k < newRotations; k += 1 {
+		current = current.rotatePentagon60ccw()
+	}
+
+	// the orientation of the base cells may be different
+	if oldBaseCell != newBaseCell {
+		if newBaseCell == h3PolarPentagonNorth || newBaseCell == h3PolarPentagonSouth {
+			if oldBaseCell != 118 && oldBaseCell != 8 && current.leadingNonZeroDigit() != h3JKAxesDigit {
+				rotations += 1
+			}
+		} else if current.leadingNonZeroDigit() == h3IKAxesDigit && !alreadyAdjustedKSubsequence {
+			rotations += 1
+		}
+	}
+
+	return current, (rotations + newRotations) % 6, nil
+}
+
+// English: The cells at a distance of at most k steps from the cell, the k-ring, the cell first and then ring by ring,
+// each ring sorted by the index.
+//
+// Português: As células a uma distância de no máximo k passos da célula, o k-ring, a célula primeiro e depois anel
+// por anel, cada anel ordenado pelo índice.
+func (e H3Index) GridDisk(k int) ([]H3Index, error) {
+	if k < 0 {
+		return nil, fmt.Errorf("h3: the distance must not be negative")
+	}
+
+	if !e.IsValid() {
+		return nil, fmt.Errorf("h3: invalid index '%v'", e)
+	}
+
+	var visited = map[H3Index]bool{e: true}
+	var returnList = []H3Index{e}
+	var ring = []H3Index{e}
+
+	for distance := 0; distance < k && len(ring) != 0; distance += 1 {
+		var next = make([]H3Index, 0, 6*(distance+1))
+
+		for _, cell := range ring {
+			for _, direction := range h3Directions {
+				neighbor, _, err := cell.neighbor(direction, 0)
+				if err == errH3Pentagon {
+					continue
+				} else if err != nil {
+					return nil, err
+				}
+
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+		}
+
+		sort.Slice(next, func(i, j int) bool { return next[i] < next[j] })
+		returnList = append(returnList, next...)
+		ring = next
+	}
+
+	return returnList, nil
+}
+
+// h3Loop is a ring of the polygon, latitude and longitude in radians, without the closing point, with its bounding
+// box. The box crosses the antimeridian when east is less than west.
+type h3Loop struct {
+	points       [][2]float64
+	south, north float64
+	west, east   float64
+}
+
+func newH3Loop(pointsList []PointStt) h3Loop {
+	var loop h3Loop
+
+	var length = len(pointsList)
+	if length > 1 && pointsList[0].Loc == pointsList[length-1].Loc {
+		length -= 1
+	}
+
+	loop.south, loop.west = math.MaxFloat64, math.MaxFloat64
+	loop.north, loop.east = -math.MaxFloat64, -math.MaxFloat64
+	var minimumPositive, maximumNegative = math.MaxFloat64, -math.MaxFloat64
+	var transmeridian = false
+
+	for k := 0; k != length; k += 1 {
+		loop.points = append(loop.points, [2]float64{DegreesToRadians(pointsList[k].Loc[1]), DegreesToRadians(pointsList[k].Loc[0])})
+	}
+
+	for k, point := range loop.points {
+		var next = loop.points[(k+1)%len(loop.points)]
+
+		loop.south, loop.north = math.Min(loop.south, point[0]), math.Max(loop.north, point[0])
+		loop.west, loop.east = math.Min(loop.west, point[1]), math.Max(loop.east, point[1])
+
+		if point[1] > 0 && point[1] < minimumPositive {
+			minimumPositive = point[1]
+		}
+		if point[1] < 0 && point[1] > maximumNegative {
+			maximumNegative = point[1]
+		}
+
+		if math.Abs(point[1]-next[1]) > math.Pi {
+			transmeridian = true
+		}
+	}
+
+	if transmeridian {
+		loop.east = maximumNegative
+		loop.west = minimumPositive
+	}
+
+	return loop
+}
+
+// contains is the ray casting of H3, with its rules for the points on the edges.
+func (el *h3Loop) contains(latitude, longitude float64) bool {
+	if latitude < el.south || latitude > el.north {
+		return false
+	}
+
+	var transmeridian = el.east < el.west
+	var normalize = func(longitude float64) float64 {
+		if transmeridian && longitude < 0 {
+			return longitude + 2*math.Pi
+		}
+
+		return longitude
+	}
+
+	if transmeridian {
+		if longitude < el.west && longitude > el.east {
+			return false
+		}
+	} else if longitude < el.west || longitude > el.east {
+		return false
+	}
+
+	longitude = normalize(longitude)
+	var contains = false
+
+	for k := range el.points {
+		var a = el.points[k]
+		var b = el.points[(k+1)%len(el.points)]
+
+		if a[0] > b[0] {
+			a, b = b, a
+		}
+
+		// the ray passes through the vertex twice, the latitude is moved to the north
+		if latitude == a[0] || latitude == b[0] {
+			latitude += h3DoubleEpsilon
+		}
+
+		if latitude < a[0] || latitude > b[0] {
+			continue
+		}
+
+		var aLongitude = normalize(a[1])
+		var bLongitude = normalize(b[1])
+
+		if aLongitude == longitude || bLongitude == longitude {
+			longitude -= h3DoubleEpsilon
+		}
+
+		var ratio = (latitude - a[0]) / (b[0] - a[0])
+		if normalize(aLongitude+(bLongitude-aLongitude)*ratio) > longitude {
+			contains = !contains
+		}
+	}
+
+	return contains
+}
+
+// English: The cells of the resolution with the center inside the polygon and out of its holes, as the polyfill of
+// H3. The edges of the polygon are straight lines in longitude and latitude.
+//
+// Português: As células da resolução com o centro dentro do polígono e fora dos seus buracos, como o polyfill do H3.
+// As arestas do polígono são retas em longitude e latitude.
+func (el *PolygonStt) H3Polyfill(resolution int) ([]H3Index, error) {
+	if resolution < 0 || resolution > H3_MAX_RESOLUTION {
+		return nil, fmt.Errorf("h3: the resolution must be between 0 and %v", H3_MAX_RESOLUTION)
+	}
+
+	if len(el.PointsList) < 3 {
+		return nil, fmt.Errorf("h3: the polygon must have at least three points")
+	}
+
+	var loops = []h3Loop{newH3Loop(el.PointsList)}
+	for k := range el.Inner {
+		loops = append(loops, newH3Loop(el.Inner[k].PointsList))
+	}
+
+	var inside = func(cell H3Index) bool {
+		var center = cell.Center()
+		var latitude, longitude = DegreesToRadians(center.Loc[1]), DegreesToRadians(center.Loc[0])
+
+		if !loops[0].contains(latitude, longitude) {
+			return false
+		}
+
+		for k := 1; k < len(loops); k += 1 {
+			if loops[k].contains(latitude, longitude) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	// the radius of a pentagon, the smallest cell, gives the step along the edges
+	var pentagon = H3Index(h3Init).setMode(h3CellMode).setResolution(resolution).setBaseCell(h3PolarPentagonNorth)
+	for r := 1; r <= resolution; r += 1 {
+		pentagon = pentagon.setDigit(r, h3CenterDigit)
+	}
+
+	var pentagonCenter = pentagon.Center()
+	var pentagonVertex = pentagon.boundary()[0]
+	var pentagonRadius = h3GreatCircleDistance(DegreesToRadians(pentagonCenter.Loc[1]), DegreesToRadians(pentagonCenter.Loc[0]),
+		pentagonVertex[0], pentagonVertex[1])
+
+	// the cells along the edges of all the rings start the search
+	var search = make([]H3Index, 0)
+	var seen = make(map[H3Index]bool)
+
+	for _, loop := range loops {
+		for k, origin := range loop.points {
+			var destination = loop.points[(k+1)%len(loop.points)]
+
+			var steps = int(math.Ceil(h3GreatCircleDistance(origin[0], origin[1], destination[0], destination[1]) / (2 * pentagonRadius)))
+			if steps == 0 {
+				steps = 1
+			}
+
+			for step := 0; step != steps; step += 1 {
+				var latitude = origin[0]*float64(steps-step)/float64(steps) + destination[0]*float64(step)/float64(steps)
+				var longitude = origin[1]*float64(steps-step)/float64(steps) + destination[1]*float64(step)/float64(steps)
+
+				face, x, y := h3GeoToHex2d(latitude, longitude, resolution)
+				var cell = h3FaceIjkToIndex(face, h3Hex2dToCoordIJK(x, y), resolution)
+
+				if !seen[cell] {
+					seen[cell] = true
+					search = append(search, cell)
+				}
+			}
+		}
+	}
+
+	// the neighbors with the center inside the polygon are found until there are no more new ones
+	var found = make(map[H3Index]bool)
+	var returnList = make([]H3Index, 0)
+
+	for len(search) != 0 {
+		var next = make([]H3Index, 0)
+
+		for _, cell := range search {
+			disk, err := cell.GridDisk(1)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, neighbor := range disk {
+				if found[neighbor] {
+					continue
+				}
+
+				if inside(neighbor) {
+					found[neighbor] = true
+					returnList = append(returnList, neighbor)
+					next = append(next, neighbor)
+				}
+			}
+		}
+
+		search = next
+	}
+
+	sort.Slice(returnList, func(i, j int) bool { return returnList[i] < returnList[j] })
+
+	return returnList, nil
+}
+
+// h3GreatCircleDistance is the distance, in radians, between two points in radians.
+func h3GreatCircleDistance(latitude1, longitude1, latitude2, longitude2 float64) float64 {
+	var sinLatitude = math.Sin((latitude2 - latitude1) / 2)
+	var sinLongitude = math.Sin((longitude2 - longitude1) / 2)
+
+	var a = sinLatitude*sinLatitude + math.Cos(latitude1)*math.Cos(latitude2)*sinLongitude*sinLongitude
+
+	return 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
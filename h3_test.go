@@ -0,0 +1,58 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+// The expected values are the ones of the reference library of H3.
+func ExamplePointStt_H3Index() {
+	var point PointStt
+	point.SetLngLatDegrees(-122.4194, 37.7749)
+
+	for _, resolution := range []int{0, 5, 9, 15} {
+		fmt.Printf("resolution %v: %v\n", resolution, point.H3Index(resolution))
+	}
+
+	cell, _ := H3IndexFromString("89283082803ffff")
+	boundary, _ := cell.Boundary()
+	for _, vertex := range boundary.PointsList[:len(boundary.PointsList)-1] {
+		fmt.Printf("vertex: %.6f %.6f\n", vertex.Loc[1], vertex.Loc[0])
+	}
+
+	disk, _ := cell.GridDisk(1)
+	fmt.Printf("disk: %v\n", disk)
+
+	// the pentagons have five neighbors
+	pentagon, _ := H3IndexFromString("8009fffffffffff")
+	disk, _ = pentagon.GridDisk(1)
+	fmt.Printf("pentagon: %v %v\n", pentagon.IsPentagon(), disk)
+
+	// Output:
+	// resolution 0: 8029fffffffffff
+	// resolution 5: 85283083fffffff
+	// resolution 9: 89283082803ffff
+	// resolution 15: 8f283082800b390
+	// vertex: 37.772010 -122.417011
+	// vertex: 37.773693 -122.415940
+	// vertex: 37.775198 -122.417200
+	// vertex: 37.775020 -122.419531
+	// vertex: 37.773337 -122.420602
+	// vertex: 37.771832 -122.419342
+	// disk: [89283082803ffff 89283082807ffff 8928308280bffff 8928308280fffff 89283082813ffff 89283082817ffff 8928308281bffff]
+	// pentagon: true [8009fffffffffff 8001fffffffffff 8007fffffffffff 8011fffffffffff 8019fffffffffff 801ffffffffffff]
+}
+
+func ExamplePolygonStt_H3Polyfill() {
+	var polygon PolygonStt
+	polygon.AddLngLatDegrees(-122.4089866999972145, 37.813318999983238)
+	polygon.AddLngLatDegrees(-122.3544736, 37.7198061999978478)
+	polygon.AddLngLatDegrees(-122.4798767000009008, 37.8151571999998453)
+	polygon.AddLngLatDegrees(-122.5323847, 37.7866302000007224)
+	polygon.Init()
+
+	cells, _ := polygon.H3Polyfill(9)
+	fmt.Printf("%v cells, from %v to %v\n", len(cells), cells[0], cells[len(cells)-1])
+
+	// Output:
+	// 299 cells, from 89283080c87ffff to 892830876dbffff
+}
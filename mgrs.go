@@ -0,0 +1,149 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// latitude bands of 8°, from -80°, the last one, X, with 12°
+	mgrsBands = "CDEFGHJKLMNPQRSTUVWX"
+
+	// letters of the rows of the 100 km squares, shifted by five in the even zones
+	mgrsRows = "ABCDEFGHJKLMNPQRSTUV"
+
+	mgrsSquare       = 100000.0
+	mgrsNorthingLoop = 2000000.0
+	mgrsMaxPrecision = 5
+)
+
+// letters of the columns of the 100 km squares, in the sets of the zones 1, 2 and 3, repeated every three zones
+var mgrsColumns = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+
+// mgrsBand is the index of the latitude band in mgrsBands.
+func mgrsBand(latitude float64) int {
+	var band = int(math.Floor((latitude - utmMinLatitude) / 8))
+	if band >= len(mgrsBands) {
+		band = len(mgrsBands) - 1
+	}
+
+	return band
+}
+
+// mgrsRowShift is the shift of the row letters of the zone.
+func mgrsRowShift(zone int) int {
+	if zone%2 == 0 {
+		return 5
+	}
+
+	return 0
+}
+
+// English: Converts the point into a MGRS reference, as "23KPU1234567890", with the number of digits of the easting
+// and of the northing given by the precision, from 0, the 100 km square, to 5, one meter. The digits are truncated,
+// so the reference is the square that contains the point.
+//
+// Português: Converte o ponto em uma referência MGRS, como "23KPU1234567890", com o número de dígitos do leste e do
+// norte dado pela precisão, de 0, o quadrado de 100 km, a 5, um metro. Os dígitos são truncados, então a referência é
+// o quadrado que contém o ponto.
+func (el *PointStt) ToMGRS(precision int) (string, error) {
+	if precision < 0 || precision > mgrsMaxPrecision {
+		return "", fmt.Errorf("mgrs: precision %v is outside the limits of 0 and %v", precision, mgrsMaxPrecision)
+	}
+
+	utm, err := el.ToUTM()
+	if err != nil {
+		return "", fmt.Errorf("mgrs: %v", err)
+	}
+
+	var columns = mgrsColumns[(utm.Zone-1)%3]
+	var column = int(math.Floor(utm.Easting/mgrsSquare)) - 1
+	if column < 0 || column >= len(columns) {
+		return "", fmt.Errorf("mgrs: easting %v is outside the 100 km squares of zone %v", utm.Easting, utm.Zone)
+	}
+
+	var row = (int(math.Floor(utm.Northing/mgrsSquare)) + mgrsRowShift(utm.Zone)) % len(mgrsRows)
+
+	var divisor = math.Pow(10, float64(mgrsMaxPrecision-precision))
+	var easting = int(math.Floor(math.Mod(utm.Easting, mgrsSquare) / divisor))
+	var northing = int(math.Floor(math.Mod(utm.Northing, mgrsSquare) / divisor))
+
+	var reference = fmt.Sprintf("%02d%c%c%c", utm.Zone, mgrsBands[mgrsBand(el.Loc[1])], columns[column], mgrsRows[row])
+	if precision != 0 {
+		reference += fmt.Sprintf("%0*d%0*d", precision, easting, precision, northing)
+	}
+
+	return reference, nil
+}
+
+// English: Sets the point from a MGRS reference, as "23KPU1234567890" or "23K PU 12345 67890", at the south-west
+// corner of the square given by the reference.
+//
+// Português: Define o ponto a partir de uma referência MGRS, como "23KPU1234567890" ou "23K PU 12345 67890", no canto
+// sudoeste do quadrado dado pela referência.
+func (el *PointStt) FromMGRS(reference string) error {
+	var text = strings.ToUpper(strings.Join(strings.Fields(reference), ""))
+
+	var digits = 0
+	for digits < len(text) && digits < 2 && text[digits] >= '0' && text[digits] <= '9' {
+		digits += 1
+	}
+
+	zone, err := strconv.Atoi(text[:digits])
+	if err != nil || zone < 1 || zone > 60 {
+		return fmt.Errorf("mgrs: invalid zone in '%v'", reference)
+	}
+
+	text = text[digits:]
+	if len(text) < 3 {
+		return fmt.Errorf("mgrs: '%v' must have the latitude band and the 100 km square", reference)
+	}
+
+	var band = strings.IndexByte(mgrsBands, text[0])
+	var column = strings.IndexByte(mgrsColumns[(zone-1)%3], text[1])
+	var row = strings.IndexByte(mgrsRows, text[2])
+	if band == -1 || column == -1 || row == -1 {
+		return fmt.Errorf("mgrs: invalid latitude band or 100 km square in '%v'", reference)
+	}
+
+	text = text[3:]
+	var precision = len(text) / 2
+	if len(text)%2 != 0 || precision > mgrsMaxPrecision {
+		return fmt.Errorf("mgrs: '%v' must have the same number of digits, up to %v, for easting and northing", reference, mgrsMaxPrecision)
+	}
+
+	var easting = float64(column+1) * mgrsSquare
+	var northing = float64((row-mgrsRowShift(zone)+len(mgrsRows))%len(mgrsRows)) * mgrsSquare
+
+	if precision != 0 {
+		var multiplier = math.Pow(10, float64(mgrsMaxPrecision-precision))
+
+		eastingDigits, errEasting := strconv.Atoi(text[:precision])
+		northingDigits, errNorthing := strconv.Atoi(text[precision:])
+		if errEasting != nil || errNorthing != nil || eastingDigits < 0 || northingDigits < 0 {
+			return fmt.Errorf("mgrs: invalid digits in '%v'", reference)
+		}
+
+		easting += float64(eastingDigits) * multiplier
+		northing += float64(northingDigits) * multiplier
+	}
+
+	// the row letters repeat every 2000 km, the band gives the cycle: the northing of the square can not be more than
+	// one square below the northing of the south limit of the band on the central meridian
+	var hemisphere = HEMISPHERE_NORTH
+	var bandLatitude = utmMinLatitude + float64(band)*8
+	_, bandNorthing := newUTMKrueger(GetDatum().Ellipsoid).forward(bandLatitude, 0)
+
+	if mgrsBands[band] < 'N' {
+		hemisphere = HEMISPHERE_SOUTH
+		bandNorthing += utmFalseNorthingSouth
+	}
+
+	for northing < bandNorthing-mgrsSquare {
+		northing += mgrsNorthingLoop
+	}
+
+	return el.FromUTM(zone, hemisphere, easting, northing)
+}
@@ -0,0 +1,122 @@
+package iotmaker_geo_osm
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	OSM_CHANGE_CREATE = "create"
+	OSM_CHANGE_MODIFY = "modify"
+	OSM_CHANGE_DELETE = "delete"
+)
+
+// English: One create, modify or delete of an OsmChange file. Only one of Point, Way and Relation is set.
+//
+// Português: Um create, modify ou delete de um arquivo OsmChange. Apenas um entre Point, Way e Relation é preenchido.
+type OsmChangeActionStt struct {
+	// English: OSM_CHANGE_CREATE, OSM_CHANGE_MODIFY or OSM_CHANGE_DELETE
+	//
+	// Português: OSM_CHANGE_CREATE, OSM_CHANGE_MODIFY ou OSM_CHANGE_DELETE
+	Action   string
+	Point    *PointStt
+	Way      *WayStt
+	Relation *RelationStt
+}
+
+// English: Content of an OsmChange (.osc) file, as the minutely, hourly and daily replication diffs of OpenStreetMaps.
+//
+// The actions are kept in the same order of the file. The ways only have IdNode, because their nodes may not be in the
+// file; Loc and Rad are resolved when the change is applied to an OsmDatasetStt.
+//
+// Português: Conteúdo de um arquivo OsmChange (.osc), como os diffs de replicação por minuto, hora e dia do
+// OpenStreetMaps.
+//
+// As ações são mantidas na mesma ordem do arquivo. Os ways só têm IdNode, porque os seus nodes podem não estar no
+// arquivo; Loc e Rad são resolvidos quando a alteração é aplicada a um OsmDatasetStt.
+type OsmChangeStt struct {
+	List []OsmChangeActionStt
+}
+
+// English: Opens and parses an OsmChange file. Files ended with .gz are uncompressed.
+//
+// Português: Abre e interpreta um arquivo OsmChange. Arquivos terminados em .gz são descompactados.
+func (el *OsmChangeStt) ParseFilePath(filePath string) error {
+	var reader io.Reader
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader = file
+	if strings.HasSuffix(filePath, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+
+		reader = gzipReader
+	}
+
+	return el.Parse(reader)
+}
+
+// English: Parses an OsmChange stream and appends its actions to List.
+//
+// Português: Interpreta um fluxo OsmChange e adiciona as suas ações em List.
+func (el *OsmChangeStt) Parse(reader io.Reader) error {
+	var decoder = OsmXmlReaderStt{
+		OnlyNodeReferences: true,
+		NodeLocation:       &NodeLocationMapStt{},
+	}
+
+	decoder.OnPoint = func(point *PointStt) error {
+		el.List = append(el.List, OsmChangeActionStt{Action: decoder.Action(), Point: point})
+		return nil
+	}
+	decoder.OnWay = func(way *WayStt) error {
+		el.List = append(el.List, OsmChangeActionStt{Action: decoder.Action(), Way: way})
+		return nil
+	}
+	decoder.OnRelation = func(relation *RelationStt) error {
+		el.List = append(el.List, OsmChangeActionStt{Action: decoder.Action(), Relation: relation})
+		return nil
+	}
+
+	return decoder.Decode(reader)
+}
+
+// English: Result of OsmDatasetStt.ApplyChange()
+//
+// Português: Resultado de OsmDatasetStt.ApplyChange()
+type OsmChangeReportStt struct {
+	Created  int
+	Modified int
+	Deleted  int
+
+	// English: actions ignored because the dataset already has the same or a newer version of the element
+	//
+	// Português: ações ignoradas porque o dataset já tem a mesma versão ou uma versão mais nova do elemento
+	Skipped int
+
+	// English: id of the ways whose geometry changed and need Init() to be called again
+	//
+	// Português: id dos ways cuja geometria mudou e precisam que Init() seja chamado novamente
+	Ways []int64
+
+	// English: id of the polygons made from changed ways, that must be rebuilt and need Init() to be called again
+	//
+	// Português: id dos polígonos feitos a partir de ways alterados, que devem ser refeitos e precisam que Init() seja
+	// chamado novamente
+	Polygons []int64
+
+	// English: nodes referenced by ways of the change and not found in the dataset
+	//
+	// Português: nodes referenciados por ways da alteração e não encontrados no dataset
+	MissingNodes []int64
+}
@@ -0,0 +1,320 @@
+package iotmaker_geo_osm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// English: In memory set of OpenStreetMaps data keyed by Id, able to receive OsmChange replication diffs.
+//
+// Português: Conjunto em memória de dados do OpenStreetMaps indexados por Id, capaz de receber diffs de replicação
+// OsmChange.
+type OsmDatasetStt struct {
+	Points    map[int64]*PointStt
+	Ways      map[int64]*WayStt
+	Relations map[int64]*RelationStt
+
+	// English: polygons made from the ways of the dataset, related to them by PolygonStt.IdWay and by the IdWay of the
+	// Inner rings
+	//
+	// Português: polígonos feitos a partir dos ways do dataset, relacionados a eles por PolygonStt.IdWay e pelo IdWay
+	// dos anéis Inner
+	Polygons map[int64]*PolygonStt
+
+	waysByNode    map[int64]map[int64]struct{}
+	polygonsByWay map[int64]map[int64]struct{}
+}
+
+func (el *OsmDatasetStt) Init() {
+	el.Points = make(map[int64]*PointStt)
+	el.Ways = make(map[int64]*WayStt)
+	el.Relations = make(map[int64]*RelationStt)
+	el.Polygons = make(map[int64]*PolygonStt)
+	el.waysByNode = make(map[int64]map[int64]struct{})
+	el.polygonsByWay = make(map[int64]map[int64]struct{})
+}
+
+func (el *OsmDatasetStt) AddPoint(point *PointStt) {
+	el.Points[point.Id] = point
+}
+
+// English: Adds a way. IdNode must be filled, as the readers of this package do, to follow changes of its nodes.
+//
+// Português: Adiciona um way. IdNode deve estar preenchido, como fazem os leitores deste pacote, para acompanhar
+// alterações dos seus nodes.
+func (el *OsmDatasetStt) AddWay(way *WayStt) {
+	if old, found := el.Ways[way.Id]; found {
+		el.unindexWay(old)
+	}
+
+	el.Ways[way.Id] = way
+
+	for _, ref := range way.IdNode {
+		if el.waysByNode[ref] == nil {
+			el.waysByNode[ref] = make(map[int64]struct{})
+		}
+		el.waysByNode[ref][way.Id] = struct{}{}
+	}
+}
+
+func (el *OsmDatasetStt) AddRelation(relation *RelationStt) {
+	el.Relations[relation.Id] = relation
+}
+
+func (el *OsmDatasetStt) AddPolygon(polygon *PolygonStt) {
+	el.Polygons[polygon.Id] = polygon
+
+	// the ways of the holes change the polygon as the ways of its outer ring do
+	var idWayList = append([]int64{}, polygon.IdWay...)
+	for _, inner := range polygon.Inner {
+		idWayList = append(idWayList, inner.IdWay...)
+	}
+
+	for _, idWay := range idWayList {
+		if el.polygonsByWay[idWay] == nil {
+			el.polygonsByWay[idWay] = make(map[int64]struct{})
+		}
+		el.polygonsByWay[idWay][polygon.Id] = struct{}{}
+	}
+}
+
+func (el *OsmDatasetStt) unindexWay(way *WayStt) {
+	for _, ref := range way.IdNode {
+		delete(el.waysByNode[ref], way.Id)
+		if len(el.waysByNode[ref]) == 0 {
+			delete(el.waysByNode, ref)
+		}
+	}
+}
+
+// English: Applies an OsmChange to the dataset.
+//
+// Elements are replaced as a whole, so Version, ChangeSet and TimeStamp come from the change. Actions with a version
+// equal or older than the one in the dataset are skipped, so overlapping diffs can be applied safely. Ways whose nodes
+// were moved have Loc and Rad updated and, together with the polygons made from them, are listed in the report to
+// have Init() called again, what can be done with InitChanged().
+//
+// Português: Aplica um OsmChange ao dataset.
+//
+// Os elementos são substituídos por inteiro, por isto Version, ChangeSet e TimeStamp vêm da alteração. Ações com versão
+// igual ou mais antiga do que a do dataset são ignoradas, por isto diffs sobrepostos podem ser aplicados com segurança.
+// Ways cujos nodes foram movidos têm Loc e Rad atualizados e, junto com os polígonos feitos a partir deles, são
+// listados no relatório para terem Init() chamado novamente, o que pode ser feito com InitChanged().
+func (el *OsmDatasetStt) ApplyChange(change *OsmChangeStt) (OsmChangeReportStt, error) {
+	var report = OsmChangeReportStt{}
+	var changedWays = make(map[int64]struct{})
+	var changedNodes = make(map[int64]struct{})
+
+	if el.Points == nil {
+		el.Init()
+	}
+
+	for _, action := range change.List {
+		var applied bool
+		var err error
+
+		switch {
+		case action.Point != nil:
+			applied, err = el.applyPoint(action.Action, action.Point)
+			if applied {
+				changedNodes[action.Point.Id] = struct{}{}
+			}
+
+		case action.Way != nil:
+			applied, err = el.applyWay(action.Action, action.Way)
+			if applied {
+				changedWays[action.Way.Id] = struct{}{}
+			}
+
+		case action.Relation != nil:
+			applied, err = el.applyRelation(action.Action, action.Relation)
+
+		default:
+			err = errors.New("empty action")
+		}
+
+		if err != nil {
+			return report, err
+		}
+
+		if !applied {
+			report.Skipped += 1
+			continue
+		}
+
+		switch action.Action {
+		case OSM_CHANGE_CREATE:
+			report.Created += 1
+		case OSM_CHANGE_MODIFY:
+			report.Modified += 1
+		case OSM_CHANGE_DELETE:
+			report.Deleted += 1
+		}
+	}
+
+	// ways not present in the change are moved by the change of its nodes; Loc and Rad are only resolved after all the
+	// actions, so the ways see the nodes created or moved later in the same change
+	for idNode := range changedNodes {
+		for idWay := range el.waysByNode[idNode] {
+			changedWays[idWay] = struct{}{}
+		}
+	}
+
+	var idWays = make([]int64, 0, len(changedWays))
+	for idWay := range changedWays {
+		idWays = append(idWays, idWay)
+	}
+	sort.Slice(idWays, func(i, j int) bool { return idWays[i] < idWays[j] })
+
+	for _, idWay := range idWays {
+		if way, found := el.Ways[idWay]; found {
+			el.resolveWay(way, &report)
+		}
+	}
+
+	var changedPolygons = make(map[int64]struct{})
+	for idWay := range changedWays {
+		if _, found := el.Ways[idWay]; found {
+			report.Ways = append(report.Ways, idWay)
+		}
+
+		for idPolygon := range el.polygonsByWay[idWay] {
+			if _, found := changedPolygons[idPolygon]; !found {
+				changedPolygons[idPolygon] = struct{}{}
+				report.Polygons = append(report.Polygons, idPolygon)
+			}
+		}
+	}
+
+	sort.Slice(report.Ways, func(i, j int) bool { return report.Ways[i] < report.Ways[j] })
+	sort.Slice(report.Polygons, func(i, j int) bool { return report.Polygons[i] < report.Polygons[j] })
+
+	return report, nil
+}
+
+// English: Calls Init() on the ways and polygons listed in the report of ApplyChange().
+//
+// Polygons are only initialized again; if their shape must follow the changed ways, rebuild them before calling this
+// function.
+//
+// Português: Chama Init() nos ways e polígonos listados no relatório de ApplyChange().
+//
+// Os polígonos são apenas inicializados novamente; se a forma deles deve seguir os ways alterados, refaça-os antes de
+// chamar esta função.
+func (el *OsmDatasetStt) InitChanged(report *OsmChangeReportStt) error {
+	var err error
+
+	for _, id := range report.Ways {
+		way, found := el.Ways[id]
+		if !found || len(way.Loc) == 0 {
+			continue
+		}
+
+		err = way.Init()
+		if err != nil {
+			return fmt.Errorf("way %v: %v", id, err)
+		}
+	}
+
+	for _, id := range report.Polygons {
+		polygon, found := el.Polygons[id]
+		if !found {
+			continue
+		}
+
+		err = polygon.Init()
+		if err != nil {
+			return fmt.Errorf("polygon %v: %v", id, err)
+		}
+	}
+
+	return nil
+}
+
+// osmDatasetIsNewer tells if the version of a change must replace the version in the dataset. Changes without version always
+// replace it.
+func osmDatasetIsNewer(newVersion, oldVersion int64) bool {
+	return newVersion == 0 || newVersion > oldVersion
+}
+
+func (el *OsmDatasetStt) applyPoint(action string, point *PointStt) (bool, error) {
+	old, found := el.Points[point.Id]
+	if found && !osmDatasetIsNewer(point.Version, old.Version) {
+		return false, nil
+	}
+
+	switch action {
+	case OSM_CHANGE_CREATE, OSM_CHANGE_MODIFY:
+		el.Points[point.Id] = point
+	case OSM_CHANGE_DELETE:
+		if !found {
+			return false, nil
+		}
+		delete(el.Points, point.Id)
+	default:
+		return false, fmt.Errorf("node %v: unknown action '%v'", point.Id, action)
+	}
+
+	return true, nil
+}
+
+func (el *OsmDatasetStt) applyWay(action string, way *WayStt) (bool, error) {
+	old, found := el.Ways[way.Id]
+	if found && !osmDatasetIsNewer(way.Version, old.Version) {
+		return false, nil
+	}
+
+	switch action {
+	case OSM_CHANGE_CREATE, OSM_CHANGE_MODIFY:
+		el.AddWay(way)
+	case OSM_CHANGE_DELETE:
+		if !found {
+			return false, nil
+		}
+		el.unindexWay(old)
+		delete(el.Ways, way.Id)
+	default:
+		return false, fmt.Errorf("way %v: unknown action '%v'", way.Id, action)
+	}
+
+	return true, nil
+}
+
+func (el *OsmDatasetStt) applyRelation(action string, relation *RelationStt) (bool, error) {
+	old, found := el.Relations[relation.Id]
+	if found && !osmDatasetIsNewer(relation.Version, old.Version) {
+		return false, nil
+	}
+
+	switch action {
+	case OSM_CHANGE_CREATE, OSM_CHANGE_MODIFY:
+		el.Relations[relation.Id] = relation
+	case OSM_CHANGE_DELETE:
+		if !found {
+			return false, nil
+		}
+		delete(el.Relations, relation.Id)
+	default:
+		return false, fmt.Errorf("relation %v: unknown action '%v'", relation.Id, action)
+	}
+
+	return true, nil
+}
+
+// resolveWay fills Loc and Rad from IdNode with the points of the dataset.
+func (el *OsmDatasetStt) resolveWay(way *WayStt, report *OsmChangeReportStt) {
+	way.Loc = make([][2]float64, 0, len(way.IdNode))
+	way.Rad = make([][2]float64, 0, len(way.IdNode))
+
+	for _, ref := range way.IdNode {
+		point, found := el.Points[ref]
+		if !found {
+			report.MissingNodes = append(report.MissingNodes, ref)
+			continue
+		}
+
+		way.Loc = append(way.Loc, point.Loc)
+		way.Rad = append(way.Rad, point.Rad)
+	}
+}
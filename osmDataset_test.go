@@ -0,0 +1,105 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ExampleOsmDatasetStt_ApplyChange() {
+	var osc = `<?xml version="1.0" encoding="UTF-8"?>
+<osmChange version="0.6">
+  <modify>
+    <node id="1" version="2" changeset="20" lat="-22.95" lon="-43.2"/>
+    <node id="2" version="1" changeset="20" lat="-22.8" lon="-43.2"/>
+  </modify>
+  <create>
+    <node id="3" version="1" changeset="20" lat="-22.7" lon="-43.1"/>
+    <way id="11" version="1" changeset="20">
+      <nd ref="2"/>
+      <nd ref="3"/>
+      <nd ref="4"/>
+    </way>
+  </create>
+  <delete>
+    <relation id="21" version="2" changeset="20"/>
+  </delete>
+</osmChange>`
+
+	var dataset = OsmDatasetStt{}
+	dataset.Init()
+
+	var point1 = PointStt{Id: 1, Version: 1}
+	point1.SetLngLatDegrees(-43.2, -22.9)
+	dataset.AddPoint(&point1)
+
+	var point2 = PointStt{Id: 2, Version: 1}
+	point2.SetLngLatDegrees(-43.2, -22.8)
+	dataset.AddPoint(&point2)
+
+	dataset.AddWay(&WayStt{Id: 10, Version: 1, IdNode: []int64{1, 2}})
+	dataset.AddRelation(&RelationStt{Id: 21, Version: 1})
+	dataset.AddPolygon(&PolygonStt{Id: 30, IdWay: []int64{10}})
+
+	var change = OsmChangeStt{}
+	err := change.Parse(strings.NewReader(osc))
+	fmt.Printf("actions: %v, error: %v\n", len(change.List), err)
+
+	report, err := dataset.ApplyChange(&change)
+	fmt.Printf("created %v, modified %v, deleted %v, skipped %v, error: %v\n", report.Created, report.Modified, report.Deleted, report.Skipped, err)
+	fmt.Printf("ways %v, polygons %v, missing nodes %v\n", report.Ways, report.Polygons, report.MissingNodes)
+	fmt.Printf("way 10: %v\n", dataset.Ways[10].Loc)
+	fmt.Printf("node 1: v%v changeset %v\n", dataset.Points[1].Version, dataset.Points[1].ChangeSet)
+	fmt.Printf("relations: %v\n", len(dataset.Relations))
+
+	// Output:
+	// actions: 5, error: <nil>
+	// created 2, modified 1, deleted 1, skipped 1, error: <nil>
+	// ways [10 11], polygons [30], missing nodes [4]
+	// way 10: [[-43.2 -22.95] [-43.2 -22.8]]
+	// node 1: v2 changeset 20
+	// relations: 0
+}
+
+func ExampleOsmDatasetStt_ApplyChange_order() {
+	// the way is changed before its nodes, which are moved or created later in the same change
+	var osc = `<?xml version="1.0" encoding="UTF-8"?>
+<osmChange version="0.6">
+  <modify>
+    <way id="10" version="2" changeset="20">
+      <nd ref="1"/>
+      <nd ref="2"/>
+      <nd ref="3"/>
+    </way>
+    <node id="1" version="2" changeset="20" lat="-22.95" lon="-43.2"/>
+  </modify>
+  <create>
+    <node id="3" version="1" changeset="20" lat="-22.7" lon="-43.1"/>
+  </create>
+</osmChange>`
+
+	var dataset = OsmDatasetStt{}
+	dataset.Init()
+
+	var point1 = PointStt{Id: 1, Version: 1}
+	point1.SetLngLatDegrees(-43.2, -22.9)
+	dataset.AddPoint(&point1)
+
+	var point2 = PointStt{Id: 2, Version: 1}
+	point2.SetLngLatDegrees(-43.2, -22.8)
+	dataset.AddPoint(&point2)
+
+	dataset.AddWay(&WayStt{Id: 10, Version: 1, IdNode: []int64{1, 2}})
+
+	var change = OsmChangeStt{}
+	err := change.Parse(strings.NewReader(osc))
+	fmt.Printf("actions: %v, error: %v\n", len(change.List), err)
+
+	report, err := dataset.ApplyChange(&change)
+	fmt.Printf("ways %v, missing nodes %v, error: %v\n", report.Ways, report.MissingNodes, err)
+	fmt.Printf("way 10: %v\n", dataset.Ways[10].Loc)
+
+	// Output:
+	// actions: 3, error: <nil>
+	// ways [10], missing nodes [], error: <nil>
+	// way 10: [[-43.2 -22.95] [-43.2 -22.8] [-43.1 -22.7]]
+}
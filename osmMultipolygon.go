@@ -0,0 +1,363 @@
+package iotmaker_geo_osm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	OSM_ROLE_OUTER = "outer"
+	OSM_ROLE_INNER = "inner"
+)
+
+// osmRingStt is a closed ring made by the concatenation of one or more ways.
+type osmRingStt struct {
+	idWay []int64
+	loc   [][2]float64
+	area  float64
+}
+
+// osmRingVertexStt identifies a vertex of a ring. Vertices are compared by node id when all ways carry IdNode and by
+// coordinates otherwise.
+type osmRingVertexStt struct {
+	id  int64
+	loc [2]float64
+}
+
+func (el osmRingVertexStt) String() string {
+	if el.id != 0 {
+		return fmt.Sprintf("node %v", el.id)
+	}
+
+	return fmt.Sprintf("point %v", el.loc)
+}
+
+// English: Builds the polygons of a type=multipolygon (or type=boundary) relation.
+//
+// The member ways are taken from the ways map, by id, and must have Loc filled. Ways are joined when their endpoints
+// are the same node, or the same coordinates when IdNode is not filled, never by proximity. Members with role outer or
+// without role make the outer rings and members with role inner make the holes, that are added to Inner of the
+// polygon of the outer ring that contains them.
+//
+// An error is returned when a member way is missing, when a ring is not closed, when a ring touches itself and when an
+// inner ring is not inside any outer ring.
+//
+// Português: Monta os polígonos de uma relação type=multipolygon (ou type=boundary).
+//
+// Os ways membros são obtidos do mapa ways, pelo id, e devem ter Loc preenchido. Os ways são unidos quando as suas
+// extremidades são o mesmo node, ou as mesmas coordenadas quando IdNode não está preenchido, nunca por proximidade.
+// Membros com role outer ou sem role formam os anéis externos e membros com role inner formam os buracos, que são
+// adicionados em Inner do polígono do anel externo que os contém.
+//
+// Um erro é retornado quando falta um way membro, quando um anel não está fechado, quando um anel toca a si mesmo e
+// quando um anel interno não está dentro de nenhum anel externo.
+func AssembleMultipolygon(relation *RelationStt, ways map[int64]*WayStt) (PolygonListStt, error) {
+	var ret = PolygonListStt{}
+	var outerWays, innerWays []*WayStt
+
+	for _, member := range relation.Members {
+		if member.Type != "way" {
+			continue
+		}
+
+		if member.Role != "" && member.Role != OSM_ROLE_OUTER && member.Role != OSM_ROLE_INNER {
+			continue
+		}
+
+		way, found := ways[member.Ref]
+		if !found || way == nil {
+			return ret, fmt.Errorf("relation %v: way %v not found", relation.Id, member.Ref)
+		}
+
+		if member.Role == OSM_ROLE_INNER {
+			innerWays = append(innerWays, way)
+		} else {
+			outerWays = append(outerWays, way)
+		}
+	}
+
+	if len(outerWays) == 0 {
+		return ret, fmt.Errorf("relation %v: multipolygon without outer ways", relation.Id)
+	}
+
+	outerRings, err := osmBuildRings(outerWays)
+	if err != nil {
+		return ret, fmt.Errorf("relation %v: outer %v", relation.Id, err)
+	}
+
+	innerRings, err := osmBuildRings(innerWays)
+	if err != nil {
+		return ret, fmt.Errorf("relation %v: inner %v", relation.Id, err)
+	}
+
+	ret.Id = relation.Id
+	ret.AddRelationDataAsPolygonData(relation)
+
+	var polygonList = make([]PolygonStt, len(outerRings))
+	for key := range outerRings {
+		polygonList[key] = osmRingToPolygon(&outerRings[key], ways)
+		polygonList[key].AddRelationDataAsPolygonData(relation)
+	}
+
+	// smaller outer rings first, so an inner ring goes to the innermost outer ring of nested outer rings
+	var order = make([]int, len(outerRings))
+	for key := range order {
+		order[key] = key
+	}
+	sort.SliceStable(order, func(i, j int) bool { return outerRings[order[i]].area < outerRings[order[j]].area })
+
+	for key := range innerRings {
+		var found = false
+
+		for _, outerKey := range order {
+			if osmRingContainsRing(&outerRings[outerKey], &innerRings[key]) {
+				polygonList[outerKey].Inner = append(polygonList[outerKey].Inner, osmRingToPolygon(&innerRings[key], ways))
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return ret, fmt.Errorf("relation %v: inner ring made by ways %v is not inside any outer ring", relation.Id, innerRings[key].idWay)
+		}
+	}
+
+	for key := range polygonList {
+		err = polygonList[key].Init()
+		if err != nil {
+			return ret, fmt.Errorf("relation %v: %v", relation.Id, err)
+		}
+
+		ret.AddPolygon(&polygonList[key])
+
+		// the ways of the holes are ways of the list too, so that a change in a hole is found by them
+		var idWayList = append([]int64{}, polygonList[key].IdWay...)
+		for _, inner := range polygonList[key].Inner {
+			idWayList = append(idWayList, inner.IdWay...)
+		}
+
+		for _, idWay := range idWayList {
+			if len(ret.idWayUnique) == 0 {
+				ret.idWayUnique = make(map[int64]int64)
+			}
+			if ret.idWayUnique[idWay] != idWay {
+				ret.idWayUnique[idWay] = idWay
+				ret.IdWay = append(ret.IdWay, idWay)
+			}
+		}
+	}
+
+	ret.Initialize()
+
+	return ret, nil
+}
+
+// osmRingToPolygon turns a ring into a polygon, without calling Init(). The id of the polygon is the id of the first
+// way of the ring.
+func osmRingToPolygon(ring *osmRingStt, ways map[int64]*WayStt) PolygonStt {
+	var polygon = PolygonStt{}
+	polygon.Id = ring.idWay[0]
+
+	for _, idWay := range ring.idWay {
+		polygon.AddWayDataAsPolygonData(ways[idWay])
+	}
+
+	for _, loc := range ring.loc {
+		polygon.AddLngLatDegrees(loc[0], loc[1])
+	}
+
+	return polygon
+}
+
+// osmBuildRings joins the ways into closed rings, matching the endpoints exactly.
+func osmBuildRings(ways []*WayStt) ([]osmRingStt, error) {
+	var rings = make([]osmRingStt, 0)
+	var vertices = make([][]osmRingVertexStt, len(ways))
+	var used = make([]bool, len(ways))
+	var byEndpoint = make(map[osmRingVertexStt][]int)
+
+	// node ids are only used when every way has them, otherwise the same node would have two different identities
+	var useId = true
+	for _, way := range ways {
+		if len(way.Loc) < 2 {
+			return nil, fmt.Errorf("way %v has less than two points", way.Id)
+		}
+
+		if len(way.IdNode) != len(way.Loc) {
+			useId = false
+		}
+	}
+
+	for key, way := range ways {
+		vertices[key] = make([]osmRingVertexStt, len(way.Loc))
+		for pointKey, loc := range way.Loc {
+			if useId {
+				vertices[key][pointKey] = osmRingVertexStt{id: way.IdNode[pointKey]}
+			} else {
+				vertices[key][pointKey] = osmRingVertexStt{loc: loc}
+			}
+		}
+
+		first := vertices[key][0]
+		last := vertices[key][len(vertices[key])-1]
+
+		byEndpoint[first] = append(byEndpoint[first], key)
+		if first != last {
+			byEndpoint[last] = append(byEndpoint[last], key)
+		}
+	}
+
+	for start := range ways {
+		if used[start] {
+			continue
+		}
+
+		var ring = osmRingStt{}
+		var chain = make([]osmRingVertexStt, 0)
+		var inChain = make(map[osmRingVertexStt]int)
+
+		var add = func(key int, reverse bool) error {
+			used[key] = true
+			ring.idWay = append(ring.idWay, ways[key].Id)
+
+			var length = len(ways[key].Loc)
+			for i := 0; i != length; i += 1 {
+				var pointKey = i
+				if reverse {
+					pointKey = length - 1 - i
+				}
+
+				// the first vertex of a way is the last vertex of the chain
+				if i == 0 && len(chain) > 0 {
+					continue
+				}
+
+				var vertex = vertices[key][pointKey]
+				if position, found := inChain[vertex]; found && !(position == 0 && i == length-1) {
+					return fmt.Errorf("ring made by ways %v touches itself at %v", ring.idWay, vertex)
+				}
+
+				inChain[vertex] = len(chain)
+				chain = append(chain, vertex)
+				ring.loc = append(ring.loc, ways[key].Loc[pointKey])
+			}
+
+			return nil
+		}
+
+		err := add(start, false)
+		if err != nil {
+			return nil, err
+		}
+
+		for len(chain) < 2 || chain[0] != chain[len(chain)-1] {
+			var end = chain[len(chain)-1]
+			var next = -1
+			var reverse bool
+
+			// a way that closes the ring is preferred to avoid taking a branch of another ring
+			for _, candidate := range byEndpoint[end] {
+				if used[candidate] {
+					continue
+				}
+
+				var candidateReverse = vertices[candidate][0] != end
+				var otherEnd = vertices[candidate][len(vertices[candidate])-1]
+				if candidateReverse {
+					otherEnd = vertices[candidate][0]
+				}
+
+				if next == -1 || otherEnd == chain[0] {
+					next = candidate
+					reverse = candidateReverse
+				}
+
+				if otherEnd == chain[0] {
+					break
+				}
+			}
+
+			if next == -1 {
+				return nil, fmt.Errorf("ring made by ways %v is not closed: %v and %v are not connected", ring.idWay, chain[0], end)
+			}
+
+			err = add(next, reverse)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if len(ring.loc) < 4 {
+			return nil, fmt.Errorf("ring made by ways %v has less than three distinct points", ring.idWay)
+		}
+
+		ring.area = math.Abs(osmRingSignedArea(ring.loc))
+		rings = append(rings, ring)
+	}
+
+	return rings, nil
+}
+
+// osmRingSignedArea is the shoelace formula over longitude and latitude, used only to compare rings.
+func osmRingSignedArea(loc [][2]float64) float64 {
+	var area float64
+
+	for i := 0; i < len(loc)-1; i += 1 {
+		area += loc[i][0]*loc[i+1][1] - loc[i+1][0]*loc[i][1]
+	}
+
+	return area / 2
+}
+
+// osmRingContainsPoint is the ray casting test over longitude and latitude.
+func osmRingContainsPoint(loc [][2]float64, point [2]float64) bool {
+	var inside = false
+
+	for i, j := 0, len(loc)-1; i < len(loc); j, i = i, i+1 {
+		if (loc[i][1] > point[1]) != (loc[j][1] > point[1]) &&
+			point[0] < (loc[j][0]-loc[i][0])*(point[1]-loc[i][1])/(loc[j][1]-loc[i][1])+loc[i][0] {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// osmRingContainsRing tells if most of the vertices of inner are inside outer. Vertices shared by both rings, where
+// the inner ring touches the outer ring, make the ray casting test undetermined, so a single vertex is not enough.
+func osmRingContainsRing(outer, inner *osmRingStt) bool {
+	var inside int
+
+	if len(outer.loc) == 0 || len(inner.loc) == 0 {
+		return false
+	}
+
+	for _, loc := range inner.loc[:len(inner.loc)-1] {
+		if osmRingContainsPoint(outer.loc, loc) {
+			inside += 1
+		}
+	}
+
+	return inside*2 > len(inner.loc)-1
+}
+
+// osmJoinWaysIntoRing joins ways that must make exactly one closed ring, as the ways of PolygonStt.tmp.
+func osmJoinWaysIntoRing(ways []WayStt) ([][2]float64, error) {
+	var list = make([]*WayStt, len(ways))
+	for key := range ways {
+		list[key] = &ways[key]
+	}
+
+	rings, err := osmBuildRings(list)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rings) != 1 {
+		return nil, errors.New("the ways make more than one ring, use AssembleMultipolygon()")
+	}
+
+	return rings[0].loc, nil
+}
@@ -0,0 +1,87 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ExampleAssembleMultipolygon() {
+	var ways = map[int64]*WayStt{
+		// the outer ring is split in two ways, the second one in the opposite direction
+		1: {Id: 1, IdNode: []int64{10, 11, 12}, Loc: [][2]float64{{0, 0}, {4, 0}, {4, 4}}},
+		2: {Id: 2, IdNode: []int64{10, 13, 12}, Loc: [][2]float64{{0, 0}, {0, 4}, {4, 4}}},
+		3: {Id: 3, IdNode: []int64{20, 21, 22, 20}, Loc: [][2]float64{{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		4: {Id: 4, IdNode: []int64{30, 31, 32}, Loc: [][2]float64{{5, 5}, {6, 5}, {6, 6}}},
+	}
+
+	var relation = RelationStt{Id: 100, Tag: map[string]string{"type": "multipolygon"}}
+	relation.AddMember(MembersStt{Type: "way", Ref: 1, Role: "outer"})
+	relation.AddMember(MembersStt{Type: "way", Ref: 2, Role: "outer"})
+	relation.AddMember(MembersStt{Type: "way", Ref: 3, Role: "inner"})
+
+	polygonList, err := AssembleMultipolygon(&relation, ways)
+	fmt.Printf("error: %v\n", err)
+	fmt.Printf("polygons: %v, ways: %v\n", len(polygonList.List), polygonList.IdWay)
+	fmt.Printf("outer: %v\n", len(polygonList.List[0].PointsList))
+	fmt.Printf("inner: %v %v\n", len(polygonList.List[0].Inner), polygonList.List[0].Inner[0].IdWay)
+
+	relation.AddMember(MembersStt{Type: "way", Ref: 4, Role: "outer"})
+	_, err = AssembleMultipolygon(&relation, ways)
+	fmt.Printf("error: %v\n", err)
+
+	// Output:
+	// error: <nil>
+	// polygons: 1, ways: [1 2 3]
+	// outer: 5
+	// inner: 1 [3]
+	// error: relation 100: outer ring made by ways [4] is not closed: node 30 and node 32 are not connected
+}
+
+func ExampleAssembleMultipolygon_change() {
+	var ways = map[int64]*WayStt{
+		1: {Id: 1, Version: 1, IdNode: []int64{10, 11, 12, 13, 10}},
+		2: {Id: 2, Version: 1, IdNode: []int64{20, 21, 22, 20}},
+	}
+
+	var dataset = OsmDatasetStt{}
+	dataset.Init()
+
+	for id, loc := range map[int64][2]float64{10: {0, 0}, 11: {4, 0}, 12: {4, 4}, 13: {0, 4}, 20: {1, 1}, 21: {2, 1}, 22: {2, 2}} {
+		var point = PointStt{Id: id, Version: 1}
+		point.SetLngLatDegrees(loc[0], loc[1])
+		dataset.AddPoint(&point)
+	}
+
+	for _, way := range ways {
+		dataset.AddWay(way)
+		for _, id := range way.IdNode {
+			way.Loc = append(way.Loc, dataset.Points[id].Loc)
+		}
+	}
+
+	var relation = RelationStt{Id: 100, Tag: map[string]string{"type": "multipolygon"}}
+	relation.AddMember(MembersStt{Type: "way", Ref: 1, Role: "outer"})
+	relation.AddMember(MembersStt{Type: "way", Ref: 2, Role: "inner"})
+
+	polygonList, err := AssembleMultipolygon(&relation, ways)
+	fmt.Printf("error: %v, ways: %v\n", err, polygonList.IdWay)
+	dataset.AddPolygon(&polygonList.List[0])
+
+	// only a node of the hole moves
+	var osc = `<?xml version="1.0" encoding="UTF-8"?>
+<osmChange version="0.6">
+  <modify>
+    <node id="21" version="2" changeset="20" lat="1" lon="3"/>
+  </modify>
+</osmChange>`
+
+	var change = OsmChangeStt{}
+	_ = change.Parse(strings.NewReader(osc))
+
+	report, err := dataset.ApplyChange(&change)
+	fmt.Printf("error: %v, ways: %v, polygons: %v\n", err, report.Ways, report.Polygons)
+
+	// Output:
+	// error: <nil>, ways: [1 2]
+	// error: <nil>, ways: [2], polygons: [1]
+}
@@ -0,0 +1,51 @@
+package iotmaker_geo_osm
+
+// English: Storage for the location of nodes used to resolve the node references of a way into Loc and Rad.
+//
+// The readers of OSM files keep the location of every node they see, because the way only knows the id of its nodes.
+// For planet-sized extracts, implement this interface on top of a disk based key-value store.
+//
+// Português: Armazenamento da localização dos nodes usada para resolver as referências de nodes de um way em Loc e Rad.
+//
+// Os leitores de arquivos OSM guardam a localização de todos os nodes lidos, porque o way só conhece o id dos seus nodes.
+// Para arquivos do tamanho do planeta, implemente esta interface sobre um banco chave-valor em disco.
+type NodeLocationStore interface {
+	// English: Stores the location [longitude, latitude] in decimal degrees of a node
+	//
+	// Português: Guarda a localização [longitude, latitude] em graus decimais de um node
+	Set(id int64, loc [2]float64) error
+
+	// English: Returns the location [longitude, latitude] in decimal degrees of a node and whether it was found
+	//
+	// Português: Devolve a localização [longitude, latitude] em graus decimais de um node e se o mesmo foi encontrado
+	Get(id int64) ([2]float64, bool)
+}
+
+// English: In memory NodeLocationStore backed by a map. It is the default store of the readers.
+//
+// Português: NodeLocationStore em memória baseado em um map. É o armazenamento padrão dos leitores.
+type NodeLocationMapStt struct {
+	list map[int64][2]float64
+}
+
+func (el *NodeLocationMapStt) Set(id int64, loc [2]float64) error {
+	if el.list == nil {
+		el.list = make(map[int64][2]float64)
+	}
+
+	el.list[id] = loc
+
+	return nil
+}
+
+func (el *NodeLocationMapStt) Get(id int64) ([2]float64, bool) {
+	loc, found := el.list[id]
+	return loc, found
+}
+
+// English: Number of nodes in the store
+//
+// Português: Quantidade de nodes no armazenamento
+func (el *NodeLocationMapStt) Len() int {
+	return len(el.list)
+}
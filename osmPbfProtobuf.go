@@ -0,0 +1,166 @@
+package iotmaker_geo_osm
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Minimal protocol buffers wire format support for the OSM PBF file format.
+// Only what fileformat.proto and osmformat.proto need is implemented.
+
+const (
+	pbfWireVarint = 0
+	pbfWire64Bit  = 1
+	pbfWireBytes  = 2
+	pbfWire32Bit  = 5
+)
+
+var errPbfTruncated = errors.New("pbf: truncated message")
+
+type pbfDecoderStt struct {
+	data []byte
+	pos  int
+}
+
+func (el *pbfDecoderStt) more() bool {
+	return el.pos < len(el.data)
+}
+
+func (el *pbfDecoderStt) varint() (uint64, error) {
+	value, length := binary.Uvarint(el.data[el.pos:])
+	if length <= 0 {
+		return 0, errPbfTruncated
+	}
+
+	el.pos += length
+	return value, nil
+}
+
+// next returns the field number and wire type of the next key.
+func (el *pbfDecoderStt) next() (int, int, error) {
+	key, err := el.varint()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int(key >> 3), int(key & 0x7), nil
+}
+
+func (el *pbfDecoderStt) bytes() ([]byte, error) {
+	length, err := el.varint()
+	if err != nil {
+		return nil, err
+	}
+
+	if length > uint64(len(el.data)-el.pos) {
+		return nil, errPbfTruncated
+	}
+
+	ret := el.data[el.pos : el.pos+int(length)]
+	el.pos += int(length)
+
+	return ret, nil
+}
+
+func (el *pbfDecoderStt) skip(wireType int) error {
+	var err error
+
+	switch wireType {
+	case pbfWireVarint:
+		_, err = el.varint()
+	case pbfWire64Bit:
+		el.pos += 8
+	case pbfWireBytes:
+		_, err = el.bytes()
+	case pbfWire32Bit:
+		el.pos += 4
+	default:
+		return errors.New("pbf: unsupported wire type")
+	}
+
+	if err == nil && el.pos > len(el.data) {
+		err = errPbfTruncated
+	}
+
+	return err
+}
+
+// varints decodes a repeated varint field that can be written packed or not.
+func (el *pbfDecoderStt) varints(wireType int, list []uint64) ([]uint64, error) {
+	if wireType == pbfWireVarint {
+		value, err := el.varint()
+		return append(list, value), err
+	}
+
+	data, err := el.bytes()
+	if err != nil {
+		return list, err
+	}
+
+	for len(data) > 0 {
+		value, length := binary.Uvarint(data)
+		if length <= 0 {
+			return list, errPbfTruncated
+		}
+		list = append(list, value)
+		data = data[length:]
+	}
+
+	return list, nil
+}
+
+func pbfZigZag(value uint64) int64 {
+	return int64(value>>1) ^ -int64(value&1)
+}
+
+func pbfZigZagEncode(value int64) uint64 {
+	return uint64(value<<1) ^ uint64(value>>63)
+}
+
+type pbfEncoderStt struct {
+	data []byte
+}
+
+func (el *pbfEncoderStt) varint(value uint64) {
+	el.data = binary.AppendUvarint(el.data, value)
+}
+
+func (el *pbfEncoderStt) key(field, wireType int) {
+	el.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (el *pbfEncoderStt) uint(field int, value uint64) {
+	el.key(field, pbfWireVarint)
+	el.varint(value)
+}
+
+func (el *pbfEncoderStt) sint(field int, value int64) {
+	el.key(field, pbfWireVarint)
+	el.varint(pbfZigZagEncode(value))
+}
+
+func (el *pbfEncoderStt) bytes(field int, value []byte) {
+	el.key(field, pbfWireBytes)
+	el.varint(uint64(len(value)))
+	el.data = append(el.data, value...)
+}
+
+func (el *pbfEncoderStt) string(field int, value string) {
+	el.key(field, pbfWireBytes)
+	el.varint(uint64(len(value)))
+	el.data = append(el.data, value...)
+}
+
+// packed writes a packed repeated varint field. Empty lists are not written.
+func (el *pbfEncoderStt) packed(field int, list []uint64) {
+	if len(list) == 0 {
+		return
+	}
+
+	var buffer = make([]byte, 0, len(list)*2)
+	for _, value := range list {
+		buffer = binary.AppendUvarint(buffer, value)
+	}
+
+	el.bytes(field, buffer)
+}
@@ -0,0 +1,1079 @@
+package iotmaker_geo_osm
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/helmutkemper/zstd"
+	"io"
+	"os"
+	"runtime"
+	"time"
+)
+
+const (
+	// English: maximum size of a BlobHeader, as defined by the PBF specification
+	//
+	// Português: tamanho máximo de um BlobHeader, como definido pela especificação PBF
+	OsmPbfMaxBlobHeaderSize = 64 * 1024
+
+	// English: maximum size of a Blob, as defined by the PBF specification
+	//
+	// Português: tamanho máximo de um Blob, como definido pela especificação PBF
+	OsmPbfMaxBlobSize = 32 * 1024 * 1024
+)
+
+// English: Header of an OSM PBF file
+//
+// Português: Cabeçalho de um arquivo OSM PBF
+type OsmPbfHeaderStt struct {
+	// English: bounding box of the data, when present in the file
+	//
+	// Português: caixa de perímetro dos dados, quando presente no arquivo
+	BBox                      BoxStt
+	RequiredFeatures          []string
+	OptionalFeatures          []string
+	WritingProgram            string
+	Source                    string
+	ReplicationTimestamp      time.Time
+	ReplicationSequenceNumber int64
+	ReplicationBaseUrl        string
+}
+
+// English: Reader of OpenStreetMaps PBF files (.osm.pbf).
+//
+// The blobs are read in sequence and decoded by a pool of workers, then delivered to the callbacks in the same order
+// they are in the file, so the ways are resolved with the nodes that came before them.
+//
+// Português: Leitor de arquivos PBF do OpenStreetMaps (.osm.pbf).
+//
+// Os blobs são lidos em sequência e decodificados por um grupo de workers, depois entregues às funções de retorno na
+// mesma ordem em que estão no arquivo, por isto os ways são resolvidos com os nodes que vieram antes deles.
+type OsmPbfReaderStt struct {
+	// English: called for each node. May be nil.
+	//
+	// Português: chamada para cada node. Pode ser nil.
+	OnPoint func(point *PointStt) error
+
+	// English: called for each way, with IdNode, Loc and Rad already resolved and Init() called. May be nil.
+	//
+	// Português: chamada para cada way, com IdNode, Loc e Rad resolvidos e Init() chamado. Pode ser nil.
+	OnWay func(way *WayStt) error
+
+	// English: called for each relation. May be nil.
+	//
+	// Português: chamada para cada relation. Pode ser nil.
+	OnRelation func(relation *RelationStt) error
+
+	// English: location of the nodes used by the ways. When nil, a NodeLocationMapStt is used.
+	//
+	// Português: localização dos nodes usada pelos ways. Quando nil, um NodeLocationMapStt é usado.
+	NodeLocation NodeLocationStore
+
+	// English: when true, node references of a way not found in NodeLocation are dropped instead of returning an error.
+	//
+	// Português: quando true, referências de nodes de um way não encontradas em NodeLocation são descartadas ao invés de
+	// devolver um erro.
+	IgnoreMissingNodes bool
+
+	// English: number of blobs decoded at the same time. When zero, runtime.NumCPU() is used.
+	//
+	// Português: quantidade de blobs decodificados ao mesmo tempo. Quando zero, runtime.NumCPU() é usado.
+	Workers int
+
+	// English: header of the file, filled by Decode()
+	//
+	// Português: cabeçalho do arquivo, preenchido por Decode()
+	Header OsmPbfHeaderStt
+}
+
+type osmPbfBlobStt struct {
+	blobType string
+	data     []byte
+	result   chan osmPbfBlockStt
+}
+
+type osmPbfBlockStt struct {
+	header    *OsmPbfHeaderStt
+	points    []PointStt
+	ways      []WayStt
+	relations []RelationStt
+	err       error
+}
+
+// English: Opens and decodes an OpenStreetMaps PBF file.
+//
+// Português: Abre e decodifica um arquivo PBF do OpenStreetMaps.
+func (el *OsmPbfReaderStt) DecodeFilePath(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return el.Decode(file)
+}
+
+// English: Decodes an OpenStreetMaps PBF stream, calling OnPoint, OnWay and OnRelation for each element found.
+//
+// The decoding stops at the first error returned by a callback.
+//
+// Português: Decodifica um fluxo PBF do OpenStreetMaps, chamando OnPoint, OnWay e OnRelation para cada elemento
+// encontrado.
+//
+// A decodificação para no primeiro erro devolvido por uma função de retorno.
+func (el *OsmPbfReaderStt) Decode(reader io.Reader) error {
+	var err error
+	var workers = el.Workers
+	var done = make(chan struct{})
+	var jobs = make(chan osmPbfBlobStt)
+	var results = make(chan chan osmPbfBlockStt, 4*runtime.NumCPU())
+	var readErr error
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	if el.NodeLocation == nil {
+		el.NodeLocation = &NodeLocationMapStt{}
+	}
+
+	defer close(done)
+
+	for i := 0; i != workers; i += 1 {
+		go func() {
+			for blob := range jobs {
+				blob.result <- osmPbfDecodeBlob(blob.blobType, blob.data)
+			}
+		}()
+	}
+
+	// the results channel keeps the order of the file, while jobs are decoded in any order
+	go func() {
+		defer close(jobs)
+		defer close(results)
+
+		var buffered = bufio.NewReaderSize(reader, 1024*1024)
+		for {
+			blobType, data, err := osmPbfReadBlob(buffered)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				readErr = err
+				return
+			}
+
+			var blob = osmPbfBlobStt{blobType: blobType, data: data, result: make(chan osmPbfBlockStt, 1)}
+
+			select {
+			case results <- blob.result:
+			case <-done:
+				return
+			}
+
+			select {
+			case jobs <- blob:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for result := range results {
+		block := <-result
+		if block.err != nil {
+			return block.err
+		}
+
+		err = el.emit(&block)
+		if err != nil {
+			return err
+		}
+	}
+
+	return readErr
+}
+
+func (el *OsmPbfReaderStt) emit(block *osmPbfBlockStt) error {
+	var err error
+
+	if block.header != nil {
+		el.Header = *block.header
+
+		for _, feature := range el.Header.RequiredFeatures {
+			switch feature {
+			case "OsmSchema-V0.6", "DenseNodes", "HistoricalInformation":
+			default:
+				return fmt.Errorf("pbf: required feature %v is not supported", feature)
+			}
+		}
+	}
+
+	for k := range block.points {
+		err = el.NodeLocation.Set(block.points[k].Id, block.points[k].Loc)
+		if err != nil {
+			return err
+		}
+
+		if el.OnPoint != nil {
+			err = el.OnPoint(&block.points[k])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for k := range block.ways {
+		err = el.resolveWay(&block.ways[k])
+		if err != nil {
+			return err
+		}
+
+		if el.OnWay != nil {
+			err = el.OnWay(&block.ways[k])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if el.OnRelation != nil {
+		for k := range block.relations {
+			err = el.OnRelation(&block.relations[k])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (el *OsmPbfReaderStt) resolveWay(way *WayStt) error {
+	var refs = way.IdNode
+
+	way.IdNode = make([]int64, 0, len(refs))
+	way.Loc = make([][2]float64, 0, len(refs))
+	way.Rad = make([][2]float64, 0, len(refs))
+
+	for _, ref := range refs {
+		loc, found := el.NodeLocation.Get(ref)
+		if !found {
+			if el.IgnoreMissingNodes {
+				continue
+			}
+
+			return fmt.Errorf("pbf: way %v: node %v not found", way.Id, ref)
+		}
+
+		way.IdNode = append(way.IdNode, ref)
+		way.Loc = append(way.Loc, loc)
+		way.Rad = append(way.Rad, [2]float64{DegreesToRadians(loc[0]), DegreesToRadians(loc[1])})
+	}
+
+	if len(way.Loc) == 0 {
+		return nil
+	}
+
+	return way.Init()
+}
+
+// osmPbfReadBlob reads the next BlobHeader and the raw bytes of its Blob.
+func osmPbfReadBlob(reader io.Reader) (string, []byte, error) {
+	var err error
+	var size = make([]byte, 4)
+	var blobType string
+	var dataSize uint64
+	var field, wireType int
+
+	_, err = io.ReadFull(reader, size)
+	if err != nil {
+		return "", nil, err
+	}
+
+	headerSize := binary.BigEndian.Uint32(size)
+	if headerSize > OsmPbfMaxBlobHeaderSize {
+		return "", nil, fmt.Errorf("pbf: blob header too big: %v bytes", headerSize)
+	}
+
+	var header = make([]byte, headerSize)
+	_, err = io.ReadFull(reader, header)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var decoder = pbfDecoderStt{data: header}
+	for decoder.more() {
+		field, wireType, err = decoder.next()
+		if err != nil {
+			return "", nil, err
+		}
+
+		switch field {
+		case 1:
+			var value []byte
+			value, err = decoder.bytes()
+			blobType = string(value)
+		case 3:
+			dataSize, err = decoder.varint()
+		default:
+			err = decoder.skip(wireType)
+		}
+
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if dataSize > OsmPbfMaxBlobSize {
+		return "", nil, fmt.Errorf("pbf: blob too big: %v bytes", dataSize)
+	}
+
+	var data = make([]byte, dataSize)
+	_, err = io.ReadFull(reader, data)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return blobType, data, err
+}
+
+func osmPbfDecodeBlob(blobType string, blob []byte) osmPbfBlockStt {
+	var block osmPbfBlockStt
+
+	data, err := osmPbfUncompressBlob(blob)
+	if err != nil {
+		block.err = err
+		return block
+	}
+
+	switch blobType {
+	case "OSMHeader":
+		block.header, block.err = osmPbfDecodeHeader(data)
+	case "OSMData":
+		block.err = osmPbfDecodePrimitiveBlock(data, &block)
+	}
+
+	return block
+}
+
+func osmPbfUncompressBlob(blob []byte) ([]byte, error) {
+	var err error
+	var field, wireType int
+	var rawSize uint64
+	var hasRawSize bool
+	var raw, zlibData, zstdData []byte
+
+	var decoder = pbfDecoderStt{data: blob}
+	for decoder.more() {
+		field, wireType, err = decoder.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch field {
+		case 1:
+			raw, err = decoder.bytes()
+		case 2:
+			rawSize, err = decoder.varint()
+			hasRawSize = true
+		case 3:
+			zlibData, err = decoder.bytes()
+		case 4, 5, 6:
+			return nil, errors.New("pbf: blob compression not supported")
+		case 7:
+			zstdData, err = decoder.bytes()
+		default:
+			err = decoder.skip(wireType)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !hasRawSize && (zlibData != nil || zstdData != nil) {
+		return nil, errors.New("pbf: compressed blob without raw size")
+	}
+
+	// raw_size is an int32, so a negative one arrives as a huge varint
+	if rawSize > OsmPbfMaxBlobSize {
+		return nil, fmt.Errorf("pbf: blob raw size out of range: %v bytes", int64(rawSize))
+	}
+
+	switch {
+	case raw != nil:
+		return raw, nil
+
+	case zlibData != nil:
+		reader, err := zlib.NewReader(bytes.NewReader(zlibData))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+
+		var data = make([]byte, rawSize)
+		_, err = io.ReadFull(reader, data)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("pbf: blob shorter than its raw size of %v bytes", rawSize)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// the stream must end at the raw size, and its checksum is only verified at the end
+		var extra [1]byte
+		_, err = io.ReadFull(reader, extra[:])
+		if err == nil {
+			return nil, fmt.Errorf("pbf: blob longer than its raw size of %v bytes", rawSize)
+		}
+		if err != io.EOF {
+			return nil, err
+		}
+
+		return data, nil
+
+	case zstdData != nil:
+		data, err := zstd.Decompress(make([]byte, 0, rawSize), zstdData)
+		if err == nil && uint64(len(data)) != rawSize {
+			return nil, fmt.Errorf("pbf: blob of %v bytes with a raw size of %v bytes", len(data), rawSize)
+		}
+
+		return data, err
+	}
+
+	return nil, errors.New("pbf: empty blob")
+}
+
+func osmPbfDecodeHeader(data []byte) (*OsmPbfHeaderStt, error) {
+	var err error
+	var field, wireType int
+	var value []byte
+	var number uint64
+	var header = OsmPbfHeaderStt{}
+
+	var decoder = pbfDecoderStt{data: data}
+	for decoder.more() {
+		field, wireType, err = decoder.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch field {
+		case 1:
+			value, err = decoder.bytes()
+			if err == nil {
+				err = osmPbfDecodeHeaderBBox(value, &header.BBox)
+			}
+		case 4, 5, 16, 17, 34:
+			value, err = decoder.bytes()
+			switch field {
+			case 4:
+				header.RequiredFeatures = append(header.RequiredFeatures, string(value))
+			case 5:
+				header.OptionalFeatures = append(header.OptionalFeatures, string(value))
+			case 16:
+				header.WritingProgram = string(value)
+			case 17:
+				header.Source = string(value)
+			case 34:
+				header.ReplicationBaseUrl = string(value)
+			}
+		case 32:
+			number, err = decoder.varint()
+			header.ReplicationTimestamp = time.Unix(int64(number), 0).UTC()
+		case 33:
+			number, err = decoder.varint()
+			header.ReplicationSequenceNumber = int64(number)
+		default:
+			err = decoder.skip(wireType)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &header, nil
+}
+
+func osmPbfDecodeHeaderBBox(data []byte, box *BoxStt) error {
+	var err error
+	var field, wireType int
+	var value uint64
+	// left, right, top, bottom in nanodegrees
+	var limits [5]float64
+
+	var decoder = pbfDecoderStt{data: data}
+	for decoder.more() {
+		field, wireType, err = decoder.next()
+		if err != nil {
+			return err
+		}
+
+		if field >= 1 && field <= 4 {
+			value, err = decoder.varint()
+			limits[field] = float64(pbfZigZag(value)) / 1e9
+		} else {
+			err = decoder.skip(wireType)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	box.BottomLeft.SetLngLatDegrees(limits[1], limits[4])
+	box.UpperRight.SetLngLatDegrees(limits[2], limits[3])
+
+	return nil
+}
+
+// osmPbfPrimitiveBlockStt keeps the values shared by all groups of a PrimitiveBlock.
+type osmPbfPrimitiveBlockStt struct {
+	strings         []string
+	granularity     int64
+	latOffset       int64
+	lonOffset       int64
+	dateGranularity int64
+}
+
+func (el *osmPbfPrimitiveBlockStt) string(index uint64) string {
+	if index >= uint64(len(el.strings)) {
+		return ""
+	}
+
+	return el.strings[index]
+}
+
+func (el *osmPbfPrimitiveBlockStt) lng(value int64) float64 {
+	return float64(el.lonOffset+el.granularity*value) / 1e9
+}
+
+func (el *osmPbfPrimitiveBlockStt) lat(value int64) float64 {
+	return float64(el.latOffset+el.granularity*value) / 1e9
+}
+
+func (el *osmPbfPrimitiveBlockStt) timeStamp(value int64) time.Time {
+	return time.Unix(0, value*el.dateGranularity*int64(time.Millisecond)).UTC()
+}
+
+func (el *osmPbfPrimitiveBlockStt) tags(keys, values []uint64) map[string]string {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	var tags = make(map[string]string, len(keys))
+	for k := range keys {
+		if k < len(values) {
+			tags[el.string(keys[k])] = el.string(values[k])
+		}
+	}
+
+	return tags
+}
+
+func osmPbfDecodePrimitiveBlock(data []byte, block *osmPbfBlockStt) error {
+	var err error
+	var field, wireType int
+	var value []byte
+	var number uint64
+	var groups = make([][]byte, 0)
+	var primitive = osmPbfPrimitiveBlockStt{
+		granularity:     100,
+		dateGranularity: 1000,
+	}
+
+	var decoder = pbfDecoderStt{data: data}
+	for decoder.more() {
+		field, wireType, err = decoder.next()
+		if err != nil {
+			return err
+		}
+
+		switch field {
+		case 1:
+			value, err = decoder.bytes()
+			if err == nil {
+				primitive.strings, err = osmPbfDecodeStringTable(value)
+			}
+		case 2:
+			value, err = decoder.bytes()
+			groups = append(groups, value)
+		case 17:
+			number, err = decoder.varint()
+			primitive.granularity = int64(number)
+		case 18:
+			number, err = decoder.varint()
+			primitive.dateGranularity = int64(number)
+		case 19:
+			number, err = decoder.varint()
+			primitive.latOffset = int64(number)
+		case 20:
+			number, err = decoder.varint()
+			primitive.lonOffset = int64(number)
+		default:
+			err = decoder.skip(wireType)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// groups are decoded at the end because granularity and offsets may come after them
+	for _, group := range groups {
+		err = primitive.decodeGroup(group, block)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func osmPbfDecodeStringTable(data []byte) ([]string, error) {
+	var list = make([]string, 0)
+
+	var decoder = pbfDecoderStt{data: data}
+	for decoder.more() {
+		field, wireType, err := decoder.next()
+		if err != nil {
+			return nil, err
+		}
+
+		if field != 1 {
+			err = decoder.skip(wireType)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		value, err := decoder.bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, string(value))
+	}
+
+	return list, nil
+}
+
+func (el *osmPbfPrimitiveBlockStt) decodeGroup(data []byte, block *osmPbfBlockStt) error {
+	var err error
+	var field, wireType int
+	var value []byte
+
+	var decoder = pbfDecoderStt{data: data}
+	for decoder.more() {
+		field, wireType, err = decoder.next()
+		if err != nil {
+			return err
+		}
+
+		if wireType != pbfWireBytes {
+			err = decoder.skip(wireType)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err = decoder.bytes()
+		if err != nil {
+			return err
+		}
+
+		switch field {
+		case 1:
+			err = el.decodeNode(value, block)
+		case 2:
+			err = el.decodeDenseNodes(value, block)
+		case 3:
+			err = el.decodeWay(value, block)
+		case 4:
+			err = el.decodeRelation(value, block)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// osmPbfInfoStt is the decoded form of the Info message.
+type osmPbfInfoStt struct {
+	version   int64
+	timeStamp time.Time
+	changeSet int64
+	uId       int64
+	user      string
+	visible   bool
+}
+
+func (el *osmPbfPrimitiveBlockStt) decodeInfo(data []byte) (osmPbfInfoStt, error) {
+	var info = osmPbfInfoStt{visible: true}
+
+	var decoder = pbfDecoderStt{data: data}
+	for decoder.more() {
+		field, wireType, err := decoder.next()
+		if err != nil {
+			return info, err
+		}
+
+		if wireType != pbfWireVarint {
+			err = decoder.skip(wireType)
+			if err != nil {
+				return info, err
+			}
+			continue
+		}
+
+		value, err := decoder.varint()
+		if err != nil {
+			return info, err
+		}
+
+		switch field {
+		case 1:
+			info.version = int64(int32(value))
+		case 2:
+			info.timeStamp = el.timeStamp(int64(value))
+		case 3:
+			info.changeSet = int64(value)
+		case 4:
+			info.uId = int64(int32(value))
+		case 5:
+			info.user = el.string(value)
+		case 6:
+			info.visible = value != 0
+		}
+	}
+
+	return info, nil
+}
+
+func (el *osmPbfPrimitiveBlockStt) decodeNode(data []byte, block *osmPbfBlockStt) error {
+	var err error
+	var field, wireType int
+	var value uint64
+	var lat, lng int64
+	var keys, values []uint64
+	var info = osmPbfInfoStt{visible: true}
+	var point = PointStt{}
+
+	var decoder = pbfDecoderStt{data: data}
+	for decoder.more() {
+		field, wireType, err = decoder.next()
+		if err != nil {
+			return err
+		}
+
+		switch field {
+		case 1:
+			value, err = decoder.varint()
+			point.Id = pbfZigZag(value)
+		case 2:
+			keys, err = decoder.varints(wireType, keys)
+		case 3:
+			values, err = decoder.varints(wireType, values)
+		case 4:
+			var message []byte
+			message, err = decoder.bytes()
+			if err == nil {
+				info, err = el.decodeInfo(message)
+			}
+		case 8:
+			value, err = decoder.varint()
+			lat = pbfZigZag(value)
+		case 9:
+			value, err = decoder.varint()
+			lng = pbfZigZag(value)
+		default:
+			err = decoder.skip(wireType)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	point.SetLngLatDegrees(el.lng(lng), el.lat(lat))
+	point.Version, point.TimeStamp, point.ChangeSet = info.version, info.timeStamp, info.changeSet
+	point.UId, point.User, point.Visible = info.uId, info.user, info.visible
+	point.Tag, point.International = splitInternationalTags(el.tags(keys, values))
+
+	block.points = append(block.points, point)
+
+	return nil
+}
+
+func (el *osmPbfPrimitiveBlockStt) decodeDenseNodes(data []byte, block *osmPbfBlockStt) error {
+	var err error
+	var field, wireType int
+	var ids, lats, lngs, keysValues []uint64
+	var denseInfo []byte
+
+	var decoder = pbfDecoderStt{data: data}
+	for decoder.more() {
+		field, wireType, err = decoder.next()
+		if err != nil {
+			return err
+		}
+
+		switch field {
+		case 1:
+			ids, err = decoder.varints(wireType, ids)
+		case 5:
+			denseInfo, err = decoder.bytes()
+		case 8:
+			lats, err = decoder.varints(wireType, lats)
+		case 9:
+			lngs, err = decoder.varints(wireType, lngs)
+		case 10:
+			keysValues, err = decoder.varints(wireType, keysValues)
+		default:
+			err = decoder.skip(wireType)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(lats) != len(ids) || len(lngs) != len(ids) {
+		return errors.New("pbf: dense nodes with inconsistent lengths")
+	}
+
+	var versions, timeStamps, changeSets, uIds, users, visibles []uint64
+	if denseInfo != nil {
+		decoder = pbfDecoderStt{data: denseInfo}
+		for decoder.more() {
+			field, wireType, err = decoder.next()
+			if err != nil {
+				return err
+			}
+
+			switch field {
+			case 1:
+				versions, err = decoder.varints(wireType, versions)
+			case 2:
+				timeStamps, err = decoder.varints(wireType, timeStamps)
+			case 3:
+				changeSets, err = decoder.varints(wireType, changeSets)
+			case 4:
+				uIds, err = decoder.varints(wireType, uIds)
+			case 5:
+				users, err = decoder.varints(wireType, users)
+			case 6:
+				visibles, err = decoder.varints(wireType, visibles)
+			default:
+				err = decoder.skip(wireType)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var id, lat, lng, timeStamp, changeSet, uId, user int64
+	var keyValueIndex int
+	var points = make([]PointStt, len(ids))
+
+	for k := range ids {
+		var point = &points[k]
+
+		id += pbfZigZag(ids[k])
+		lat += pbfZigZag(lats[k])
+		lng += pbfZigZag(lngs[k])
+
+		point.Id = id
+		point.Visible = true
+		point.SetLngLatDegrees(el.lng(lng), el.lat(lat))
+
+		if k < len(versions) {
+			point.Version = int64(int32(versions[k]))
+		}
+		if k < len(timeStamps) {
+			timeStamp += pbfZigZag(timeStamps[k])
+			point.TimeStamp = el.timeStamp(timeStamp)
+		}
+		if k < len(changeSets) {
+			changeSet += pbfZigZag(changeSets[k])
+			point.ChangeSet = changeSet
+		}
+		if k < len(uIds) {
+			uId += pbfZigZag(uIds[k])
+			point.UId = uId
+		}
+		if k < len(users) {
+			user += pbfZigZag(users[k])
+			point.User = el.string(uint64(user))
+		}
+		if k < len(visibles) {
+			point.Visible = visibles[k] != 0
+		}
+
+		// keys_vals is a list of key, value pairs and each node ends with a zero
+		if keyValueIndex < len(keysValues) {
+			var tags map[string]string
+			for keyValueIndex < len(keysValues) && keysValues[keyValueIndex] != 0 {
+				if keyValueIndex+1 >= len(keysValues) {
+					return errors.New("pbf: dense nodes with truncated keys_vals")
+				}
+				if tags == nil {
+					tags = make(map[string]string)
+				}
+				tags[el.string(keysValues[keyValueIndex])] = el.string(keysValues[keyValueIndex+1])
+				keyValueIndex += 2
+			}
+			keyValueIndex += 1
+
+			point.Tag, point.International = splitInternationalTags(tags)
+		}
+	}
+
+	block.points = append(block.points, points...)
+
+	return nil
+}
+
+func (el *osmPbfPrimitiveBlockStt) decodeWay(data []byte, block *osmPbfBlockStt) error {
+	var err error
+	var field, wireType int
+	var value uint64
+	var keys, values, refs []uint64
+	var info = osmPbfInfoStt{visible: true}
+	var way = WayStt{}
+
+	var decoder = pbfDecoderStt{data: data}
+	for decoder.more() {
+		field, wireType, err = decoder.next()
+		if err != nil {
+			return err
+		}
+
+		switch field {
+		case 1:
+			value, err = decoder.varint()
+			way.Id = int64(value)
+		case 2:
+			keys, err = decoder.varints(wireType, keys)
+		case 3:
+			values, err = decoder.varints(wireType, values)
+		case 4:
+			var message []byte
+			message, err = decoder.bytes()
+			if err == nil {
+				info, err = el.decodeInfo(message)
+			}
+		case 8:
+			refs, err = decoder.varints(wireType, refs)
+		default:
+			err = decoder.skip(wireType)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// refs are resolved into Loc and Rad when the block is emitted, in the order of the file
+	var ref int64
+	way.IdNode = make([]int64, len(refs))
+	for k := range refs {
+		ref += pbfZigZag(refs[k])
+		way.IdNode[k] = ref
+	}
+
+	way.Version, way.TimeStamp, way.ChangeSet = info.version, info.timeStamp, info.changeSet
+	way.UId, way.User, way.Visible = info.uId, info.user, info.visible
+	way.Tag, way.International = splitInternationalTags(el.tags(keys, values))
+
+	block.ways = append(block.ways, way)
+
+	return nil
+}
+
+func (el *osmPbfPrimitiveBlockStt) decodeRelation(data []byte, block *osmPbfBlockStt) error {
+	var err error
+	var field, wireType int
+	var value uint64
+	var keys, values, roles, memberIds, types []uint64
+	var info = osmPbfInfoStt{visible: true}
+	var relation = RelationStt{}
+
+	var decoder = pbfDecoderStt{data: data}
+	for decoder.more() {
+		field, wireType, err = decoder.next()
+		if err != nil {
+			return err
+		}
+
+		switch field {
+		case 1:
+			value, err = decoder.varint()
+			relation.Id = int64(value)
+		case 2:
+			keys, err = decoder.varints(wireType, keys)
+		case 3:
+			values, err = decoder.varints(wireType, values)
+		case 4:
+			var message []byte
+			message, err = decoder.bytes()
+			if err == nil {
+				info, err = el.decodeInfo(message)
+			}
+		case 8:
+			roles, err = decoder.varints(wireType, roles)
+		case 9:
+			memberIds, err = decoder.varints(wireType, memberIds)
+		case 10:
+			types, err = decoder.varints(wireType, types)
+		default:
+			err = decoder.skip(wireType)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(roles) != len(memberIds) || len(types) != len(memberIds) {
+		return fmt.Errorf("pbf: relation %v with inconsistent members", relation.Id)
+	}
+
+	var memberId int64
+	for k := range memberIds {
+		memberId += pbfZigZag(memberIds[k])
+
+		var member = MembersStt{Ref: memberId, Role: el.string(roles[k])}
+		switch types[k] {
+		case 0:
+			member.Type = "node"
+		case 1:
+			member.Type = "way"
+		case 2:
+			member.Type = "relation"
+		}
+
+		relation.AddMember(member)
+	}
+
+	relation.Version, relation.TimeStamp, relation.ChangeSet = info.version, info.timeStamp, info.changeSet
+	relation.UId, relation.User, relation.Visible = info.uId, info.user, info.visible
+	relation.Tag, relation.International = splitInternationalTags(el.tags(keys, values))
+
+	block.relations = append(block.relations, relation)
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package iotmaker_geo_osm
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+)
+
+// osmPbfTestFileBlock frames the blob as a block of the file, with the BlobHeader of the type
+func osmPbfTestFileBlock(blobType string, blob pbfEncoderStt) []byte {
+	var header = pbfEncoderStt{}
+	header.string(1, blobType)
+	header.uint(3, uint64(len(blob.data)))
+
+	var block = binary.BigEndian.AppendUint32(nil, uint32(len(header.data)))
+	block = append(block, header.data...)
+
+	return append(block, blob.data...)
+}
+
+func ExampleOsmPbfReaderStt_Decode() {
+	// the coordinates are in microdegrees from an offset of 43 degrees west and 22 degrees south
+	var dense = pbfEncoderStt{}
+	dense.packed(1, []uint64{pbfZigZagEncode(1), pbfZigZagEncode(1)})
+	dense.packed(8, []uint64{pbfZigZagEncode(-900000), pbfZigZagEncode(100000)})
+	dense.packed(9, []uint64{pbfZigZagEncode(-200000), pbfZigZagEncode(100000)})
+
+	var group = pbfEncoderStt{}
+	group.bytes(2, dense.data)
+
+	var latOffset, lonOffset int64 = -22000000000, -43000000000
+
+	var primitive = pbfEncoderStt{}
+	primitive.bytes(1, []byte{})
+	primitive.bytes(2, group.data)
+	primitive.uint(17, 1000)
+	primitive.uint(19, uint64(latOffset))
+	primitive.uint(20, uint64(lonOffset))
+
+	var blob = pbfEncoderStt{}
+	blob.bytes(1, primitive.data)
+
+	var reader = OsmPbfReaderStt{
+		OnPoint: func(point *PointStt) error {
+			fmt.Printf("node %v %.6f\n", point.Id, point.Loc)
+			return nil
+		},
+	}
+	err := reader.Decode(bytes.NewReader(osmPbfTestFileBlock("OSMData", blob)))
+	fmt.Printf("error: %v\n", err)
+
+	// Output:
+	// node 1 [-43.200000 -22.900000]
+	// node 2 [-43.100000 -22.800000]
+	// error: <nil>
+}
+
+func ExampleOsmPbfReaderStt_Decode_rawSize() {
+	var compressed bytes.Buffer
+	var writer = zlib.NewWriter(&compressed)
+	_, _ = writer.Write([]byte{})
+	_ = writer.Close()
+
+	// a raw size bigger than a blob can be, and a negative one
+	var negative int64 = -1
+	for _, rawSize := range []uint64{OsmPbfMaxBlobSize + 1, uint64(negative)} {
+		var blob = pbfEncoderStt{}
+		blob.uint(2, rawSize)
+		blob.bytes(3, compressed.Bytes())
+
+		var reader = OsmPbfReaderStt{}
+		err := reader.Decode(bytes.NewReader(osmPbfTestFileBlock("OSMData", blob)))
+		fmt.Printf("error: %v\n", err)
+	}
+
+	// Output:
+	// error: pbf: blob raw size out of range: 33554433 bytes
+	// error: pbf: blob raw size out of range: -1 bytes
+}
+
+func ExampleOsmPbfReaderStt_Decode_compressed() {
+	var compressed bytes.Buffer
+	var writer = zlib.NewWriter(&compressed)
+	_, _ = writer.Write([]byte{0x0a, 0x00, 0x12, 0x00})
+	_ = writer.Close()
+
+	// without a raw size, with one shorter than the data, one longer than the data and the right one
+	for _, rawSize := range []int{-1, 2, 8, 4} {
+		var blob = pbfEncoderStt{}
+		if rawSize >= 0 {
+			blob.uint(2, uint64(rawSize))
+		}
+		blob.bytes(3, compressed.Bytes())
+
+		var reader = OsmPbfReaderStt{}
+		err := reader.Decode(bytes.NewReader(osmPbfTestFileBlock("OSMData", blob)))
+		fmt.Printf("error: %v\n", err)
+	}
+
+	// Output:
+	// error: pbf: compressed blob without raw size
+	// error: pbf: blob longer than its raw size of 2 bytes
+	// error: pbf: blob shorter than its raw size of 8 bytes
+	// error: <nil>
+}
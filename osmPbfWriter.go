@@ -0,0 +1,584 @@
+package iotmaker_geo_osm
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"time"
+)
+
+// English: maximum number of entities of each type in a PrimitiveBlock
+//
+// Português: quantidade máxima de entidades de cada tipo em um PrimitiveBlock
+const OsmPbfEntitiesPerBlock = 8000
+
+// English: Writer of OpenStreetMaps PBF files (.osm.pbf), readable by osmium, JOSM and other tools.
+//
+// Entities are buffered and written in blocks of nodes, ways and relations, in this order, with DenseNodes and a string
+// table per block. Ways without IdNode, as the ones made with AddLngLatDegrees(), receive new nodes with negative ids,
+// the same convention used by JOSM for new data.
+//
+// Close() must be called at the end to write the entities still buffered.
+//
+// Português: Escritor de arquivos PBF do OpenStreetMaps (.osm.pbf), legíveis pelo osmium, JOSM e outras ferramentas.
+//
+// As entidades são guardadas e escritas em blocos de nodes, ways e relations, nessa ordem, com DenseNodes e uma tabela
+// de strings por bloco. Ways sem IdNode, como os feitos com AddLngLatDegrees(), recebem nodes novos com ids negativos,
+// a mesma convenção usada pelo JOSM para dados novos.
+//
+// Close() deve ser chamado ao final para escrever as entidades ainda guardadas.
+type OsmPbfWriterStt struct {
+	// English: name of the program written in the header
+	//
+	// Português: nome do programa escrito no cabeçalho
+	WritingProgram string
+
+	// English: bounding box written in the header. Ignored when zero.
+	//
+	// Português: caixa de perímetro escrita no cabeçalho. Ignorada quando zero.
+	BBox BoxStt
+
+	writer        io.Writer
+	file          *os.File
+	headerWritten bool
+	lastId        int64
+
+	points    []PointStt
+	ways      []WayStt
+	relations []RelationStt
+}
+
+// English: Prepares the writer to write into writer.
+//
+// Português: Prepara o escritor para escrever em writer.
+func (el *OsmPbfWriterStt) Init(writer io.Writer) {
+	el.writer = writer
+	el.headerWritten = false
+	el.lastId = 0
+	el.points = make([]PointStt, 0)
+	el.ways = make([]WayStt, 0)
+	el.relations = make([]RelationStt, 0)
+}
+
+// English: Creates the file and prepares the writer to write into it. Close() closes the file.
+//
+// Português: Cria o arquivo e prepara o escritor para escrever nele. Close() fecha o arquivo.
+func (el *OsmPbfWriterStt) InitFilePath(filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	el.Init(file)
+	el.file = file
+
+	return nil
+}
+
+// English: Writes the collections of points, ways and relations, in this order.
+//
+// Português: Escreve as coleções de pontos, ways e relations, nessa ordem.
+func (el *OsmPbfWriterStt) Write(points []PointStt, ways []WayStt, relations []RelationStt) error {
+	var err error
+
+	for k := range points {
+		err = el.WritePoint(&points[k])
+		if err != nil {
+			return err
+		}
+	}
+
+	for k := range ways {
+		err = el.WriteWay(&ways[k])
+		if err != nil {
+			return err
+		}
+	}
+
+	for k := range relations {
+		err = el.WriteRelation(&relations[k])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// English: Adds a node to the file
+//
+// Português: Adiciona um node ao arquivo
+func (el *OsmPbfWriterStt) WritePoint(point *PointStt) error {
+	if len(el.ways) != 0 || len(el.relations) != 0 {
+		err := el.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	el.points = append(el.points, *point)
+
+	return el.flushIfFull()
+}
+
+// English: Adds a way to the file. When IdNode doesn't match Loc, new nodes are made from Loc.
+//
+// Português: Adiciona um way ao arquivo. Quando IdNode não corresponde a Loc, nodes novos são feitos a partir de Loc.
+func (el *OsmPbfWriterStt) WriteWay(way *WayStt) error {
+	if len(el.relations) != 0 {
+		err := el.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(way.IdNode) != len(way.Loc) {
+		var wayCopy = *way
+		wayCopy.IdNode = make([]int64, len(way.Loc))
+
+		for k, loc := range way.Loc {
+			var point = PointStt{Id: el.newId(), Visible: true}
+			point.SetLngLatDegrees(loc[0], loc[1])
+
+			wayCopy.IdNode[k] = point.Id
+			el.points = append(el.points, point)
+		}
+
+		way = &wayCopy
+	}
+
+	el.ways = append(el.ways, *way)
+
+	return el.flushIfFull()
+}
+
+// English: Adds a relation to the file
+//
+// Português: Adiciona uma relation ao arquivo
+func (el *OsmPbfWriterStt) WriteRelation(relation *RelationStt) error {
+	el.relations = append(el.relations, *relation)
+
+	return el.flushIfFull()
+}
+
+// English: Adds a polygon list to the file as a multipolygon relation with the id, version, timestamp, changeset and
+// user of the list. Each polygon becomes a closed way with role outer, and each of its Inner rings a closed way with
+// role inner, all with new negative ids.
+//
+// Português: Adiciona uma lista de polígonos ao arquivo como uma relation multipolygon com o id, versão, timestamp,
+// changeset e usuário da lista. Cada polígono vira um way fechado com papel outer, e cada um dos seus anéis Inner um
+// way fechado com papel inner, todos com ids negativos novos.
+func (el *OsmPbfWriterStt) WritePolygonList(polygonList *PolygonListStt) error {
+	var err error
+	var relation = RelationStt{
+		Id:        polygonList.Id,
+		Version:   polygonList.Version,
+		TimeStamp: polygonList.TimeStamp,
+		ChangeSet: polygonList.ChangeSet,
+		UId:       polygonList.UId,
+		User:      polygonList.User,
+		Visible:   polygonList.Visible,
+		Tag:       make(map[string]string),
+	}
+
+	for key, value := range polygonList.Tag {
+		relation.Tag[key] = value
+	}
+	relation.International = polygonList.International
+
+	if relation.Tag["type"] == "" {
+		relation.Tag["type"] = "multipolygon"
+	}
+
+	for k := range polygonList.List {
+		err = el.writePolygonRing(&relation, &polygonList.List[k], OSM_ROLE_OUTER)
+		if err != nil {
+			return err
+		}
+
+		for innerKey := range polygonList.List[k].Inner {
+			err = el.writePolygonRing(&relation, &polygonList.List[k].Inner[innerKey], OSM_ROLE_INNER)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return el.WriteRelation(&relation)
+}
+
+// writePolygonRing writes the ring of the polygon as a new closed way, member of the relation. The last node of the way
+// is the first one, also when the points of the polygon are not closed.
+func (el *OsmPbfWriterStt) writePolygonRing(relation *RelationStt, polygon *PolygonStt, role string) error {
+	var err error
+	var way = WayStt{Id: el.newId(), Visible: true}
+	var pointsList = polygon.PointsList
+
+	if len(pointsList) == 0 {
+		return errors.New("pbf: the polygon has no points")
+	}
+
+	var first = pointsList[0]
+	var last = pointsList[len(pointsList)-1]
+	if len(pointsList) > 1 && first.Loc[0] == last.Loc[0] && first.Loc[1] == last.Loc[1] {
+		pointsList = pointsList[:len(pointsList)-1]
+	}
+
+	for _, point := range pointsList {
+		var node = PointStt{Id: el.newId(), Visible: true}
+		node.SetLngLatDegrees(point.Loc[0], point.Loc[1])
+		el.points = append(el.points, node)
+
+		way.IdNode = append(way.IdNode, node.Id)
+		err = way.AddLngLatDegrees(point.Loc[0], point.Loc[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	way.IdNode = append(way.IdNode, way.IdNode[0])
+	err = way.AddLngLatDegrees(first.Loc[0], first.Loc[1])
+	if err != nil {
+		return err
+	}
+
+	err = el.WriteWay(&way)
+	if err != nil {
+		return err
+	}
+
+	relation.AddMember(MembersStt{Type: "way", Ref: way.Id, Role: role})
+
+	return nil
+}
+
+// English: Writes the buffered entities and, if the writer was made by InitFilePath(), closes the file.
+//
+// Português: Escreve as entidades guardadas e, se o escritor foi feito por InitFilePath(), fecha o arquivo.
+func (el *OsmPbfWriterStt) Close() error {
+	var err = el.Flush()
+
+	if el.file != nil {
+		closeErr := el.file.Close()
+		if err == nil {
+			err = closeErr
+		}
+		el.file = nil
+	}
+
+	return err
+}
+
+// English: Writes the buffered entities as PrimitiveBlocks.
+//
+// Português: Escreve as entidades guardadas como PrimitiveBlocks.
+func (el *OsmPbfWriterStt) Flush() error {
+	var err error
+
+	if el.writer == nil {
+		return errors.New("pbf: writer not initialized, call Init() first")
+	}
+
+	if !el.headerWritten {
+		err = el.writeBlob("OSMHeader", el.encodeHeader())
+		if err != nil {
+			return err
+		}
+		el.headerWritten = true
+	}
+
+	if len(el.points) != 0 {
+		err = el.writeBlob("OSMData", osmPbfEncodeDenseNodes(el.points))
+		if err != nil {
+			return err
+		}
+		el.points = el.points[:0]
+	}
+
+	if len(el.ways) != 0 {
+		err = el.writeBlob("OSMData", osmPbfEncodeWays(el.ways))
+		if err != nil {
+			return err
+		}
+		el.ways = el.ways[:0]
+	}
+
+	if len(el.relations) != 0 {
+		err = el.writeBlob("OSMData", osmPbfEncodeRelations(el.relations))
+		if err != nil {
+			return err
+		}
+		el.relations = el.relations[:0]
+	}
+
+	return nil
+}
+
+func (el *OsmPbfWriterStt) flushIfFull() error {
+	if len(el.points) >= OsmPbfEntitiesPerBlock || len(el.ways) >= OsmPbfEntitiesPerBlock || len(el.relations) >= OsmPbfEntitiesPerBlock {
+		return el.Flush()
+	}
+
+	return nil
+}
+
+func (el *OsmPbfWriterStt) newId() int64 {
+	el.lastId -= 1
+	return el.lastId
+}
+
+func (el *OsmPbfWriterStt) encodeHeader() []byte {
+	var header = pbfEncoderStt{}
+
+	if el.BBox.BottomLeft.Loc != [2]float64{} || el.BBox.UpperRight.Loc != [2]float64{} {
+		var bbox = pbfEncoderStt{}
+		bbox.sint(1, int64(math.Round(el.BBox.BottomLeft.Loc[0]*1e9)))
+		bbox.sint(2, int64(math.Round(el.BBox.UpperRight.Loc[0]*1e9)))
+		bbox.sint(3, int64(math.Round(el.BBox.UpperRight.Loc[1]*1e9)))
+		bbox.sint(4, int64(math.Round(el.BBox.BottomLeft.Loc[1]*1e9)))
+		header.bytes(1, bbox.data)
+	}
+
+	header.string(4, "OsmSchema-V0.6")
+	header.string(4, "DenseNodes")
+
+	if el.WritingProgram != "" {
+		header.string(16, el.WritingProgram)
+	}
+
+	return header.data
+}
+
+func (el *OsmPbfWriterStt) writeBlob(blobType string, data []byte) error {
+	var err error
+	var compressed bytes.Buffer
+
+	writer := zlib.NewWriter(&compressed)
+	_, err = writer.Write(data)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	var blob = pbfEncoderStt{}
+	blob.uint(2, uint64(len(data)))
+	blob.bytes(3, compressed.Bytes())
+
+	var header = pbfEncoderStt{}
+	header.string(1, blobType)
+	header.uint(3, uint64(len(blob.data)))
+
+	var size = make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(header.data)))
+
+	for _, part := range [][]byte{size, header.data, blob.data} {
+		_, err = el.writer.Write(part)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// osmPbfStringTableStt builds the string table of a PrimitiveBlock. Index zero is reserved.
+type osmPbfStringTableStt struct {
+	index map[string]uint64
+	list  []string
+}
+
+func (el *osmPbfStringTableStt) add(value string) uint64 {
+	if el.index == nil {
+		el.index = map[string]uint64{"": 0}
+		el.list = []string{""}
+	}
+
+	if index, found := el.index[value]; found {
+		return index
+	}
+
+	el.index[value] = uint64(len(el.list))
+	el.list = append(el.list, value)
+
+	return el.index[value]
+}
+
+// tags adds the Tag and International keys to the table and returns the indexes of keys and values.
+func (el *osmPbfStringTableStt) tags(tag, international map[string]string) (keys, values []uint64) {
+	for _, list := range []map[string]string{tag, international} {
+		for key, value := range list {
+			keys = append(keys, el.add(key))
+			values = append(values, el.add(value))
+		}
+	}
+
+	return
+}
+
+// block returns the encoded PrimitiveBlock with the string table and a single group.
+func (el *osmPbfStringTableStt) block(group []byte) []byte {
+	var table = pbfEncoderStt{}
+	el.add("")
+	for _, value := range el.list {
+		table.string(1, value)
+	}
+
+	var block = pbfEncoderStt{}
+	block.bytes(1, table.data)
+	block.bytes(2, group)
+
+	return block.data
+}
+
+func osmPbfTimeStamp(timeStamp time.Time) int64 {
+	if timeStamp.IsZero() {
+		return 0
+	}
+
+	return timeStamp.Unix()
+}
+
+func osmPbfEncodeInfo(table *osmPbfStringTableStt, version int64, timeStamp time.Time, changeSet, uId int64, user string) []byte {
+	var info = pbfEncoderStt{}
+
+	info.uint(1, uint64(version))
+	info.uint(2, uint64(osmPbfTimeStamp(timeStamp)))
+	info.uint(3, uint64(changeSet))
+	info.uint(4, uint64(uId))
+	info.uint(5, table.add(user))
+
+	return info.data
+}
+
+func osmPbfEncodeDenseNodes(points []PointStt) []byte {
+	var table = osmPbfStringTableStt{}
+	var ids, lats, lngs, keysValues = make([]uint64, len(points)), make([]uint64, len(points)), make([]uint64, len(points)), make([]uint64, 0)
+	var versions, timeStamps, changeSets, uIds, users = make([]uint64, len(points)), make([]uint64, len(points)), make([]uint64, len(points)), make([]uint64, len(points)), make([]uint64, len(points))
+	var lastId, lastLat, lastLng, lastTimeStamp, lastChangeSet, lastUId, lastUser int64
+	var hasTags bool
+
+	for k := range points {
+		var point = &points[k]
+
+		lat := int64(math.Round(point.Loc[1] * 1e7))
+		lng := int64(math.Round(point.Loc[0] * 1e7))
+		timeStamp := osmPbfTimeStamp(point.TimeStamp)
+		user := int64(table.add(point.User))
+
+		ids[k] = pbfZigZagEncode(point.Id - lastId)
+		lats[k] = pbfZigZagEncode(lat - lastLat)
+		lngs[k] = pbfZigZagEncode(lng - lastLng)
+		versions[k] = uint64(point.Version)
+		timeStamps[k] = pbfZigZagEncode(timeStamp - lastTimeStamp)
+		changeSets[k] = pbfZigZagEncode(point.ChangeSet - lastChangeSet)
+		uIds[k] = pbfZigZagEncode(point.UId - lastUId)
+		users[k] = pbfZigZagEncode(user - lastUser)
+
+		lastId, lastLat, lastLng = point.Id, lat, lng
+		lastTimeStamp, lastChangeSet, lastUId, lastUser = timeStamp, point.ChangeSet, point.UId, user
+
+		keys, values := table.tags(point.Tag, point.International)
+		for i := range keys {
+			keysValues = append(keysValues, keys[i], values[i])
+		}
+		keysValues = append(keysValues, 0)
+		hasTags = hasTags || len(keys) != 0
+	}
+
+	var info = pbfEncoderStt{}
+	info.packed(1, versions)
+	info.packed(2, timeStamps)
+	info.packed(3, changeSets)
+	info.packed(4, uIds)
+	info.packed(5, users)
+
+	var dense = pbfEncoderStt{}
+	dense.packed(1, ids)
+	dense.bytes(5, info.data)
+	dense.packed(8, lats)
+	dense.packed(9, lngs)
+	if hasTags {
+		dense.packed(10, keysValues)
+	}
+
+	var group = pbfEncoderStt{}
+	group.bytes(2, dense.data)
+
+	return table.block(group.data)
+}
+
+func osmPbfEncodeWays(ways []WayStt) []byte {
+	var table = osmPbfStringTableStt{}
+	var group = pbfEncoderStt{}
+
+	for k := range ways {
+		var way = &ways[k]
+		var encoder = pbfEncoderStt{}
+
+		keys, values := table.tags(way.Tag, way.International)
+
+		var refs = make([]uint64, len(way.IdNode))
+		var lastRef int64
+		for i, ref := range way.IdNode {
+			refs[i] = pbfZigZagEncode(ref - lastRef)
+			lastRef = ref
+		}
+
+		encoder.uint(1, uint64(way.Id))
+		encoder.packed(2, keys)
+		encoder.packed(3, values)
+		encoder.bytes(4, osmPbfEncodeInfo(&table, way.Version, way.TimeStamp, way.ChangeSet, way.UId, way.User))
+		encoder.packed(8, refs)
+
+		group.bytes(3, encoder.data)
+	}
+
+	return table.block(group.data)
+}
+
+func osmPbfEncodeRelations(relations []RelationStt) []byte {
+	var table = osmPbfStringTableStt{}
+	var group = pbfEncoderStt{}
+
+	for k := range relations {
+		var relation = &relations[k]
+		var encoder = pbfEncoderStt{}
+
+		keys, values := table.tags(relation.Tag, relation.International)
+
+		var roles = make([]uint64, len(relation.Members))
+		var memberIds = make([]uint64, len(relation.Members))
+		var types = make([]uint64, len(relation.Members))
+		var lastRef int64
+		for i, member := range relation.Members {
+			roles[i] = table.add(member.Role)
+			memberIds[i] = pbfZigZagEncode(member.Ref - lastRef)
+			lastRef = member.Ref
+
+			switch member.Type {
+			case "way":
+				types[i] = 1
+			case "relation":
+				types[i] = 2
+			}
+		}
+
+		encoder.uint(1, uint64(relation.Id))
+		encoder.packed(2, keys)
+		encoder.packed(3, values)
+		encoder.bytes(4, osmPbfEncodeInfo(&table, relation.Version, relation.TimeStamp, relation.ChangeSet, relation.UId, relation.User))
+		encoder.packed(8, roles)
+		encoder.packed(9, memberIds)
+		encoder.packed(10, types)
+
+		group.bytes(4, encoder.data)
+	}
+
+	return table.block(group.data)
+}
@@ -0,0 +1,109 @@
+package iotmaker_geo_osm
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+func ExampleOsmPbfWriterStt_Write() {
+	var buffer bytes.Buffer
+	var timeStamp = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var points = make([]PointStt, 2)
+	points[0] = PointStt{Id: 1, Version: 2, TimeStamp: timeStamp, ChangeSet: 10, UId: 7, User: "helmut", Tag: map[string]string{"amenity": "bench"}}
+	points[0].SetLngLatDegrees(-43.2, -22.9)
+	points[1] = PointStt{Id: 2, Version: 1, TimeStamp: timeStamp, ChangeSet: 10, UId: 7, User: "helmut"}
+	points[1].SetLngLatDegrees(-43.2, -22.8)
+
+	var ways = []WayStt{{Id: 3, Version: 4, ChangeSet: 11, IdNode: []int64{1, 2}, Tag: map[string]string{"highway": "residential"}, International: map[string]string{"name:pt": "Rua"}}}
+	ways[0].AddLngLatDegrees(-43.2, -22.9)
+	ways[0].AddLngLatDegrees(-43.2, -22.8)
+
+	var relations = []RelationStt{{Id: 4, Version: 1, Tag: map[string]string{"type": "route"}}}
+	relations[0].AddMember(MembersStt{Type: "way", Ref: 3, Role: "forward"})
+	relations[0].AddMember(MembersStt{Type: "node", Ref: 1, Role: "stop"})
+
+	var writer = OsmPbfWriterStt{WritingProgram: "iotmaker.geo.osm"}
+	writer.Init(&buffer)
+	err := writer.Write(points, ways, relations)
+	if err == nil {
+		err = writer.Close()
+	}
+	fmt.Printf("write error: %v\n", err)
+
+	var reader = OsmPbfReaderStt{
+		OnPoint: func(point *PointStt) error {
+			fmt.Printf("node %v v%v %v changeset %v uid %v %v %v %v\n", point.Id, point.Version, point.TimeStamp.Format(time.RFC3339), point.ChangeSet, point.UId, point.User, point.Loc, point.Tag)
+			return nil
+		},
+		OnWay: func(way *WayStt) error {
+			fmt.Printf("way %v v%v changeset %v nodes %v %v %v %v\n", way.Id, way.Version, way.ChangeSet, way.IdNode, way.Loc, way.Tag, way.International)
+			return nil
+		},
+		OnRelation: func(relation *RelationStt) error {
+			fmt.Printf("relation %v v%v members %v %v\n", relation.Id, relation.Version, relation.Members, relation.Tag)
+			return nil
+		},
+	}
+	err = reader.Decode(&buffer)
+	fmt.Printf("read error: %v %v\n", err, reader.Header.WritingProgram)
+
+	// Output:
+	// write error: <nil>
+	// node 1 v2 2019-01-01T00:00:00Z changeset 10 uid 7 helmut [-43.2 -22.9] map[amenity:bench]
+	// node 2 v1 2019-01-01T00:00:00Z changeset 10 uid 7 helmut [-43.2 -22.8] map[]
+	// way 3 v4 changeset 11 nodes [1 2] [[-43.2 -22.9] [-43.2 -22.8]] map[highway:residential] map[name:pt:Rua]
+	// relation 4 v1 members [{way 3 forward} {node 1 stop}] map[type:route]
+	// read error: <nil> iotmaker.geo.osm
+}
+
+func ExampleOsmPbfWriterStt_WritePolygonList() {
+	var buffer bytes.Buffer
+
+	var polygon PolygonStt
+	polygon.AddLngLatDegrees(-43.3, -22.9)
+	polygon.AddLngLatDegrees(-43.1, -22.9)
+	polygon.AddLngLatDegrees(-43.1, -22.7)
+	polygon.AddLngLatDegrees(-43.3, -22.7)
+
+	// the hole is closed by its points, the polygon is not
+	var hole PolygonStt
+	hole.AddLngLatDegrees(-43.25, -22.85)
+	hole.AddLngLatDegrees(-43.25, -22.75)
+	hole.AddLngLatDegrees(-43.15, -22.75)
+	hole.AddLngLatDegrees(-43.25, -22.85)
+	polygon.Inner = append(polygon.Inner, hole)
+
+	var list = PolygonListStt{Id: 1, Tag: map[string]string{"landuse": "forest"}}
+	list.AddPolygon(&polygon)
+
+	var writer = OsmPbfWriterStt{}
+	writer.Init(&buffer)
+	err := writer.WritePolygonList(&list)
+	if err == nil {
+		err = writer.Close()
+	}
+	fmt.Printf("write error: %v\n", err)
+
+	var reader = OsmPbfReaderStt{
+		OnWay: func(way *WayStt) error {
+			var closed = way.IdNode[0] == way.IdNode[len(way.IdNode)-1] && way.Loc[0] == way.Loc[len(way.Loc)-1]
+			fmt.Printf("way %v nodes %v closed %v\n", way.Id, way.IdNode, closed)
+			return nil
+		},
+		OnRelation: func(relation *RelationStt) error {
+			fmt.Printf("relation %v members %v %v\n", relation.Id, relation.Members, relation.Tag)
+			return nil
+		},
+	}
+	err = reader.Decode(&buffer)
+	fmt.Printf("read error: %v\n", err)
+
+	// Output:
+	// write error: <nil>
+	// way -1 nodes [-2 -3 -4 -5 -2] closed true
+	// way -6 nodes [-7 -8 -9 -7] closed true
+	// relation 1 members [{way -1 outer} {way -6 inner}] map[landuse:forest type:multipolygon]
+	// read error: <nil>
+}
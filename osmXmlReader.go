@@ -0,0 +1,329 @@
+package iotmaker_geo_osm
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// English: Streaming reader of OpenStreetMaps XML files (.osm).
+//
+// The file is decoded element by element and every node, way and relation is delivered to the callbacks as soon as it
+// is complete, so the memory used does not depend on the size of the file, except by the location of the nodes kept
+// in NodeLocation to resolve the references of the ways.
+//
+// Português: Leitor em fluxo de arquivos XML do OpenStreetMaps (.osm).
+//
+// O arquivo é decodificado elemento a elemento e cada node, way e relation é entregue às funções de retorno assim que
+// termina, por isto a memória usada não depende do tamanho do arquivo, exceto pela localização dos nodes guardada em
+// NodeLocation para resolver as referências dos ways.
+type OsmXmlReaderStt struct {
+	// English: called for each node. May be nil.
+	//
+	// Português: chamada para cada node. Pode ser nil.
+	OnPoint func(point *PointStt) error
+
+	// English: called for each way, with IdNode, Loc and Rad already resolved and Init() called. May be nil.
+	//
+	// Português: chamada para cada way, com IdNode, Loc e Rad resolvidos e Init() chamado. Pode ser nil.
+	OnWay func(way *WayStt) error
+
+	// English: called for each relation. May be nil.
+	//
+	// Português: chamada para cada relation. Pode ser nil.
+	OnRelation func(relation *RelationStt) error
+
+	// English: location of the nodes used by the ways. When nil, a NodeLocationMapStt is used.
+	//
+	// Português: localização dos nodes usada pelos ways. Quando nil, um NodeLocationMapStt é usado.
+	NodeLocation NodeLocationStore
+
+	// English: when true, node references of a way not found in NodeLocation are dropped instead of returning an error.
+	// Useful for clipped extracts.
+	//
+	// Português: quando true, referências de nodes de um way não encontradas em NodeLocation são descartadas ao invés de
+	// devolver um erro. Útil para recortes de mapas.
+	IgnoreMissingNodes bool
+
+	// English: when true, ways only receive IdNode and Loc, Rad and Init() are left to the caller. Used by files where
+	// the nodes of the ways may not be present, as osmChange files.
+	//
+	// Português: quando true, ways recebem apenas IdNode e Loc, Rad e Init() ficam a cargo de quem chama. Usado em
+	// arquivos onde os nodes dos ways podem não estar presentes, como os arquivos osmChange.
+	OnlyNodeReferences bool
+
+	action string
+}
+
+// English: Returns the osmChange action (create, modify or delete) that contains the element being delivered to the
+// callbacks, or an empty string outside of osmChange files.
+//
+// Português: Devolve a ação osmChange (create, modify ou delete) que contém o elemento sendo entregue às funções de
+// retorno, ou uma string vazia fora de arquivos osmChange.
+func (el *OsmXmlReaderStt) Action() string {
+	return el.action
+}
+
+// English: Opens and decodes an OpenStreetMaps XML file.
+//
+// Português: Abre e decodifica um arquivo XML do OpenStreetMaps.
+func (el *OsmXmlReaderStt) DecodeFilePath(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return el.Decode(file)
+}
+
+// English: Decodes an OpenStreetMaps XML stream, calling OnPoint, OnWay and OnRelation for each element found.
+//
+// The decoding stops at the first error returned by a callback.
+//
+// Português: Decodifica um fluxo XML do OpenStreetMaps, chamando OnPoint, OnWay e OnRelation para cada elemento
+// encontrado.
+//
+// A decodificação para no primeiro erro devolvido por uma função de retorno.
+func (el *OsmXmlReaderStt) Decode(reader io.Reader) error {
+	var err error
+	var token xml.Token
+	var decoder = xml.NewDecoder(reader)
+
+	var point *PointStt
+	var way *WayStt
+	var relation *RelationStt
+	var tags map[string]string
+	var ref int64
+
+	if el.NodeLocation == nil {
+		el.NodeLocation = &NodeLocationMapStt{}
+	}
+
+	for {
+		token, err = decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "create", "modify", "delete":
+				el.action = element.Name.Local
+
+			case "node":
+				point = &PointStt{}
+				tags = make(map[string]string)
+				err = el.decodeNode(point, element.Attr)
+
+			case "way":
+				way = &WayStt{}
+				tags = make(map[string]string)
+				err = osmXmlDecodeMeta(element.Attr, &way.Id, &way.Version, &way.TimeStamp, &way.ChangeSet, &way.UId, &way.User, &way.Visible)
+
+			case "relation":
+				relation = &RelationStt{}
+				tags = make(map[string]string)
+				err = osmXmlDecodeMeta(element.Attr, &relation.Id, &relation.Version, &relation.TimeStamp, &relation.ChangeSet, &relation.UId, &relation.User, &relation.Visible)
+
+			case "tag":
+				if tags != nil {
+					tags[osmXmlAttr(element.Attr, "k")] = osmXmlAttr(element.Attr, "v")
+				}
+
+			case "nd":
+				if way != nil {
+					ref, err = strconv.ParseInt(osmXmlAttr(element.Attr, "ref"), 10, 64)
+					if err == nil {
+						err = el.addNodeToWay(way, ref)
+					}
+				}
+
+			case "member":
+				if relation != nil {
+					err = el.addMemberToRelation(relation, element.Attr)
+				}
+			}
+
+		case xml.EndElement:
+			switch element.Name.Local {
+			case "create", "modify", "delete":
+				el.action = ""
+
+			case "node":
+				point.Tag, point.International = splitInternationalTags(tags)
+				tags = nil
+				if el.OnPoint != nil {
+					err = el.OnPoint(point)
+				}
+				point = nil
+
+			case "way":
+				way.Tag, way.International = splitInternationalTags(tags)
+				tags = nil
+				if len(way.Loc) != 0 {
+					err = way.Init()
+				}
+				if err == nil && el.OnWay != nil {
+					err = el.OnWay(way)
+				}
+				way = nil
+
+			case "relation":
+				relation.Tag, relation.International = splitInternationalTags(tags)
+				tags = nil
+				if el.OnRelation != nil {
+					err = el.OnRelation(relation)
+				}
+				relation = nil
+			}
+		}
+
+		if err != nil {
+			line, _ := decoder.InputPos()
+			return fmt.Errorf("osm xml, line %v: %v", line, err)
+		}
+	}
+}
+
+func (el *OsmXmlReaderStt) decodeNode(point *PointStt, attr []xml.Attr) error {
+	var err error
+	var lng, lat float64
+
+	err = osmXmlDecodeMeta(attr, &point.Id, &point.Version, &point.TimeStamp, &point.ChangeSet, &point.UId, &point.User, &point.Visible)
+	if err != nil {
+		return err
+	}
+
+	// deleted nodes of history files don't have coordinates
+	if osmXmlAttr(attr, "lon") == "" && osmXmlAttr(attr, "lat") == "" {
+		return nil
+	}
+
+	lng, err = strconv.ParseFloat(osmXmlAttr(attr, "lon"), 64)
+	if err != nil {
+		return err
+	}
+
+	lat, err = strconv.ParseFloat(osmXmlAttr(attr, "lat"), 64)
+	if err != nil {
+		return err
+	}
+
+	err = point.SetLngLatDegrees(lng, lat)
+	if err != nil {
+		return err
+	}
+
+	return el.NodeLocation.Set(point.Id, point.Loc)
+}
+
+func (el *OsmXmlReaderStt) addNodeToWay(way *WayStt, ref int64) error {
+	if el.OnlyNodeReferences {
+		way.IdNode = append(way.IdNode, ref)
+		return nil
+	}
+
+	loc, found := el.NodeLocation.Get(ref)
+	if !found {
+		if el.IgnoreMissingNodes {
+			return nil
+		}
+
+		return fmt.Errorf("way %v: node %v not found", way.Id, ref)
+	}
+
+	way.IdNode = append(way.IdNode, ref)
+	return way.AddLngLatDegrees(loc[0], loc[1])
+}
+
+func (el *OsmXmlReaderStt) addMemberToRelation(relation *RelationStt, attr []xml.Attr) error {
+	var err error
+	var member = MembersStt{
+		Type: osmXmlAttr(attr, "type"),
+		Role: osmXmlAttr(attr, "role"),
+	}
+
+	member.Ref, err = strconv.ParseInt(osmXmlAttr(attr, "ref"), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	relation.AddMember(member)
+
+	return nil
+}
+
+// osmXmlDecodeMeta decodes the attributes common to node, way and relation.
+func osmXmlDecodeMeta(attr []xml.Attr, id, version *int64, timeStamp *time.Time, changeSet, uId *int64, user *string, visible *bool) error {
+	var err error
+
+	*visible = true
+
+	for _, a := range attr {
+		switch a.Name.Local {
+		case "id":
+			*id, err = strconv.ParseInt(a.Value, 10, 64)
+		case "version":
+			*version, err = strconv.ParseInt(a.Value, 10, 64)
+		case "changeset":
+			*changeSet, err = strconv.ParseInt(a.Value, 10, 64)
+		case "uid":
+			*uId, err = strconv.ParseInt(a.Value, 10, 64)
+		case "user":
+			*user = a.Value
+		case "visible":
+			*visible = a.Value != "false"
+		case "timestamp":
+			*timeStamp, err = time.Parse(time.RFC3339, a.Value)
+		}
+
+		if err != nil {
+			return fmt.Errorf("attribute %v: %v", a.Name.Local, err)
+		}
+	}
+
+	return nil
+}
+
+func osmXmlAttr(attr []xml.Attr, name string) string {
+	for _, a := range attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+// English: Separates the tags as the package keeps them: every key started with 'name:' goes to International and the
+// rest goes to Tag. Empty maps are returned as nil.
+//
+// Português: Separa as tags da forma como o pacote as guarda: toda chave iniciada com 'name:' vai para International e
+// o resto vai para Tag. Maps vazios são devolvidos como nil.
+func splitInternationalTags(tags map[string]string) (tag, international map[string]string) {
+	for key, value := range tags {
+		if strings.HasPrefix(key, "name:") {
+			if international == nil {
+				international = make(map[string]string)
+			}
+			international[key] = value
+			continue
+		}
+
+		if tag == nil {
+			tag = make(map[string]string)
+		}
+		tag[key] = value
+	}
+
+	return
+}
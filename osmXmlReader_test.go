@@ -0,0 +1,51 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ExampleOsmXmlReaderStt_Decode() {
+	var osm = `<?xml version="1.0" encoding="UTF-8"?>
+<osm version="0.6">
+  <node id="1" version="2" changeset="10" uid="7" user="helmut" timestamp="2019-01-01T00:00:00Z" lat="-22.9" lon="-43.2">
+    <tag k="amenity" v="bench"/>
+  </node>
+  <node id="2" version="1" lat="-22.8" lon="-43.2"/>
+  <way id="3" version="4" changeset="11">
+    <nd ref="1"/>
+    <nd ref="2"/>
+    <tag k="highway" v="residential"/>
+    <tag k="name:pt" v="Rua"/>
+  </way>
+  <relation id="4" version="1">
+    <member type="way" ref="3" role="outer"/>
+    <tag k="type" v="multipolygon"/>
+  </relation>
+</osm>`
+
+	var reader = OsmXmlReaderStt{
+		OnPoint: func(point *PointStt) error {
+			fmt.Printf("node %v v%v changeset %v uid %v %v %v\n", point.Id, point.Version, point.ChangeSet, point.UId, point.Loc, point.Tag)
+			return nil
+		},
+		OnWay: func(way *WayStt) error {
+			fmt.Printf("way %v v%v nodes %v %v %v %v\n", way.Id, way.Version, way.IdNode, way.Loc, way.Tag, way.International)
+			return nil
+		},
+		OnRelation: func(relation *RelationStt) error {
+			fmt.Printf("relation %v members %v ways %v %v\n", relation.Id, relation.Members, relation.IdWay, relation.Tag)
+			return nil
+		},
+	}
+
+	err := reader.Decode(strings.NewReader(osm))
+	fmt.Printf("error: %v\n", err)
+
+	// Output:
+	// node 1 v2 changeset 10 uid 7 [-43.2 -22.9] map[amenity:bench]
+	// node 2 v1 changeset 0 uid 0 [-43.2 -22.8] map[]
+	// way 3 v4 nodes [1 2] [[-43.2 -22.9] [-43.2 -22.8]] map[highway:residential] map[name:pt:Rua]
+	// relation 4 members [{way 3 outer}] ways [3] map[type:multipolygon]
+	// error: <nil>
+}
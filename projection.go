@@ -0,0 +1,186 @@
+package iotmaker_geo_osm
+
+import (
+	"math"
+)
+
+// English: Map projection between longitude and latitude, in degrees, and planar coordinates, in meters.
+//
+// The projected geometries keep x and y in the fields of longitude and latitude, as set by SetXYDegrees(), so the
+// planar algorithms of the package, as the hulls and PointInPolygon(), work in meters.
+//
+// Português: Projeção cartográfica entre longitude e latitude, em graus, e coordenadas planas, em metros.
+//
+// As geometrias projetadas guardam x e y nos campos de longitude e latitude, como definido por SetXYDegrees(), então
+// os algoritmos planos do pacote, como os hulls e PointInPolygon(), trabalham em metros.
+type Projection interface {
+	Forward(longitude, latitude float64) (x, y float64)
+	Inverse(x, y float64) (longitude, latitude float64)
+}
+
+// English: Returns a copy of the point with the coordinates projected, in meters.
+//
+// Português: Devolve uma cópia do ponto com as coordenadas projetadas, em metros.
+func (el *PointStt) Project(projection Projection) PointStt {
+	var returnLStt = *el
+
+	x, y := projection.Forward(el.Loc[0], el.Loc[1])
+	returnLStt.SetXYDegrees(x, y)
+
+	return returnLStt
+}
+
+// English: Returns a copy of the projected point with the coordinates back in longitude and latitude.
+//
+// Português: Devolve uma cópia do ponto projetado com as coordenadas de volta em longitude e latitude.
+func (el *PointStt) Unproject(projection Projection) PointStt {
+	var returnLStt = *el
+
+	longitude, latitude := projection.Inverse(el.Loc[0], el.Loc[1])
+	returnLStt.SetLngLatDegrees(longitude, latitude)
+
+	return returnLStt
+}
+
+// projectLoc converts the list of coordinates, returning the new coordinates and their values in radians.
+func projectLoc(loc [][2]float64, convert func(float64, float64) (float64, float64)) ([][2]float64, [][2]float64) {
+	var locList = make([][2]float64, len(loc))
+	var radList = make([][2]float64, len(loc))
+
+	for k := range loc {
+		x, y := convert(loc[k][0], loc[k][1])
+		locList[k] = [2]float64{x, y}
+		radList[k] = [2]float64{DegreesToRadians(x), DegreesToRadians(y)}
+	}
+
+	return locList, radList
+}
+
+// English: Returns a copy of the way with the coordinates projected, in meters. Distances, angles and the bounding box
+// are not recalculated.
+//
+// Português: Devolve uma cópia do caminho com as coordenadas projetadas, em metros. Distâncias, ângulos e a caixa
+// delimitadora não são recalculados.
+func (el *WayStt) Project(projection Projection) WayStt {
+	return el.convertLoc(projection.Forward)
+}
+
+// English: Returns a copy of the projected way with the coordinates back in longitude and latitude.
+//
+// Português: Devolve uma cópia do caminho projetado com as coordenadas de volta em longitude e latitude.
+func (el *WayStt) Unproject(projection Projection) WayStt {
+	return el.convertLoc(projection.Inverse)
+}
+
+func (el *WayStt) convertLoc(convert func(float64, float64) (float64, float64)) WayStt {
+	var returnLStt = *el
+
+	returnLStt.Loc, returnLStt.Rad = projectLoc(el.Loc, convert)
+	if len(returnLStt.Loc) != 0 {
+		returnLStt.LocFirst = returnLStt.Loc[0]
+		returnLStt.LocLast = returnLStt.Loc[len(returnLStt.Loc)-1]
+	}
+
+	return returnLStt
+}
+
+// English: Returns a copy of the polygon, holes included, with the coordinates and the centroid projected, in meters.
+// Distances, angles, area and the bounding box are not recalculated.
+//
+// Português: Devolve uma cópia do polígono, buracos incluídos, com as coordenadas e o centroide projetados, em metros.
+// Distâncias, ângulos, área e a caixa delimitadora não são recalculados.
+func (el *PolygonStt) Project(projection Projection) PolygonStt {
+	var returnLStt = *el
+
+	returnLStt.PointsList = make([]PointStt, len(el.PointsList))
+	for k := range el.PointsList {
+		returnLStt.PointsList[k] = el.PointsList[k].Project(projection)
+	}
+
+	returnLStt.Inner = make([]PolygonStt, len(el.Inner))
+	for k := range el.Inner {
+		returnLStt.Inner[k] = el.Inner[k].Project(projection)
+	}
+
+	returnLStt.Centroid = el.Centroid.Project(projection)
+
+	return returnLStt
+}
+
+// English: Returns a copy of the projected polygon with the coordinates back in longitude and latitude.
+//
+// Português: Devolve uma cópia do polígono projetado com as coordenadas de volta em longitude e latitude.
+func (el *PolygonStt) Unproject(projection Projection) PolygonStt {
+	var returnLStt = *el
+
+	returnLStt.PointsList = make([]PointStt, len(el.PointsList))
+	for k := range el.PointsList {
+		returnLStt.PointsList[k] = el.PointsList[k].Unproject(projection)
+	}
+
+	returnLStt.Inner = make([]PolygonStt, len(el.Inner))
+	for k := range el.Inner {
+		returnLStt.Inner[k] = el.Inner[k].Unproject(projection)
+	}
+
+	returnLStt.Centroid = el.Centroid.Unproject(projection)
+
+	return returnLStt
+}
+
+const (
+	// radius of the sphere of EPSG:3857
+	webMercatorRadius = 6378137.0
+
+	// latitude where the Web Mercator map is square
+	webMercatorMaxLatitude = 85.051128779806604
+)
+
+// English: Web Mercator, EPSG:3857, the projection of the web maps. The latitude is limited to ±85.0511°.
+//
+// Português: Web Mercator, EPSG:3857, a projeção dos mapas da web. A latitude é limitada a ±85,0511°.
+type WebMercatorStt struct{}
+
+func (el WebMercatorStt) Forward(longitude, latitude float64) (float64, float64) {
+	latitude = math.Max(-webMercatorMaxLatitude, math.Min(webMercatorMaxLatitude, latitude))
+
+	return webMercatorRadius * DegreesToRadians(longitude),
+		webMercatorRadius * math.Log(math.Tan(math.Pi/4+DegreesToRadians(latitude)/2))
+}
+
+func (el WebMercatorStt) Inverse(x, y float64) (float64, float64) {
+	return RadiansToDegrees(x / webMercatorRadius), RadiansToDegrees(2*math.Atan(math.Exp(y/webMercatorRadius)) - math.Pi/2)
+}
+
+// English: Equirectangular projection, with the scale true along the standard parallel, on a sphere with the major
+// axis of the ellipsoid, or of the one of the datum selected by SetDatum() when Ellipsoid is not set.
+//
+// Português: Projeção equirretangular, com a escala verdadeira ao longo do paralelo padrão, em uma esfera com o eixo
+// maior do elipsoide, ou do elipsoide do datum selecionado por SetDatum() quando Ellipsoid não está definido.
+type EquirectangularStt struct {
+	// English: longitude of the origin of x, in degrees
+	//
+	// Português: longitude da origem de x, em graus
+	CentralMeridian float64
+
+	// English: latitude of true scale, in degrees
+	//
+	// Português: latitude de escala verdadeira, em graus
+	StandardParallel float64
+
+	Ellipsoid EllipsoidStt
+}
+
+func (el EquirectangularStt) Forward(longitude, latitude float64) (float64, float64) {
+	var radius = ellipsoidOrSelected(el.Ellipsoid).Major
+
+	return radius * DegreesToRadians(geodesicAngNormalize(longitude-el.CentralMeridian)) * math.Cos(DegreesToRadians(el.StandardParallel)),
+		radius * DegreesToRadians(latitude)
+}
+
+func (el EquirectangularStt) Inverse(x, y float64) (float64, float64) {
+	var radius = ellipsoidOrSelected(el.Ellipsoid).Major
+
+	return geodesicAngNormalize(el.CentralMeridian + RadiansToDegrees(x/(radius*math.Cos(DegreesToRadians(el.StandardParallel))))),
+		RadiansToDegrees(y / radius)
+}
@@ -0,0 +1,108 @@
+package iotmaker_geo_osm
+
+import (
+	"math"
+)
+
+// English: Lambert azimuthal equal-area projection, on the ellipsoid, or on the one of the datum selected by SetDatum()
+// when Ellipsoid is not set, centered on the origin. The areas are true everywhere, as in EPSG:3035 for Europe.
+//
+// Português: Projeção azimutal equivalente de Lambert, no elipsoide, ou no do datum selecionado por SetDatum() quando
+// Ellipsoid não está definido, centrada na origem. As áreas são verdadeiras em todo lugar, como no EPSG:3035 para a
+// Europa.
+type LambertAzimuthalEqualAreaStt struct {
+	Origin    PointStt
+	Ellipsoid EllipsoidStt
+}
+
+// lambertConstants holds the values of the ellipsoid and of the origin used by both directions.
+type lambertConstants struct {
+	e             float64
+	e2            float64
+	qp            float64
+	rq            float64
+	d             float64
+	sinBetaOrigin float64
+	cosBetaOrigin float64
+}
+
+// lambertQ is the q function of Snyder, from the sine of the latitude.
+func lambertQ(e, sinLatitude float64) float64 {
+	if e == 0 {
+		return 2 * sinLatitude
+	}
+
+	var e2 = e * e
+
+	return (1 - e2) * (sinLatitude/(1-e2*sinLatitude*sinLatitude) - 1/(2*e)*math.Log((1-e*sinLatitude)/(1+e*sinLatitude)))
+}
+
+func (el LambertAzimuthalEqualAreaStt) constants() lambertConstants {
+	var ellipsoid = ellipsoidOrSelected(el.Ellipsoid)
+	var returnL = lambertConstants{e2: ellipsoid.EccentricitySquared()}
+	returnL.e = math.Sqrt(returnL.e2)
+
+	returnL.qp = lambertQ(returnL.e, 1)
+	returnL.rq = ellipsoid.Major * math.Sqrt(returnL.qp/2)
+
+	var sinLatitude, cosLatitude = math.Sincos(DegreesToRadians(el.Origin.Loc[1]))
+	returnL.sinBetaOrigin = math.Max(-1, math.Min(1, lambertQ(returnL.e, sinLatitude)/returnL.qp))
+	returnL.cosBetaOrigin = math.Sqrt(1 - returnL.sinBetaOrigin*returnL.sinBetaOrigin)
+
+	// on the poles the limit of d is one
+	returnL.d = 1
+	if returnL.cosBetaOrigin > 1e-12 {
+		var m = cosLatitude / math.Sqrt(1-returnL.e2*sinLatitude*sinLatitude)
+		returnL.d = ellipsoid.Major * m / (returnL.rq * returnL.cosBetaOrigin)
+	}
+
+	return returnL
+}
+
+func (el LambertAzimuthalEqualAreaStt) Forward(longitude, latitude float64) (float64, float64) {
+	var c = el.constants()
+
+	var sinBeta = math.Max(-1, math.Min(1, lambertQ(c.e, math.Sin(DegreesToRadians(latitude)))/c.qp))
+	var cosBeta = math.Sqrt(1 - sinBeta*sinBeta)
+	var sinLambda, cosLambda = math.Sincos(DegreesToRadians(longitude - el.Origin.Loc[0]))
+
+	var b = c.rq * math.Sqrt(2/(1+c.sinBetaOrigin*sinBeta+c.cosBetaOrigin*cosBeta*cosLambda))
+
+	return b * c.d * cosBeta * sinLambda,
+		b / c.d * (c.cosBetaOrigin*sinBeta - c.sinBetaOrigin*cosBeta*cosLambda)
+}
+
+func (el LambertAzimuthalEqualAreaStt) Inverse(x, y float64) (float64, float64) {
+	var c = el.constants()
+
+	var rho = math.Hypot(x/c.d, c.d*y)
+	if rho == 0 {
+		return el.Origin.Loc[0], el.Origin.Loc[1]
+	}
+
+	var sinC, cosC = math.Sincos(2 * math.Asin(rho/(2*c.rq)))
+	var beta = math.Asin(cosC*c.sinBetaOrigin + c.d*y*sinC*c.cosBetaOrigin/rho)
+	var lambda = math.Atan2(x*sinC, c.d*rho*c.cosBetaOrigin*cosC-c.d*c.d*y*c.sinBetaOrigin*sinC)
+
+	// latitude from the authalic latitude, with the series of Snyder refined by his iteration on q
+	var e4 = c.e2 * c.e2
+	var e6 = e4 * c.e2
+	var latitude = beta +
+		(c.e2/3+31*e4/180+517*e6/5040)*math.Sin(2*beta) +
+		(23*e4/360+251*e6/3780)*math.Sin(4*beta) +
+		(761*e6/45360)*math.Sin(6*beta)
+
+	var q = c.qp * math.Sin(beta)
+	for i := 0; i != 5 && c.e != 0 && math.Abs(math.Cos(latitude)) > 1e-12; i += 1 {
+		var sinLatitude = math.Sin(latitude)
+		var w = 1 - c.e2*sinLatitude*sinLatitude
+		var delta = w * w / (2 * math.Cos(latitude)) * (q/(1-c.e2) - lambertQ(c.e, sinLatitude)/(1-c.e2))
+		latitude += delta
+
+		if math.Abs(delta) < 1e-15 {
+			break
+		}
+	}
+
+	return geodesicAngNormalize(el.Origin.Loc[0] + RadiansToDegrees(lambda)), RadiansToDegrees(latitude)
+}
@@ -0,0 +1,114 @@
+package iotmaker_geo_osm
+
+import (
+	"math"
+)
+
+// English: Local tangent plane, east-north-up, on the ellipsoid, or on the one of the datum selected by SetDatum() when
+// Ellipsoid is not set, touching the ellipsoid at the origin. x is the distance to the east and y to the north, in
+// meters. It is accurate near the origin, for the planar work on a city or a region.
+//
+// The inverse returns the point of the ellipsoid below the position of the plane.
+//
+// Português: Plano tangente local, leste-norte-cima, no elipsoide, ou no do datum selecionado por SetDatum() quando
+// Ellipsoid não está definido, tocando o elipsoide na origem. x é a distância para leste e y para norte, em metros. É
+// preciso perto da origem, para o trabalho plano em uma cidade ou uma região.
+//
+// O inverso devolve o ponto do elipsoide abaixo da posição do plano.
+type LocalTangentPlaneStt struct {
+	Origin    PointStt
+	Ellipsoid EllipsoidStt
+}
+
+// axes returns the origin, in earth-centered, earth-fixed coordinates, and the unit vectors east, north and up.
+func (el LocalTangentPlaneStt) axes(ellipsoid EllipsoidStt) ([3]float64, [3][3]float64) {
+	var origin = ellipsoid.toCartesian(el.Origin.Loc[1], el.Origin.Loc[0], 0)
+	var sinLatitude, cosLatitude = math.Sincos(DegreesToRadians(el.Origin.Loc[1]))
+	var sinLongitude, cosLongitude = math.Sincos(DegreesToRadians(el.Origin.Loc[0]))
+
+	return origin, [3][3]float64{
+		{-sinLongitude, cosLongitude, 0},
+		{-sinLatitude * cosLongitude, -sinLatitude * sinLongitude, cosLatitude},
+		{cosLatitude * cosLongitude, cosLatitude * sinLongitude, sinLatitude},
+	}
+}
+
+// English: Returns east, north and up, in meters, of the point with its altitude, relative to the origin.
+//
+// Português: Devolve leste, norte e cima, em metros, do ponto com a sua altitude, relativos à origem.
+func (el LocalTangentPlaneStt) ToENU(pointAStt PointStt) (float64, float64, float64) {
+	var ellipsoid = ellipsoidOrSelected(el.Ellipsoid)
+	var origin, axes = el.axes(ellipsoid)
+	var cartesian = ellipsoid.toCartesian(pointAStt.Loc[1], pointAStt.Loc[0], pointAStt.Alt)
+
+	var delta = [3]float64{cartesian[0] - origin[0], cartesian[1] - origin[1], cartesian[2] - origin[2]}
+	var enu [3]float64
+	for k := range axes {
+		enu[k] = axes[k][0]*delta[0] + axes[k][1]*delta[1] + axes[k][2]*delta[2]
+	}
+
+	return enu[0], enu[1], enu[2]
+}
+
+// English: Returns the point, with the altitude, at east, north and up, in meters, relative to the origin.
+//
+// Português: Devolve o ponto, com a altitude, em leste, norte e cima, em metros, relativos à origem.
+func (el LocalTangentPlaneStt) FromENU(east, north, up float64) PointStt {
+	var returnLStt PointStt
+	var ellipsoid = ellipsoidOrSelected(el.Ellipsoid)
+	var origin, axes = el.axes(ellipsoid)
+
+	var cartesian [3]float64
+	for k := range cartesian {
+		cartesian[k] = origin[k] + axes[0][k]*east + axes[1][k]*north + axes[2][k]*up
+	}
+
+	latitude, longitude, height := ellipsoid.fromCartesian(cartesian)
+	returnLStt.SetLngLatDegrees(longitude, latitude)
+	returnLStt.Alt = height
+
+	return returnLStt
+}
+
+func (el LocalTangentPlaneStt) Forward(longitude, latitude float64) (float64, float64) {
+	var point PointStt
+	point.SetLngLatDegrees(longitude, latitude)
+
+	east, north, _ := el.ToENU(point)
+
+	return east, north
+}
+
+func (el LocalTangentPlaneStt) Inverse(x, y float64) (float64, float64) {
+	var ellipsoid = ellipsoidOrSelected(el.Ellipsoid)
+	var origin, axes = el.axes(ellipsoid)
+
+	// the point of the plane goes down along the up axis until it reaches the ellipsoid, the smallest root of
+	// (X² + Y²) / a² + Z² / b² = 1 for the position p + t·up
+	var major2 = ellipsoid.Major * ellipsoid.Major
+	var minor2 = ellipsoid.Minor() * ellipsoid.Minor()
+
+	var p [3]float64
+	for k := range p {
+		p[k] = origin[k] + axes[0][k]*x + axes[1][k]*y
+	}
+
+	var up = axes[2]
+	var a = (up[0]*up[0]+up[1]*up[1])/major2 + up[2]*up[2]/minor2
+	var b = 2 * ((p[0]*up[0]+p[1]*up[1])/major2 + p[2]*up[2]/minor2)
+	var c = (p[0]*p[0]+p[1]*p[1])/major2 + p[2]*p[2]/minor2 - 1
+
+	var t = 0.0
+	var discriminant = b*b - 4*a*c
+	if discriminant >= 0 {
+		// the root nearest to zero, stable when c is small
+		var q = -(b + math.Copysign(math.Sqrt(discriminant), b)) / 2
+		if q != 0 {
+			t = c / q
+		}
+	}
+
+	latitude, longitude, _ := ellipsoid.fromCartesian([3]float64{p[0] + t*up[0], p[1] + t*up[1], p[2] + t*up[2]})
+
+	return longitude, latitude
+}
@@ -0,0 +1,88 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"math"
+)
+
+func ExampleProjection() {
+	// the example of the EPSG guidance note 7-2, for EPSG:3035 without the false origin of 4321000 m and 3210000 m
+	var origin PointStt
+	origin.SetLngLatDegrees(10, 52)
+
+	var point PointStt
+	point.SetLngLatDegrees(5, 50)
+
+	var projected = point.Project(LambertAzimuthalEqualAreaStt{Origin: origin})
+	fmt.Printf("lambert: %.2f %.2f\n", projected.Loc[0]+4321000, projected.Loc[1]+3210000)
+
+	projected = point.Project(WebMercatorStt{})
+	fmt.Printf("web mercator: %.2f %.2f\n", projected.Loc[0], projected.Loc[1])
+
+	east, north, up := LocalTangentPlaneStt{Origin: origin}.ToENU(point)
+	fmt.Printf("enu: %.2f %.2f %.2f\n", east, north, up)
+
+	// round trip of every projection
+	var projections = []Projection{
+		WebMercatorStt{},
+		EquirectangularStt{CentralMeridian: -45, StandardParallel: -15},
+		LambertAzimuthalEqualAreaStt{Origin: origin},
+		LocalTangentPlaneStt{Origin: origin},
+	}
+
+	for _, projection := range projections {
+		var maxError float64
+		for latitude := 30.0; latitude <= 70; latitude += 2.5 {
+			for longitude := -20.0; longitude <= 40; longitude += 2.5 {
+				var point PointStt
+				point.SetLngLatDegrees(longitude, latitude)
+
+				var projected = point.Project(projection)
+				var back = projected.Unproject(projection)
+
+				maxError = math.Max(maxError, math.Max(math.Abs(back.Loc[0]-longitude), math.Abs(back.Loc[1]-latitude)))
+			}
+		}
+
+		fmt.Printf("%T: %v\n", projection, maxError < 1e-9)
+	}
+
+	// Output:
+	// lambert: 3962799.45 2999718.85
+	// web mercator: 556597.45 6446275.84
+	// enu: -358023.95 -210133.41 -13506.49
+	// iotmaker_geo_osm.WebMercatorStt: true
+	// iotmaker_geo_osm.EquirectangularStt: true
+	// iotmaker_geo_osm.LambertAzimuthalEqualAreaStt: true
+	// iotmaker_geo_osm.LocalTangentPlaneStt: true
+}
+
+func ExampleLocalTangentPlaneStt() {
+	// the projections keep their ellipsoid, so a datum selected between Project() and Unproject() does not change them
+	var origin PointStt
+	origin.SetLngLatDegrees(-47.8825, -15.7942)
+
+	var point PointStt
+	point.SetLngLatDegrees(-47.8725, -15.7842)
+
+	var projections = []Projection{
+		EquirectangularStt{CentralMeridian: -45, StandardParallel: -15, Ellipsoid: ELLIPSOID_GRS67_MODIFIED},
+		LambertAzimuthalEqualAreaStt{Origin: origin, Ellipsoid: ELLIPSOID_GRS67_MODIFIED},
+		LocalTangentPlaneStt{Origin: origin, Ellipsoid: ELLIPSOID_GRS67_MODIFIED},
+	}
+
+	for _, projection := range projections {
+		var projected = point.Project(projection)
+
+		SetDatum(DATUM_SPHERE)
+		var back = projected.Unproject(projection)
+		SetDatum(DATUM_WGS84)
+
+		fmt.Printf("%T: %.3f, back %.6f\n", projection, projected.Loc, back.Loc)
+	}
+
+	// Output:
+	// iotmaker_geo_osm.EquirectangularStt: [-308870.615 -1757095.443], back [-47.872500 -15.784200]
+	// iotmaker_geo_osm.LambertAzimuthalEqualAreaStt: [1071.489 1106.544], back [-47.872500 -15.784200]
+	// iotmaker_geo_osm.LocalTangentPlaneStt: [1071.489 1106.544], back [-47.872500 -15.784200]
+}
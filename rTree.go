@@ -0,0 +1,705 @@
+package iotmaker_geo_osm
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// maximum and minimum number of entries of a node of the R-tree
+const (
+	rTreeMaxEntries = 16
+	rTreeMinEntries = 6
+)
+
+// English: Item kept in the RTreeStt. PolygonStt, WayStt, PointStt and PointListStt are items.
+//
+// The items are compared by identity, so the pointer of the item must be used.
+//
+// Português: Item guardado no RTreeStt. PolygonStt, WayStt, PointStt e PointListStt são itens.
+//
+// Os itens são comparados por identidade, por isto, o ponteiro do item deve ser usado.
+type RTreeItem interface {
+	// English: Box that contains the item, in decimal degrees
+	//
+	// Português: Caixa que contém o item, em graus decimais
+	GetBox() BoxStt
+}
+
+// English: Item found by RTreeStt.Nearest() and its distance to the point.
+//
+// Português: Item achado por RTreeStt.Nearest() e a sua distância ao ponto.
+type RTreeNeighborStt struct {
+	Item     RTreeItem
+	Distance DistanceStt
+}
+
+// rTreeRect is the box as minimum longitude, minimum latitude, maximum longitude and maximum latitude, in degrees
+type rTreeRect [4]float64
+
+// rTreeEntryStt is a child node, in the inner nodes, or an item, in the leaves
+type rTreeEntryStt struct {
+	rect  rTreeRect
+	child *rTreeNodeStt
+	item  RTreeItem
+}
+
+type rTreeNodeStt struct {
+	rect    rTreeRect
+	leaf    bool
+	entries []rTreeEntryStt
+}
+
+// English: R-tree in memory, keyed on the box of the items, for the search of the items in a box, of the nearest items
+// and of the polygons that contain a point.
+//
+// The zero value is an empty tree. Load() builds the tree packed by Sort-Tile-Recursive, Insert() and Delete() change
+// it one item at a time. Many goroutines can search the tree at the same time; the changes wait for the searches.
+//
+// The tree does not go around the antimeridian: a box is the range of longitudes between -180 and 180.
+//
+// Português: R-tree em memória, indexada pela caixa dos itens, para a busca dos itens em uma caixa, dos itens mais
+// próximos e dos polígonos que contêm um ponto.
+//
+// O valor zero é uma árvore vazia. Load() monta a árvore empacotada por Sort-Tile-Recursive, Insert() e Delete() a
+// alteram um item por vez. Muitas goroutines podem buscar na árvore ao mesmo tempo; as alterações esperam as buscas.
+//
+// A árvore não dá a volta no antimeridiano: uma caixa é a faixa de longitudes entre -180 e 180.
+type RTreeStt struct {
+	mutex  sync.RWMutex
+	root   *rTreeNodeStt
+	height int
+	size   int
+}
+
+// rTreeRectOfBox accepts the corners of the box in any order, as GetBox() does not put the minimum longitude on the
+// bottom left corner
+func rTreeRectOfBox(box BoxStt) rTreeRect {
+	return rTreeRect{
+		math.Min(box.BottomLeft.Loc[0], box.UpperRight.Loc[0]),
+		math.Min(box.BottomLeft.Loc[1], box.UpperRight.Loc[1]),
+		math.Max(box.BottomLeft.Loc[0], box.UpperRight.Loc[0]),
+		math.Max(box.BottomLeft.Loc[1], box.UpperRight.Loc[1]),
+	}
+}
+
+func (el rTreeRect) area() float64 {
+	return (el[2] - el[0]) * (el[3] - el[1])
+}
+
+func (el rTreeRect) margin() float64 {
+	return (el[2] - el[0]) + (el[3] - el[1])
+}
+
+func (el rTreeRect) extend(other rTreeRect) rTreeRect {
+	return rTreeRect{math.Min(el[0], other[0]), math.Min(el[1], other[1]), math.Max(el[2], other[2]), math.Max(el[3], other[3])}
+}
+
+func (el rTreeRect) intersects(other rTreeRect) bool {
+	return other[0] <= el[2] && other[1] <= el[3] && other[2] >= el[0] && other[3] >= el[1]
+}
+
+func (el rTreeRect) contains(other rTreeRect) bool {
+	return el[0] <= other[0] && el[1] <= other[1] && other[2] <= el[2] && other[3] <= el[3]
+}
+
+func (el rTreeRect) intersection(other rTreeRect) float64 {
+	var width = math.Min(el[2], other[2]) - math.Max(el[0], other[0])
+	var height = math.Min(el[3], other[3]) - math.Max(el[1], other[1])
+	if width < 0 || height < 0 {
+		return 0
+	}
+
+	return width * height
+}
+
+func (el rTreeRect) center(axis int) float64 {
+	return (el[axis] + el[axis+2]) / 2
+}
+
+// rTreeRectOfEntries is the box of all the entries
+func rTreeRectOfEntries(entries []rTreeEntryStt) rTreeRect {
+	var rect = rTreeRect{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for k := range entries {
+		rect = rect.extend(entries[k].rect)
+	}
+
+	return rect
+}
+
+// rTreeNewEntry makes the entry of the item. The polygons are initialized here, because PointInPolygon() initializes
+// them on the first call and the searches must not change the items.
+func rTreeNewEntry(item RTreeItem) (rTreeEntryStt, error) {
+	if item == nil {
+		return rTreeEntryStt{}, fmt.Errorf("rtree: the item must not be nil")
+	}
+
+	if polygon, ok := item.(*PolygonStt); ok {
+		if err := rTreeInitPolygon(polygon); err != nil {
+			return rTreeEntryStt{}, err
+		}
+	}
+
+	var rect = rTreeRectOfBox(item.GetBox())
+	for _, value := range rect {
+		if math.IsNaN(value) {
+			return rTreeEntryStt{}, fmt.Errorf("rtree: the box of the item is not a number")
+		}
+	}
+
+	return rTreeEntryStt{rect: rect, item: item}, nil
+}
+
+func rTreeInitPolygon(polygon *PolygonStt) error {
+	if polygon.Initialize == false {
+		if err := polygon.Init(); err != nil {
+			return fmt.Errorf("rtree: %v", err)
+		}
+	}
+
+	for k := range polygon.Inner {
+		if err := rTreeInitPolygon(&polygon.Inner[k]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// English: Replaces the content of the tree by the items, packed by Sort-Tile-Recursive. It is faster than Insert() one
+// by one and gives a tree of better searches.
+//
+// Português: Substitui o conteúdo da árvore pelos itens, empacotados por Sort-Tile-Recursive. É mais rápido que
+// Insert() um por um e dá uma árvore de buscas melhores.
+func (el *RTreeStt) Load(items []RTreeItem) error {
+	var entries = make([]rTreeEntryStt, len(items))
+	for k := range items {
+		var err error
+		entries[k], err = rTreeNewEntry(items[k])
+		if err != nil {
+			return err
+		}
+	}
+
+	el.mutex.Lock()
+	defer el.mutex.Unlock()
+
+	el.root, el.height, el.size = nil, 0, len(entries)
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var leaf = true
+	for el.height = 1; ; el.height += 1 {
+		var nodes = rTreePack(entries, leaf)
+		if len(nodes) == 1 {
+			el.root = nodes[0]
+			return nil
+		}
+
+		entries = make([]rTreeEntryStt, len(nodes))
+		for k := range nodes {
+			entries[k] = rTreeEntryStt{rect: nodes[k].rect, child: nodes[k]}
+		}
+		leaf = false
+	}
+}
+
+// rTreePack makes one level of the tree: the entries are sorted by longitude in vertical slices, each slice is sorted by
+// latitude and cut in nodes
+func rTreePack(entries []rTreeEntryStt, leaf bool) []*rTreeNodeStt {
+	var nodesCount = (len(entries) + rTreeMaxEntries - 1) / rTreeMaxEntries
+	var slicesCount = int(math.Ceil(math.Sqrt(float64(nodesCount))))
+	var sliceSize = slicesCount * rTreeMaxEntries
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].rect.center(0) < entries[j].rect.center(0) })
+
+	var nodes = make([]*rTreeNodeStt, 0, nodesCount)
+	for start := 0; start < len(entries); start += sliceSize {
+		var slice = entries[start:int(math.Min(float64(start+sliceSize), float64(len(entries))))]
+		sort.Slice(slice, func(i, j int) bool { return slice[i].rect.center(1) < slice[j].rect.center(1) })
+
+		for first := 0; first < len(slice); first += rTreeMaxEntries {
+			var last = int(math.Min(float64(first+rTreeMaxEntries), float64(len(slice))))
+			var node = &rTreeNodeStt{leaf: leaf, entries: append(make([]rTreeEntryStt, 0, rTreeMaxEntries+1), slice[first:last]...)}
+			node.rect = rTreeRectOfEntries(node.entries)
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+// English: Adds the item to the tree.
+//
+// Português: Adiciona o item à árvore.
+func (el *RTreeStt) Insert(item RTreeItem) error {
+	entry, err := rTreeNewEntry(item)
+	if err != nil {
+		return err
+	}
+
+	el.mutex.Lock()
+	defer el.mutex.Unlock()
+
+	el.insert(entry, 0)
+	el.size += 1
+
+	return nil
+}
+
+// insert puts the entry in a node of the level, where the leaves are the level zero
+func (el *RTreeStt) insert(entry rTreeEntryStt, level int) {
+	if el.root == nil {
+		el.root = &rTreeNodeStt{rect: entry.rect, leaf: true}
+		el.height = 1
+	}
+
+	// path from the root to the node where the entry goes, with the boxes extended on the way down
+	el.root.rect = el.root.rect.extend(entry.rect)
+	var path = []*rTreeNodeStt{el.root}
+	for node := el.root; el.height-len(path) != level; {
+		var best = 0
+		var bestEnlargement, bestArea = math.Inf(1), math.Inf(1)
+
+		for k := range node.entries {
+			var area = node.entries[k].rect.area()
+			var enlargement = node.entries[k].rect.extend(entry.rect).area() - area
+			if enlargement < bestEnlargement || enlargement == bestEnlargement && area < bestArea {
+				best, bestEnlargement, bestArea = k, enlargement, area
+			}
+		}
+
+		var child = node.entries[best].child
+		child.rect = child.rect.extend(entry.rect)
+		node.entries[best].rect = child.rect
+		node = child
+		path = append(path, node)
+	}
+
+	var node = path[len(path)-1]
+	node.entries = append(node.entries, entry)
+
+	// the nodes with too many entries are split from the bottom up
+	for k := len(path) - 1; k >= 0 && len(path[k].entries) > rTreeMaxEntries; k -= 1 {
+		var sibling = rTreeSplit(path[k])
+
+		if k == 0 {
+			el.root = &rTreeNodeStt{entries: []rTreeEntryStt{{rect: path[k].rect, child: path[k]}, {rect: sibling.rect, child: sibling}}}
+			el.root.rect = rTreeRectOfEntries(el.root.entries)
+			el.height += 1
+			break
+		}
+
+		var parent = path[k-1]
+		for i := range parent.entries {
+			if parent.entries[i].child == path[k] {
+				parent.entries[i].rect = path[k].rect
+			}
+		}
+		parent.entries = append(parent.entries, rTreeEntryStt{rect: sibling.rect, child: sibling})
+	}
+}
+
+// rTreeSplit moves part of the entries of the node to a new node. The axis is the one of the least sum of the margins
+// and the cut is the one of the least overlap, then of the least area.
+func rTreeSplit(node *rTreeNodeStt) *rTreeNodeStt {
+	var entries = node.entries
+	var count = len(entries)
+
+	var sortByAxis = func(axis int) {
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].rect[axis] != entries[j].rect[axis] {
+				return entries[i].rect[axis] < entries[j].rect[axis]
+			}
+			return entries[i].rect[axis+2] < entries[j].rect[axis+2]
+		})
+	}
+
+	var marginOfAxis = func(axis int) float64 {
+		sortByAxis(axis)
+
+		var margin = 0.0
+		for cut := rTreeMinEntries; cut <= count-rTreeMinEntries; cut += 1 {
+			margin += rTreeRectOfEntries(entries[:cut]).margin() + rTreeRectOfEntries(entries[cut:]).margin()
+		}
+
+		return margin
+	}
+
+	if marginOfAxis(0) <= marginOfAxis(1) {
+		sortByAxis(0)
+	}
+
+	var bestCut = rTreeMinEntries
+	var bestOverlap, bestArea = math.Inf(1), math.Inf(1)
+	for cut := rTreeMinEntries; cut <= count-rTreeMinEntries; cut += 1 {
+		var first = rTreeRectOfEntries(entries[:cut])
+		var second = rTreeRectOfEntries(entries[cut:])
+		var overlap = first.intersection(second)
+		var area = first.area() + second.area()
+
+		if overlap < bestOverlap || overlap == bestOverlap && area < bestArea {
+			bestCut, bestOverlap, bestArea = cut, overlap, area
+		}
+	}
+
+	var sibling = &rTreeNodeStt{leaf: node.leaf, entries: append(make([]rTreeEntryStt, 0, rTreeMaxEntries+1), entries[bestCut:]...)}
+	sibling.rect = rTreeRectOfEntries(sibling.entries)
+
+	node.entries = append(make([]rTreeEntryStt, 0, rTreeMaxEntries+1), entries[:bestCut]...)
+	node.rect = rTreeRectOfEntries(node.entries)
+
+	return sibling
+}
+
+// English: Removes the item from the tree. The item must not be changed between Insert() and Delete(), because it is
+// found by its box.
+//
+// Português: Remove o item da árvore. O item não deve ser alterado entre Insert() e Delete(), porque ele é achado pela
+// sua caixa.
+func (el *RTreeStt) Delete(item RTreeItem) error {
+	if item == nil {
+		return fmt.Errorf("rtree: the item must not be nil")
+	}
+
+	var rect = rTreeRectOfBox(item.GetBox())
+
+	el.mutex.Lock()
+	defer el.mutex.Unlock()
+
+	if el.root == nil {
+		return fmt.Errorf("rtree: the item is not in the tree")
+	}
+
+	path, index := rTreeFind(el.root, rect, item, nil)
+	if path == nil {
+		return fmt.Errorf("rtree: the item is not in the tree")
+	}
+
+	var leaf = path[len(path)-1]
+	leaf.entries = append(leaf.entries[:index], leaf.entries[index+1:]...)
+	el.size -= 1
+
+	// the nodes with few entries leave the tree and their entries go in again, at the same level
+	type orphanStt struct {
+		entry rTreeEntryStt
+		level int
+	}
+	var orphans = make([]orphanStt, 0)
+
+	for k := len(path) - 1; k > 0; k -= 1 {
+		var node, parent = path[k], path[k-1]
+
+		for i := range parent.entries {
+			if parent.entries[i].child != node {
+				continue
+			}
+
+			if len(node.entries) < rTreeMinEntries {
+				for _, entry := range node.entries {
+					orphans = append(orphans, orphanStt{entry: entry, level: len(path) - 1 - k})
+				}
+				parent.entries = append(parent.entries[:i], parent.entries[i+1:]...)
+			} else {
+				node.rect = rTreeRectOfEntries(node.entries)
+				parent.entries[i].rect = node.rect
+			}
+			break
+		}
+	}
+	el.root.rect = rTreeRectOfEntries(el.root.entries)
+
+	for !el.root.leaf && len(el.root.entries) == 1 {
+		el.root = el.root.entries[0].child
+		el.height -= 1
+	}
+
+	if len(el.root.entries) == 0 {
+		el.root, el.height = nil, 0
+	}
+
+	for _, orphan := range orphans {
+		if orphan.level < el.height {
+			el.insert(orphan.entry, orphan.level)
+			continue
+		}
+
+		// the tree got shorter than the orphan, its items go in one by one
+		for _, entry := range rTreeLeafEntries(orphan.entry.child, nil) {
+			el.insert(entry, 0)
+		}
+	}
+
+	return nil
+}
+
+// rTreeLeafEntries are the entries of all the leaves under the node
+func rTreeLeafEntries(node *rTreeNodeStt, entries []rTreeEntryStt) []rTreeEntryStt {
+	if node.leaf {
+		return append(entries, node.entries...)
+	}
+
+	for k := range node.entries {
+		entries = rTreeLeafEntries(node.entries[k].child, entries)
+	}
+
+	return entries
+}
+
+// rTreeFind is the path from the node to the leaf that has the item, and the index of the item in the leaf
+func rTreeFind(node *rTreeNodeStt, rect rTreeRect, item RTreeItem, path []*rTreeNodeStt) ([]*rTreeNodeStt, int) {
+	path = append(path, node)
+
+	for k := range node.entries {
+		if !node.entries[k].rect.contains(rect) {
+			continue
+		}
+
+		if node.leaf {
+			if node.entries[k].item == item {
+				return path, k
+			}
+			continue
+		}
+
+		if found, index := rTreeFind(node.entries[k].child, rect, item, path); found != nil {
+			return found, index
+		}
+	}
+
+	return nil, 0
+}
+
+// English: Number of items in the tree.
+//
+// Português: Quantidade de itens na árvore.
+func (el *RTreeStt) Len() int {
+	el.mutex.RLock()
+	defer el.mutex.RUnlock()
+
+	return el.size
+}
+
+// English: Items whose box intersects the box.
+//
+// Português: Itens cuja caixa cruza a caixa.
+func (el *RTreeStt) Search(box BoxStt) []RTreeItem {
+	var rect = rTreeRectOfBox(box)
+	var items = make([]RTreeItem, 0)
+
+	el.mutex.RLock()
+	defer el.mutex.RUnlock()
+
+	if el.root == nil {
+		return items
+	}
+
+	el.search(el.root, rect, func(item RTreeItem) {
+		items = append(items, item)
+	})
+
+	return items
+}
+
+func (el *RTreeStt) search(node *rTreeNodeStt, rect rTreeRect, found func(item RTreeItem)) {
+	for k := range node.entries {
+		if !node.entries[k].rect.intersects(rect) {
+			continue
+		}
+
+		if node.leaf {
+			found(node.entries[k].item)
+		} else {
+			el.search(node.entries[k].child, rect, found)
+		}
+	}
+}
+
+// English: Items that contain the point. The polygons are tested by PointInPolygon(), so the point in a hole is not in
+// the polygon; the other items are tested only by their box.
+//
+// Português: Itens que contêm o ponto. Os polígonos são testados por PointInPolygon(), então o ponto em um buraco não
+// está no polígono; os outros itens são testados só pela sua caixa.
+func (el *RTreeStt) Containing(point PointStt) []RTreeItem {
+	var rect = rTreeRect{point.Loc[0], point.Loc[1], point.Loc[0], point.Loc[1]}
+	var items = make([]RTreeItem, 0)
+
+	el.mutex.RLock()
+	defer el.mutex.RUnlock()
+
+	if el.root == nil {
+		return items
+	}
+
+	el.search(el.root, rect, func(item RTreeItem) {
+		if polygon, ok := item.(*PolygonStt); ok && !polygon.PointInPolygon(point) {
+			return
+		}
+
+		items = append(items, item)
+	})
+
+	return items
+}
+
+// rTreeMetric measures in the plane of longitude and latitude, with the longitude shortened by the cosine of the
+// latitude of the point, as the Earth is near the point
+type rTreeMetric struct {
+	point [2]float64
+	scale float64
+}
+
+func newRTreeMetric(point [2]float64) rTreeMetric {
+	return rTreeMetric{point: point, scale: math.Cos(DegreesToRadians(point[1]))}
+}
+
+// toPoint is the distance, in degrees of latitude, to the location
+func (el rTreeMetric) toPoint(loc [2]float64) float64 {
+	return math.Hypot((loc[0]-el.point[0])*el.scale, loc[1]-el.point[1])
+}
+
+// toRect is the least distance to any location of the box
+func (el rTreeMetric) toRect(rect rTreeRect) float64 {
+	var x = math.Max(0, math.Max(rect[0]-el.point[0], el.point[0]-rect[2]))
+	var y = math.Max(0, math.Max(rect[1]-el.point[1], el.point[1]-rect[3]))
+
+	return math.Hypot(x*el.scale, y)
+}
+
+// toSegment is the least distance to the segment between the locations a and b
+func (el rTreeMetric) toSegment(a, b [2]float64) float64 {
+	var ax, ay = (a[0] - el.point[0]) * el.scale, a[1] - el.point[1]
+	var bx, by = (b[0] - el.point[0]) * el.scale, b[1] - el.point[1]
+	var dx, dy = bx - ax, by - ay
+
+	var fraction = 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		fraction = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/length))
+	}
+
+	return math.Hypot(ax+fraction*dx, ay+fraction*dy)
+}
+
+func (el rTreeMetric) toLine(line [][2]float64) float64 {
+	if len(line) == 1 {
+		return el.toPoint(line[0])
+	}
+
+	var distance = math.Inf(1)
+	for k := 1; k < len(line); k += 1 {
+		distance = math.Min(distance, el.toSegment(line[k-1], line[k]))
+	}
+
+	return distance
+}
+
+func (el rTreeMetric) toRing(ring []PointStt) float64 {
+	var distance = math.Inf(1)
+	for k := range ring {
+		distance = math.Min(distance, el.toSegment(ring[k].Loc, ring[(k+1)%len(ring)].Loc))
+	}
+
+	return distance
+}
+
+// toItem is the distance to the item itself; the items of unknown kind are measured by their box
+func (el rTreeMetric) toItem(item RTreeItem, rect rTreeRect, point PointStt) float64 {
+	switch converted := item.(type) {
+	case *PointStt:
+		return el.toPoint(converted.Loc)
+
+	case *WayStt:
+		if len(converted.Loc) == 0 {
+			return el.toRect(rect)
+		}
+		return el.toLine(converted.Loc)
+
+	case *PolygonStt:
+		if converted.PointInPolygon(point) {
+			return 0
+		}
+
+		var distance = el.toRing(converted.PointsList)
+		for k := range converted.Inner {
+			distance = math.Min(distance, el.toRing(converted.Inner[k].PointsList))
+		}
+		return distance
+	}
+
+	return el.toRect(rect)
+}
+
+// rTreeQueueStt is the queue of the nodes and items by distance, for heap
+type rTreeQueueStt []rTreeQueueEntryStt
+
+type rTreeQueueEntryStt struct {
+	distance float64
+	entry    rTreeEntryStt
+	isItem   bool
+}
+
+func (el rTreeQueueStt) Len() int            { return len(el) }
+func (el rTreeQueueStt) Less(i, j int) bool  { return el[i].distance < el[j].distance }
+func (el rTreeQueueStt) Swap(i, j int)       { el[i], el[j] = el[j], el[i] }
+func (el *rTreeQueueStt) Push(x interface{}) { *el = append(*el, x.(rTreeQueueEntryStt)) }
+func (el *rTreeQueueStt) Pop() interface{} {
+	var last = (*el)[len(*el)-1]
+	*el = (*el)[:len(*el)-1]
+	return last
+}
+
+// English: The k items nearest to the point, from the nearest. The distance is to the item itself: to the point, to the
+// line of the way and to the border of the polygon, zero inside it; the other items are measured by their box.
+//
+// The distance is measured in a plane tangent at the point, good for the neighborhood of the point; for distances on
+// the ellipsoid, use GeodesicTreeStt.
+//
+// Português: Os k itens mais próximos do ponto, a partir do mais próximo. A distância é até o próprio item: até o
+// ponto, até a linha do way e até a borda do polígono, zero dentro dele; os outros itens são medidos pela sua caixa.
+//
+// A distância é medida em um plano tangente no ponto, boa para a vizinhança do ponto; para distâncias no elipsoide, use
+// GeodesicTreeStt.
+func (el *RTreeStt) Nearest(point PointStt, k int) ([]RTreeNeighborStt, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("rtree: the number of items must be at least one")
+	}
+
+	var metric = newRTreeMetric(point.Loc)
+	var radius = EarthRadius(point)
+	var meters = DegreesToRadians(1) * radius.GetMeters()
+	var neighbors = make([]RTreeNeighborStt, 0, k)
+
+	el.mutex.RLock()
+	defer el.mutex.RUnlock()
+
+	if el.root == nil {
+		return neighbors, nil
+	}
+
+	var queue = &rTreeQueueStt{{distance: metric.toRect(el.root.rect), entry: rTreeEntryStt{rect: el.root.rect, child: el.root}}}
+	for queue.Len() != 0 && len(neighbors) != k {
+		var next = heap.Pop(queue).(rTreeQueueEntryStt)
+
+		if next.isItem {
+			var neighbor = RTreeNeighborStt{Item: next.entry.item}
+			neighbor.Distance.SetMeters(next.distance * meters)
+			neighbors = append(neighbors, neighbor)
+			continue
+		}
+
+		var node = next.entry.child
+		for i := range node.entries {
+			if node.leaf {
+				var distance = metric.toItem(node.entries[i].item, node.entries[i].rect, point)
+				heap.Push(queue, rTreeQueueEntryStt{distance: distance, entry: node.entries[i], isItem: true})
+			} else {
+				heap.Push(queue, rTreeQueueEntryStt{distance: metric.toRect(node.entries[i].rect), entry: node.entries[i]})
+			}
+		}
+	}
+
+	return neighbors, nil
+}
@@ -0,0 +1,46 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+func ExampleRTreeStt() {
+	var square = func(name string, west, south, east, north float64) *PolygonStt {
+		var polygon = &PolygonStt{Tag: map[string]string{"name": name}}
+		polygon.AddLngLatDegrees(west, south)
+		polygon.AddLngLatDegrees(east, south)
+		polygon.AddLngLatDegrees(east, north)
+		polygon.AddLngLatDegrees(west, north)
+		return polygon
+	}
+
+	// a city with a park as a hole, and the park itself
+	var city = square("city", -46.8, -23.8, -46.4, -23.4)
+	city.Inner = []PolygonStt{*square("", -46.7, -23.7, -46.6, -23.6)}
+	var park = square("park", -46.7, -23.7, -46.6, -23.6)
+	var airport = square("airport", -46.5, -23.3, -46.4, -23.2)
+
+	var tree RTreeStt
+	_ = tree.Load([]RTreeItem{city, park, airport})
+
+	var point PointStt
+	point.SetLngLatDegrees(-46.65, -23.65)
+	for _, item := range tree.Containing(point) {
+		fmt.Printf("containing: %v\n", item.(*PolygonStt).Tag["name"])
+	}
+
+	nearest, _ := tree.Nearest(point, 3)
+	for _, neighbor := range nearest {
+		fmt.Printf("nearest: %v %.0fm\n", neighbor.Item.(*PolygonStt).Tag["name"], neighbor.Distance.GetMeters())
+	}
+
+	_ = tree.Delete(park)
+	fmt.Printf("after delete: %v items, %v containing\n", tree.Len(), len(tree.Containing(point)))
+
+	// Output:
+	// containing: park
+	// nearest: park 0m
+	// nearest: city 5096m
+	// nearest: airport 41834m
+	// after delete: 2 items, 0 containing
+}
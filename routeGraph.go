@@ -0,0 +1,639 @@
+package iotmaker_geo_osm
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// routeNodeKeyStt identifies a node shared by the ways: by the id of the node, when the way has them, or by the
+// location
+type routeNodeKeyStt struct {
+	id  int64
+	loc [2]float64
+}
+
+type routeNodeStt struct {
+	id  int64
+	loc [2]float64
+
+	// direction from the center of the Earth, for the heuristic of A*
+	direction [3]float64
+}
+
+// routeEdgeStt goes along the way from the point first to the point last, which is before first when the edge goes
+// against the direction of the way
+type routeEdgeStt struct {
+	from, to    int
+	weight      float64
+	distance    float64
+	duration    float64
+	way         *WayStt
+	first, last int
+}
+
+// English: Graph of the streets and roads for the search of the shortest path between two points.
+//
+// Build() splits the ways at the nodes they share and gives to each part the directions allowed by the tags oneway and
+// junction, for the path of the least distance. BuildWithProfile() does the same with the speed and the access of a
+// RouteProfileStt, for the path of the least cost. AddRestrictions() adds the turn restrictions of the relations and
+// Contract() prepares the graph for the fast searches of ContractionHierarchy(). Isochrone() and Isodistance() are the
+// regions reached from a point. The graph does not change after it is built, many goroutines can search it at the
+// same time.
+//
+// Português: Grafo das ruas e estradas para a busca do caminho mais curto entre dois pontos.
+//
+// Build() divide os ways nos nodes que eles compartilham e dá a cada parte os sentidos permitidos pelas tags oneway e
+// junction, para o caminho de menor distância. BuildWithProfile() faz o mesmo com a velocidade e o acesso de um
+// RouteProfileStt, para o caminho de menor custo. AddRestrictions() adiciona as restrições de conversão das relações e
+// Contract() prepara o grafo para as buscas rápidas de ContractionHierarchy(). Isochrone() e Isodistance() são as
+// regiões alcançadas a partir de um ponto. O grafo não muda depois de montado, muitas goroutines podem buscar nele ao
+// mesmo tempo.
+type RouteGraphStt struct {
+	nodes    []routeNodeStt
+	edges    []routeEdgeStt
+	outgoing [][]int
+	incoming [][]int
+	network  routeNetworkStt
+
+	// the edges have the time of the profile
+	timed bool
+
+	// tags of access of the profile, for the turn restrictions of each vehicle, and the restrictions added
+	vehicles     []string
+	restrictions []routeRestrictionStt
+
+	// contraction hierarchy of the network, made by Contract()
+	hierarchy *routeHierarchyStt
+
+	// least weight of a meter of the edges on the sphere of the polar radius of the Earth, for the heuristic of A*
+	weightPerMeter float64
+	radius         float64
+
+	// ellipsoid of the datum selected when the graph was built, for the directions and the tree of the nodes
+	ellipsoid EllipsoidStt
+
+	tree   GeodesicTreeStt
+	points map[*PointStt]int
+}
+
+// routeWayDirection are the directions of the way allowed by the tags, along the points of the way and against them.
+// The first of the keys found in the tags decides, then the roundabouts and the motorways are one way.
+func routeWayDirection(tag map[string]string, keys []string) (forward, backward bool) {
+	for _, key := range keys {
+		switch tag[key] {
+		case "yes", "true", "1":
+			return true, false
+		case "-1", "reverse":
+			return false, true
+		case "no", "false", "0":
+			return true, true
+		case "reversible", "alternating":
+			return false, false
+		}
+	}
+
+	if tag["junction"] == "roundabout" || tag["junction"] == "circular" || tag["highway"] == "motorway" {
+		return true, false
+	}
+
+	return true, true
+}
+
+// routeNodeKey is the key of the point of the way
+func routeNodeKey(way *WayStt, index int) routeNodeKeyStt {
+	if len(way.IdNode) == len(way.Loc) {
+		return routeNodeKeyStt{id: way.IdNode[index]}
+	}
+
+	return routeNodeKeyStt{loc: way.Loc[index]}
+}
+
+// routeAccessFunc is the speed, in km/h, the factor of the cost of the time and the directions allowed on the way;
+// the speed is zero when the cost is the distance
+type routeAccessFunc func(tag map[string]string) (speed, cost float64, forward, backward bool)
+
+// English: Replaces the graph by the one of the ways with the tag highway, for the path of the least distance. The
+// ways that share a node are joined at it, by the id of the node in IdNode or, when the way has no ids, by the
+// location.
+//
+// Português: Substitui o grafo pelo dos ways com a tag highway, para o caminho de menor distância. Os ways que
+// compartilham um node são unidos nele, pelo id do node em IdNode ou, quando o way não tem ids, pela localização.
+func (el *RouteGraphStt) Build(ways []*WayStt) error {
+	return el.build(ways, false, nil, func(tag map[string]string) (float64, float64, bool, bool) {
+		if tag["highway"] == "" {
+			return 0, 0, false, false
+		}
+
+		forward, backward := routeWayDirection(tag, []string{"oneway"})
+		return 0, 1, forward, backward
+	})
+}
+
+// English: Replaces the graph by the one of the ways accessible to the profile, for the path of the least cost: the
+// time on each way, at the speed of the profile, times the cost of its highway. The path found has the time, in
+// seconds, in Data["duration"].
+//
+// Português: Substitui o grafo pelo dos ways acessíveis ao perfil, para o caminho de menor custo: o tempo em cada way,
+// na velocidade do perfil, vezes o custo da sua highway. O caminho achado tem o tempo, em segundos, em
+// Data["duration"].
+func (el *RouteGraphStt) BuildWithProfile(ways []*WayStt, profile RouteProfileStt) error {
+	if err := profile.check(); err != nil {
+		return err
+	}
+
+	return el.build(ways, true, profile.AccessTags, func(tag map[string]string) (float64, float64, bool, bool) {
+		speed, forward, backward := profile.Speed(tag)
+		return speed, profile.cost(tag), forward, backward
+	})
+}
+
+func (el *RouteGraphStt) build(ways []*WayStt, timed bool, vehicles []string, access routeAccessFunc) error {
+	var routable = make([]*WayStt, 0, len(ways))
+	var uses = make(map[routeNodeKeyStt]int)
+
+	for _, way := range ways {
+		if way == nil || len(way.Loc) < 2 {
+			continue
+		}
+
+		if _, _, forward, backward := access(way.Tag); !forward && !backward {
+			continue
+		}
+
+		routable = append(routable, way)
+		for index := range way.Loc {
+			uses[routeNodeKey(way, index)] += 1
+		}
+	}
+
+	var ellipsoid = GetDatum().Ellipsoid
+	var indexes = make(map[routeNodeKeyStt]int)
+
+	el.nodes = make([]routeNodeStt, 0)
+	el.edges = make([]routeEdgeStt, 0)
+	el.timed = timed
+	el.vehicles = vehicles
+	el.restrictions = nil
+	el.hierarchy = nil
+	el.ellipsoid = ellipsoid
+	el.radius = ellipsoid.Minor()
+	el.weightPerMeter = math.Inf(1)
+
+	var nodeIndex = func(way *WayStt, index int) int {
+		var key = routeNodeKey(way, index)
+		if node, found := indexes[key]; found {
+			return node
+		}
+
+		var node = routeNodeStt{id: key.id, loc: way.Loc[index], direction: routeNodeDirection(ellipsoid, way.Loc[index])}
+
+		indexes[key] = len(el.nodes)
+		el.nodes = append(el.nodes, node)
+
+		return len(el.nodes) - 1
+	}
+
+	for _, way := range routable {
+		var speed, cost, forward, backward = access(way.Tag)
+		var first = 0
+		var distance = 0.0
+
+		for index := 1; index != len(way.Loc); index += 1 {
+			var pointA, pointB PointStt
+			pointA.SetLngLatDegrees(way.Loc[index-1][0], way.Loc[index-1][1])
+			pointB.SetLngLatDegrees(way.Loc[index][0], way.Loc[index][1])
+
+			segment, _ := distanceAndDirection(pointA, pointB)
+			distance += segment.GetMeters()
+
+			if index != len(way.Loc)-1 && uses[routeNodeKey(way, index)] < 2 {
+				continue
+			}
+
+			var from, to = nodeIndex(way, first), nodeIndex(way, index)
+			if from != to {
+				var edge = routeEdgeStt{distance: distance, weight: distance * cost, way: way}
+				if timed {
+					edge.duration = distance / (speed / 3.6)
+					edge.weight = edge.duration * cost
+				}
+
+				if forward {
+					edge.from, edge.to, edge.first, edge.last = from, to, first, index
+					el.addEdge(edge)
+				}
+
+				if backward {
+					edge.from, edge.to, edge.first, edge.last = to, from, index, first
+					el.addEdge(edge)
+				}
+			}
+
+			first, distance = index, 0
+		}
+	}
+
+	if math.IsInf(el.weightPerMeter, 1) {
+		el.weightPerMeter = 0
+	}
+
+	el.network = newRouteNodeNetwork(el)
+
+	return el.index()
+}
+
+// routeNodeDirection is the direction of the location from the center of the Earth
+func routeNodeDirection(ellipsoid EllipsoidStt, loc [2]float64) [3]float64 {
+	var cartesian = ellipsoid.toCartesian(loc[1], loc[0], 0)
+	var length = math.Sqrt(cartesian[0]*cartesian[0] + cartesian[1]*cartesian[1] + cartesian[2]*cartesian[2])
+
+	return [3]float64{cartesian[0] / length, cartesian[1] / length, cartesian[2] / length}
+}
+
+// index makes the lists of the edges of each node and the tree of the nodes, on the ellipsoid of the graph
+func (el *RouteGraphStt) index() error {
+	el.outgoing = make([][]int, len(el.nodes))
+	el.incoming = make([][]int, len(el.nodes))
+	for k, edge := range el.edges {
+		el.outgoing[edge.from] = append(el.outgoing[edge.from], k)
+		el.incoming[edge.to] = append(el.incoming[edge.to], k)
+	}
+
+	var points = make([]*PointStt, len(el.nodes))
+	el.points = make(map[*PointStt]int, len(el.nodes))
+	for k := range el.nodes {
+		points[k] = &PointStt{Id: el.nodes[k].id}
+		points[k].SetLngLatDegrees(el.nodes[k].loc[0], el.nodes[k].loc[1])
+		el.points[points[k]] = k
+	}
+
+	return el.tree.LoadOnEllipsoid(ellipsoidOrSelected(el.ellipsoid), points)
+}
+
+// addEdge adds the edge and keeps the least weight of a meter measured as the heuristic measures it, by the angle
+// between the ends of the edge on the sphere of the polar radius, so that the heuristic stays below the weight of the
+// path with any model of distance
+func (el *RouteGraphStt) addEdge(edge routeEdgeStt) {
+	var angle = routeDirectionAngle(el.nodes[edge.from].direction, el.nodes[edge.to].direction)
+	if angle > 0 {
+		el.weightPerMeter = math.Min(el.weightPerMeter, edge.weight/(angle*el.radius))
+	}
+
+	el.edges = append(el.edges, edge)
+}
+
+// nearestNode is the node of the graph nearest to the point
+func (el *RouteGraphStt) nearestNode(point PointStt) (int, error) {
+	nearest, err := el.tree.Nearest(point, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(nearest) == 0 {
+		return 0, fmt.Errorf("route: the graph has no nodes")
+	}
+
+	return el.points[nearest[0].Point], nil
+}
+
+// endpoints are the nodes nearest to the points of departure and arrival
+func (el *RouteGraphStt) endpoints(from, to PointStt) (int, int, error) {
+	source, err := el.nearestNode(from)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	target, err := el.nearestNode(to)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return source, target, nil
+}
+
+// routeDirectionAngle is the angle, in radians, between two directions from the center of the Earth
+func routeDirectionAngle(a, b [3]float64) float64 {
+	var cross = [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+
+	return math.Atan2(math.Sqrt(cross[0]*cross[0]+cross[1]*cross[1]+cross[2]*cross[2]), a[0]*b[0]+a[1]*b[1]+a[2]*b[2])
+}
+
+// heuristic is a weight never greater than the one of the path from the vertex to the target: the great circle on the
+// sphere of the polar radius at the least weight of a meter of the edges, both measured by the angle between the
+// directions, which never sum less along the path than straight to the target
+func (el *RouteGraphStt) heuristic(vertex, target int) float64 {
+	var angle = routeDirectionAngle(el.nodes[el.network.node[vertex]].direction, el.nodes[target].direction)
+
+	return angle * el.radius * el.weightPerMeter
+}
+
+// routeArcStt goes to the vertex along the edge of the graph, or along no edge, -1, when it arrives at the end of the
+// path. In the incoming arcs, the vertex is the one where the arc starts.
+type routeArcStt struct {
+	vertex int
+	weight float64
+	edge   int
+}
+
+// routeNetworkStt is the graph where the searches run: the nodes of the graph themselves or, with the turn
+// restrictions, the edges of the graph, as the turns are from edge to edge, with a vertex of departure and one of
+// arrival for each node
+type routeNetworkStt struct {
+	outgoing [][]routeArcStt
+	incoming [][]routeArcStt
+
+	// node of the graph at each vertex, for the heuristic, and the first vertex of departure and of arrival
+	node      []int
+	departure int
+	arrival   int
+}
+
+// newRouteNodeNetwork is the network of the nodes of the graph, where the edges are the arcs
+func newRouteNodeNetwork(graph *RouteGraphStt) routeNetworkStt {
+	var network = routeNetworkStt{
+		outgoing: make([][]routeArcStt, len(graph.nodes)),
+		incoming: make([][]routeArcStt, len(graph.nodes)),
+		node:     make([]int, len(graph.nodes)),
+	}
+
+	for k := range graph.nodes {
+		network.node[k] = k
+	}
+
+	for k, edge := range graph.edges {
+		network.outgoing[edge.from] = append(network.outgoing[edge.from], routeArcStt{vertex: edge.to, weight: edge.weight, edge: k})
+		network.incoming[edge.to] = append(network.incoming[edge.to], routeArcStt{vertex: edge.from, weight: edge.weight, edge: k})
+	}
+
+	return network
+}
+
+// routeQueueStt is the queue of the vertices by weight, for heap
+type routeQueueStt []routeQueueEntryStt
+
+type routeQueueEntryStt struct {
+	vertex   int
+	priority float64
+}
+
+func (el routeQueueStt) Len() int            { return len(el) }
+func (el routeQueueStt) Less(i, j int) bool  { return el[i].priority < el[j].priority }
+func (el routeQueueStt) Swap(i, j int)       { el[i], el[j] = el[j], el[i] }
+func (el *routeQueueStt) Push(x interface{}) { *el = append(*el, x.(routeQueueEntryStt)) }
+func (el *routeQueueStt) Pop() interface{} {
+	var last = (*el)[len(*el)-1]
+	*el = (*el)[:len(*el)-1]
+	return last
+}
+
+// routeSearchStt is the state of a search in one direction: the weight from the start to each vertex and the arc that
+// arrives at it, with the vertex where the arc starts
+type routeSearchStt struct {
+	weight map[int]float64
+	parent map[int]routeArcStt
+	done   map[int]bool
+	queue  routeQueueStt
+}
+
+func newRouteSearch(start int) *routeSearchStt {
+	var search = &routeSearchStt{
+		weight: map[int]float64{start: 0},
+		parent: map[int]routeArcStt{start: {vertex: -1, edge: -1}},
+		done:   make(map[int]bool),
+	}
+	heap.Push(&search.queue, routeQueueEntryStt{vertex: start})
+
+	return search
+}
+
+// next is the vertex of the least weight not yet done, or -1 when there is none
+func (el *routeSearchStt) next() int {
+	for el.queue.Len() != 0 {
+		var entry = heap.Pop(&el.queue).(routeQueueEntryStt)
+		if !el.done[entry.vertex] {
+			el.done[entry.vertex] = true
+			return entry.vertex
+		}
+	}
+
+	return -1
+}
+
+// relax reaches the vertex by the arc from the vertex from, when it is the lightest way found to the vertex
+func (el *routeSearchStt) relax(from int, arc routeArcStt, heuristic float64) {
+	var weight = el.weight[from] + arc.weight
+	if known, found := el.weight[arc.vertex]; found && known <= weight {
+		return
+	}
+
+	el.weight[arc.vertex] = weight
+	el.parent[arc.vertex] = routeArcStt{vertex: from, edge: arc.edge}
+	heap.Push(&el.queue, routeQueueEntryStt{vertex: arc.vertex, priority: weight + heuristic})
+}
+
+// edges are the edges of the graph from the start of the search to the vertex, in the order of the search
+func (el *routeSearchStt) edges(vertex int) []int {
+	var edges = make([]int, 0)
+	for parent := el.parent[vertex]; parent.vertex != -1; parent = el.parent[parent.vertex] {
+		if parent.edge != -1 {
+			edges = append(edges, parent.edge)
+		}
+	}
+
+	return edges
+}
+
+// routeReverse reverses the edges in place
+func routeReverse(edges []int) []int {
+	for left, right := 0, len(edges)-1; left < right; left, right = left+1, right-1 {
+		edges[left], edges[right] = edges[right], edges[left]
+	}
+
+	return edges
+}
+
+// search is A* from the source to the target; Dijkstra is A* without the heuristic
+func (el *RouteGraphStt) search(source, target int, useHeuristic bool) ([]int, error) {
+	if source == target {
+		return []int{}, nil
+	}
+
+	var network = &el.network
+	var search = newRouteSearch(network.departure + source)
+
+	for vertex := search.next(); vertex != -1; vertex = search.next() {
+		if vertex == network.arrival+target {
+			return routeReverse(search.edges(vertex)), nil
+		}
+
+		for _, arc := range network.outgoing[vertex] {
+			var heuristic = 0.0
+			if useHeuristic {
+				heuristic = el.heuristic(arc.vertex, target)
+			}
+
+			search.relax(vertex, arc, heuristic)
+		}
+	}
+
+	return nil, fmt.Errorf("route: there is no path between the points")
+}
+
+// bidirectional searches from the source forwards and from the target backwards, until the least weights of the two
+// queues together are not less than the best path found through a vertex reached by both
+func (el *RouteGraphStt) bidirectional(source, target int) ([]int, error) {
+	if source == target {
+		return []int{}, nil
+	}
+
+	var network = &el.network
+	var forward, backward = newRouteSearch(network.departure + source), newRouteSearch(network.arrival + target)
+	var best, meeting = math.Inf(1), -1
+
+	var meet = func(vertex int) {
+		weightForward, foundForward := forward.weight[vertex]
+		weightBackward, foundBackward := backward.weight[vertex]
+		if foundForward && foundBackward && weightForward+weightBackward < best {
+			best, meeting = weightForward+weightBackward, vertex
+		}
+	}
+
+	for forward.queue.Len() != 0 && backward.queue.Len() != 0 {
+		if forward.queue[0].priority+backward.queue[0].priority >= best {
+			break
+		}
+
+		if forward.queue.Len() <= backward.queue.Len() {
+			var vertex = forward.next()
+			if vertex == -1 {
+				break
+			}
+
+			for _, arc := range network.outgoing[vertex] {
+				forward.relax(vertex, arc, 0)
+				meet(arc.vertex)
+			}
+		} else {
+			var vertex = backward.next()
+			if vertex == -1 {
+				break
+			}
+
+			for _, arc := range network.incoming[vertex] {
+				backward.relax(vertex, arc, 0)
+				meet(arc.vertex)
+			}
+		}
+	}
+
+	if meeting == -1 {
+		return nil, fmt.Errorf("route: there is no path between the points")
+	}
+
+	return append(routeReverse(forward.edges(meeting)), backward.edges(meeting)...), nil
+}
+
+// way is the path of the edges as a way, with the points of the ways that the edges go along
+func (el *RouteGraphStt) way(source int, edges []int) (WayStt, error) {
+	var path WayStt
+	var withIds = el.nodes[source].id != 0
+
+	var duration = 0.0
+
+	path.Loc = [][2]float64{el.nodes[source].loc}
+	path.IdNode = []int64{el.nodes[source].id}
+
+	for _, edge := range edges {
+		duration += el.edges[edge].duration
+
+		var step = 1
+		if el.edges[edge].last < el.edges[edge].first {
+			step = -1
+		}
+
+		var way = el.edges[edge].way
+		withIds = withIds && len(way.IdNode) == len(way.Loc)
+
+		for index := el.edges[edge].first + step; index != el.edges[edge].last+step; index += step {
+			path.Loc = append(path.Loc, way.Loc[index])
+			if withIds {
+				path.IdNode = append(path.IdNode, way.IdNode[index])
+			}
+		}
+	}
+
+	if !withIds {
+		path.IdNode = nil
+	}
+
+	path.Rad = make([][2]float64, len(path.Loc))
+	for k := range path.Loc {
+		path.Rad[k] = [2]float64{DegreesToRadians(path.Loc[k][0]), DegreesToRadians(path.Loc[k][1])}
+	}
+
+	if err := path.Init(); err != nil {
+		return WayStt{}, err
+	}
+
+	if el.timed {
+		path.Data = map[string]string{"duration": strconv.FormatFloat(duration, 'f', 1, 64)}
+	}
+
+	return path, nil
+}
+
+// English: Shortest path by Dijkstra, from the node of the graph nearest to the point from to the one nearest to the
+// point to. The path is a way with the points of the ways it goes along; DistanceTotal is the length of the path.
+//
+// Português: Caminho mais curto por Dijkstra, do node do grafo mais próximo do ponto from até o mais próximo do ponto
+// to. O caminho é um way com os pontos dos ways pelos quais ele passa; DistanceTotal é o comprimento do caminho.
+func (el *RouteGraphStt) Dijkstra(from, to PointStt) (WayStt, error) {
+	source, target, err := el.endpoints(from, to)
+	if err != nil {
+		return WayStt{}, err
+	}
+
+	edges, err := el.search(source, target, false)
+	if err != nil {
+		return WayStt{}, err
+	}
+
+	return el.way(source, edges)
+}
+
+// English: Same as Dijkstra(), with the search from both points at the same time, which visits fewer nodes.
+//
+// Português: O mesmo que Dijkstra(), com a busca a partir dos dois pontos ao mesmo tempo, que visita menos nodes.
+func (el *RouteGraphStt) BidirectionalDijkstra(from, to PointStt) (WayStt, error) {
+	source, target, err := el.endpoints(from, to)
+	if err != nil {
+		return WayStt{}, err
+	}
+
+	edges, err := el.bidirectional(source, target)
+	if err != nil {
+		return WayStt{}, err
+	}
+
+	return el.way(source, edges)
+}
+
+// English: Same as Dijkstra(), guided towards the point to by the great circle distance, which visits fewer nodes.
+//
+// Português: O mesmo que Dijkstra(), guiado em direção ao ponto to pela distância do grande círculo, que visita menos
+// nodes.
+func (el *RouteGraphStt) AStar(from, to PointStt) (WayStt, error) {
+	source, target, err := el.endpoints(from, to)
+	if err != nil {
+		return WayStt{}, err
+	}
+
+	edges, err := el.search(source, target, true)
+	if err != nil {
+		return WayStt{}, err
+	}
+
+	return el.way(source, edges)
+}
@@ -0,0 +1,460 @@
+package iotmaker_geo_osm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// routeFileMagic identifies the file of the graph and the version of its format
+const routeFileMagic = "IOTROUTE1"
+
+// routeFileMaxLength is the greatest number of items of a list in the file, when the size of the file is not known
+const routeFileMaxLength = 1 << 28
+
+// routeFileWriterStt writes the numbers in little endian and keeps the first error
+type routeFileWriterStt struct {
+	writer *bufio.Writer
+	buffer [8]byte
+	err    error
+}
+
+func (el *routeFileWriterStt) integer(value int) {
+	if el.err == nil {
+		binary.LittleEndian.PutUint64(el.buffer[:], uint64(int64(value)))
+		_, el.err = el.writer.Write(el.buffer[:])
+	}
+}
+
+func (el *routeFileWriterStt) float(value float64) {
+	if el.err == nil {
+		binary.LittleEndian.PutUint64(el.buffer[:], math.Float64bits(value))
+		_, el.err = el.writer.Write(el.buffer[:])
+	}
+}
+
+func (el *routeFileWriterStt) boolean(value bool) {
+	if value {
+		el.integer(1)
+	} else {
+		el.integer(0)
+	}
+}
+
+func (el *routeFileWriterStt) text(value string) {
+	el.integer(len(value))
+	if el.err == nil {
+		_, el.err = el.writer.WriteString(value)
+	}
+}
+
+func (el *routeFileWriterStt) list(values []int) {
+	el.integer(len(values))
+	for _, value := range values {
+		el.integer(value)
+	}
+}
+
+// set writes the keys of the set in order, so the same graph always makes the same file
+func (el *routeFileWriterStt) set(values map[int]bool) {
+	var keys = make([]int, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	el.list(keys)
+}
+
+// routeFileReaderStt reads what routeFileWriterStt writes, checks the indices and the lengths and keeps the first
+// error. The bytes that remain in the file bound the lengths, or -1 when the size of the file is not known.
+type routeFileReaderStt struct {
+	reader    *bufio.Reader
+	buffer    [8]byte
+	remaining int64
+	err       error
+}
+
+// newRouteFileReader reads the file and, when it tells its size, as os.File and bytes.Reader do, bounds the lengths
+// by it
+func newRouteFileReader(file io.Reader) *routeFileReaderStt {
+	var reader = &routeFileReaderStt{reader: bufio.NewReader(file), remaining: -1}
+
+	switch sized := file.(type) {
+	case interface{ Len() int }:
+		reader.remaining = int64(sized.Len())
+
+	case interface{ Stat() (os.FileInfo, error) }:
+		info, err := sized.Stat()
+		if seeker, ok := file.(io.Seeker); ok && err == nil && info.Mode().IsRegular() {
+			if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+				reader.remaining = info.Size() - offset
+			}
+		}
+	}
+
+	return reader
+}
+
+// read fills the buffer from the file
+func (el *routeFileReaderStt) read(buffer []byte) {
+	if el.err != nil {
+		return
+	}
+
+	_, el.err = io.ReadFull(el.reader, buffer)
+	if el.remaining != -1 {
+		el.remaining -= int64(len(buffer))
+	}
+}
+
+func (el *routeFileReaderStt) integer() int {
+	if el.read(el.buffer[:]); el.err != nil {
+		return 0
+	}
+
+	return int(int64(binary.LittleEndian.Uint64(el.buffer[:])))
+}
+
+func (el *routeFileReaderStt) float() float64 {
+	if el.read(el.buffer[:]); el.err != nil {
+		return 0
+	}
+
+	return math.Float64frombits(binary.LittleEndian.Uint64(el.buffer[:]))
+}
+
+func (el *routeFileReaderStt) boolean() bool {
+	return el.integer() != 0
+}
+
+// index is an index less than the length or, when none is true, -1
+func (el *routeFileReaderStt) index(length int, none bool) int {
+	var value = el.integer()
+	if el.err == nil && (value >= length || value < 0 && !(none && value == -1)) {
+		el.err = fmt.Errorf("route: the file has the index %v out of the range of %v items", value, length)
+	}
+
+	return value
+}
+
+// length is the number of items that follow, each one with at least the size in bytes, which must not be negative
+// nor more than the bytes that remain in the file
+func (el *routeFileReaderStt) length(size int) int {
+	var value = el.integer()
+	switch {
+	case el.err != nil:
+	case value < 0:
+		el.err = fmt.Errorf("route: the file has a negative length")
+	case el.remaining == -1 && value > routeFileMaxLength:
+		el.err = fmt.Errorf("route: the file has the length %v, more than the maximum of %v", value, routeFileMaxLength)
+	case el.remaining != -1 && int64(value) > el.remaining/int64(size):
+		el.err = fmt.Errorf("route: the file has the length %v, more than the %v bytes that remain", value, el.remaining)
+	}
+
+	if el.err != nil {
+		return 0
+	}
+
+	return value
+}
+
+func (el *routeFileReaderStt) text() string {
+	var buffer = make([]byte, el.length(1))
+	el.read(buffer)
+
+	return string(buffer)
+}
+
+func (el *routeFileReaderStt) list(length int) []int {
+	var values = make([]int, el.length(8))
+	for k := range values {
+		values[k] = el.index(length, false)
+	}
+
+	return values
+}
+
+func (el *routeFileReaderStt) set(length int) map[int]bool {
+	var values = make(map[int]bool)
+	for _, value := range el.list(length) {
+		values[value] = true
+	}
+
+	return values
+}
+
+// English: Writes the graph, with the turn restrictions and the preparation of Contract(), in a binary format read by
+// FromFile() without building the graph again. The ways of the graph keep only the id, the points and the ids of the
+// nodes, which are what the paths need.
+//
+// Português: Escreve o grafo, com as restrições de conversão e a preparação de Contract(), em um formato binário lido
+// por FromFile() sem montar o grafo de novo. Os ways do grafo guardam apenas o id, os pontos e os ids dos nodes, que são
+// o que os caminhos precisam.
+func (el *RouteGraphStt) ToFile(file io.Writer) error {
+	var writer = routeFileWriterStt{writer: bufio.NewWriter(file)}
+	_, writer.err = writer.writer.WriteString(routeFileMagic)
+
+	writer.boolean(el.timed)
+	writer.float(el.weightPerMeter)
+	writer.float(el.radius)
+
+	writer.boolean(el.vehicles != nil)
+	writer.integer(len(el.vehicles))
+	for _, vehicle := range el.vehicles {
+		writer.text(vehicle)
+	}
+
+	var ways = make(map[*WayStt]int)
+	var wayList = make([]*WayStt, 0)
+	for _, edge := range el.edges {
+		if _, found := ways[edge.way]; !found {
+			ways[edge.way] = len(wayList)
+			wayList = append(wayList, edge.way)
+		}
+	}
+
+	writer.integer(len(wayList))
+	for _, way := range wayList {
+		writer.integer(int(way.Id))
+		writer.integer(len(way.Loc))
+		for _, loc := range way.Loc {
+			writer.float(loc[0])
+			writer.float(loc[1])
+		}
+
+		writer.integer(len(way.IdNode))
+		for _, id := range way.IdNode {
+			writer.integer(int(id))
+		}
+	}
+
+	writer.integer(len(el.nodes))
+	for _, node := range el.nodes {
+		writer.integer(int(node.id))
+		writer.float(node.loc[0])
+		writer.float(node.loc[1])
+	}
+
+	writer.integer(len(el.edges))
+	for _, edge := range el.edges {
+		writer.integer(edge.from)
+		writer.integer(edge.to)
+		writer.float(edge.weight)
+		writer.float(edge.distance)
+		writer.float(edge.duration)
+		writer.integer(ways[edge.way])
+		writer.integer(edge.first)
+		writer.integer(edge.last)
+	}
+
+	writer.integer(len(el.restrictions))
+	for _, restriction := range el.restrictions {
+		writer.boolean(restriction.only)
+		writer.set(restriction.from)
+		writer.list(restriction.via)
+		writer.set(restriction.to)
+	}
+
+	writer.integer(len(el.network.outgoing))
+	writer.integer(el.network.departure)
+	writer.integer(el.network.arrival)
+	for vertex, arcs := range el.network.outgoing {
+		writer.integer(el.network.node[vertex])
+		writer.integer(len(arcs))
+		for _, arc := range arcs {
+			writer.integer(arc.vertex)
+			writer.float(arc.weight)
+			writer.integer(arc.edge)
+		}
+	}
+
+	writer.boolean(el.hierarchy != nil)
+	if el.hierarchy != nil {
+		writer.integer(len(el.hierarchy.arcs))
+		for _, arc := range el.hierarchy.arcs {
+			writer.integer(arc.from)
+			writer.integer(arc.to)
+			writer.float(arc.weight)
+			writer.integer(arc.edge)
+			writer.integer(arc.first)
+			writer.integer(arc.second)
+		}
+
+		for vertex := range el.hierarchy.up {
+			writer.list(el.hierarchy.up[vertex])
+			writer.list(el.hierarchy.down[vertex])
+		}
+	}
+
+	if writer.err != nil {
+		return writer.err
+	}
+
+	return writer.writer.Flush()
+}
+
+// English: Replaces the graph by the one written by ToFile().
+//
+// Português: Substitui o grafo pelo escrito por ToFile().
+func (el *RouteGraphStt) FromFile(file io.Reader) error {
+	var reader = newRouteFileReader(file)
+	var magic = make([]byte, len(routeFileMagic))
+	if reader.read(magic); reader.err != nil || string(magic) != routeFileMagic {
+		return fmt.Errorf("route: the file is not a graph written by ToFile()")
+	}
+
+	var graph RouteGraphStt
+	var ellipsoid = GetDatum().Ellipsoid
+
+	graph.timed = reader.boolean()
+	graph.weightPerMeter = reader.float()
+	graph.radius = reader.float()
+
+	var hasVehicles = reader.boolean()
+	var vehicles = make([]string, reader.length(8))
+	for k := range vehicles {
+		vehicles[k] = reader.text()
+	}
+
+	if hasVehicles {
+		graph.vehicles = vehicles
+	}
+
+	var ways = make([]*WayStt, reader.length(24))
+	for k := range ways {
+		ways[k] = &WayStt{Id: int64(reader.integer())}
+		ways[k].Loc = make([][2]float64, reader.length(16))
+		for point := range ways[k].Loc {
+			ways[k].Loc[point] = [2]float64{reader.float(), reader.float()}
+		}
+
+		ways[k].IdNode = make([]int64, reader.length(8))
+		for point := range ways[k].IdNode {
+			ways[k].IdNode[point] = int64(reader.integer())
+		}
+	}
+
+	graph.nodes = make([]routeNodeStt, reader.length(24))
+	for k := range graph.nodes {
+		graph.nodes[k].id = int64(reader.integer())
+		graph.nodes[k].loc = [2]float64{reader.float(), reader.float()}
+		graph.nodes[k].direction = routeNodeDirection(ellipsoid, graph.nodes[k].loc)
+	}
+
+	graph.edges = make([]routeEdgeStt, reader.length(64))
+	for k := range graph.edges {
+		var edge = &graph.edges[k]
+		edge.from = reader.index(len(graph.nodes), false)
+		edge.to = reader.index(len(graph.nodes), false)
+		edge.weight = reader.float()
+		edge.distance = reader.float()
+		edge.duration = reader.float()
+
+		var way = reader.index(len(ways), false)
+		if reader.err != nil {
+			return reader.err
+		}
+
+		edge.way = ways[way]
+		edge.first = reader.index(len(edge.way.Loc), false)
+		edge.last = reader.index(len(edge.way.Loc), false)
+	}
+
+	graph.restrictions = make([]routeRestrictionStt, reader.length(32))
+	for k := range graph.restrictions {
+		graph.restrictions[k].only = reader.boolean()
+		graph.restrictions[k].from = reader.set(len(graph.edges))
+		graph.restrictions[k].via = reader.list(len(graph.edges))
+		graph.restrictions[k].to = reader.set(len(graph.edges))
+	}
+
+	var vertices = reader.length(16)
+	graph.network = routeNetworkStt{
+		outgoing:  make([][]routeArcStt, vertices),
+		incoming:  make([][]routeArcStt, vertices),
+		node:      make([]int, vertices),
+		departure: reader.index(vertices, false),
+		arrival:   reader.index(vertices, false),
+	}
+
+	for vertex := range graph.network.outgoing {
+		graph.network.node[vertex] = reader.index(len(graph.nodes), false)
+		graph.network.outgoing[vertex] = make([]routeArcStt, reader.length(24))
+		for k := range graph.network.outgoing[vertex] {
+			var arc = routeArcStt{vertex: reader.index(vertices, false), weight: reader.float(), edge: reader.index(len(graph.edges), true)}
+			if reader.err != nil {
+				return reader.err
+			}
+
+			graph.network.outgoing[vertex][k] = arc
+			graph.network.incoming[arc.vertex] = append(graph.network.incoming[arc.vertex], routeArcStt{vertex: vertex, weight: arc.weight, edge: arc.edge})
+		}
+	}
+
+	if reader.boolean() {
+		graph.hierarchy = &routeHierarchyStt{up: make([][]int, vertices), down: make([][]int, vertices)}
+		graph.hierarchy.arcs = make([]routeShortcutStt, reader.length(48))
+		for k := range graph.hierarchy.arcs {
+			graph.hierarchy.arcs[k] = routeShortcutStt{
+				from:   reader.index(vertices, false),
+				to:     reader.index(vertices, false),
+				weight: reader.float(),
+				edge:   reader.index(len(graph.edges), true),
+				first:  reader.index(k, true),
+				second: reader.index(k, true),
+			}
+		}
+
+		for vertex := range graph.hierarchy.up {
+			graph.hierarchy.up[vertex] = reader.list(len(graph.hierarchy.arcs))
+			graph.hierarchy.down[vertex] = reader.list(len(graph.hierarchy.arcs))
+		}
+	}
+
+	if reader.err != nil {
+		return reader.err
+	}
+
+	el.nodes, el.edges, el.network = graph.nodes, graph.edges, graph.network
+	el.timed, el.vehicles, el.restrictions = graph.timed, graph.vehicles, graph.restrictions
+	el.hierarchy, el.weightPerMeter, el.radius = graph.hierarchy, graph.weightPerMeter, graph.radius
+	el.ellipsoid = ellipsoid
+
+	return el.index()
+}
+
+// English: Writes the graph in the file, as ToFile().
+//
+// Português: Escreve o grafo no arquivo, como ToFile().
+func (el *RouteGraphStt) ToFilePath(filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	err = el.ToFile(file)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// English: Replaces the graph by the one in the file written by ToFilePath().
+//
+// Português: Substitui o grafo pelo do arquivo escrito por ToFilePath().
+func (el *RouteGraphStt) FromFilePath(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return el.FromFile(file)
+}
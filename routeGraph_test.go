@@ -0,0 +1,262 @@
+package iotmaker_geo_osm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// routeTestNodesStt are the locations of the nodes of the graph of an example, by their ids
+type routeTestNodesStt map[int64][2]float64
+
+// way is the way with the tags along the nodes
+func (el routeTestNodesStt) way(tag map[string]string, ids ...int64) *WayStt {
+	var way = &WayStt{Tag: tag}
+	for _, id := range ids {
+		way.IdNode = append(way.IdNode, id)
+		way.Loc = append(way.Loc, el[id])
+	}
+	return way
+}
+
+func ExampleRouteGraphStt() {
+	var nodes = routeTestNodesStt{
+		1: {-46.630, -23.550},
+		2: {-46.620, -23.550},
+		3: {-46.620, -23.540},
+		4: {-46.630, -23.540},
+	}
+
+	var graph RouteGraphStt
+	_ = graph.Build([]*WayStt{
+		nodes.way(map[string]string{"highway": "primary"}, 1, 2, 3),
+		nodes.way(map[string]string{"highway": "residential", "oneway": "yes"}, 3, 4, 1),
+		nodes.way(map[string]string{"highway": "residential", "oneway": "-1"}, 1, 3),
+		nodes.way(map[string]string{"waterway": "river"}, 2, 4),
+	})
+
+	var pointA, pointB PointStt
+	pointA.SetLngLatDegrees(-46.6301, -23.5501)
+	pointB.SetLngLatDegrees(-46.6199, -23.5399)
+
+	path, _ := graph.Dijkstra(pointA, pointB)
+	fmt.Printf("dijkstra: %v %.1fm\n", path.IdNode, path.DistanceTotal.GetMeters())
+
+	path, _ = graph.BidirectionalDijkstra(pointB, pointA)
+	fmt.Printf("bidirectional: %v %.1fm\n", path.IdNode, path.DistanceTotal.GetMeters())
+
+	path, _ = graph.AStar(pointB, pointA)
+	fmt.Printf("a*: %v %.1fm\n", path.IdNode, path.DistanceTotal.GetMeters())
+
+	// Output:
+	// dijkstra: [1 2 3] 2132.5m
+	// bidirectional: [3 1] 1509.4m
+	// a*: [3 1] 1509.4m
+}
+
+func ExampleRouteGraphStt_AStar() {
+	// far from the equator the streets run along the parallels, where the heuristic must not overestimate the way
+	var nodes = routeTestNodesStt{
+		1: {10.0, 60.0},
+		2: {10.2, 60.0},
+		3: {10.1, 60.0},
+		4: {10.2, 59.99985},
+	}
+
+	var tag = map[string]string{"highway": "primary"}
+	var graph RouteGraphStt
+	_ = graph.Build([]*WayStt{nodes.way(tag, 1, 3), nodes.way(tag, 3, 2), nodes.way(tag, 1, 4), nodes.way(tag, 4, 2)})
+
+	var pointA, pointB PointStt
+	pointA.SetLngLatDegrees(10.0, 60.0)
+	pointB.SetLngLatDegrees(10.2, 60.0)
+
+	path, _ := graph.Dijkstra(pointA, pointB)
+	fmt.Printf("dijkstra: %v %.1fm\n", path.IdNode, path.DistanceTotal.GetMeters())
+
+	path, _ = graph.AStar(pointA, pointB)
+	fmt.Printf("a*: %v %.1fm\n", path.IdNode, path.DistanceTotal.GetMeters())
+
+	// Output:
+	// dijkstra: [1 3 2] 11104.0m
+	// a*: [1 3 2] 11104.0m
+}
+
+func ExampleRouteGraphStt_BuildWithProfile() {
+	var nodes = routeTestNodesStt{
+		1: {-46.630, -23.550},
+		2: {-46.620, -23.550},
+		3: {-46.610, -23.550},
+		4: {-46.620, -23.560},
+	}
+
+	// the short way passes under a low bridge, the long one is a residential street
+	var ways = []*WayStt{
+		nodes.way(map[string]string{"highway": "primary", "maxheight": "3.2"}, 1, 2, 3),
+		nodes.way(map[string]string{"highway": "residential"}, 1, 4, 3),
+	}
+
+	var pointA, pointB PointStt
+	pointA.SetLngLatDegrees(-46.630, -23.550)
+	pointB.SetLngLatDegrees(-46.610, -23.550)
+
+	for _, profile := range []RouteProfileStt{ROUTE_PROFILE_CAR, ROUTE_PROFILE_TRUCK} {
+		var graph RouteGraphStt
+		_ = graph.BuildWithProfile(ways, profile)
+
+		path, _ := graph.AStar(pointA, pointB)
+		fmt.Printf("%v: %v %.1fm %vs\n", profile.Name, path.IdNode, path.DistanceTotal.GetMeters(), path.Data["duration"])
+	}
+
+	// Output:
+	// car: [1 2 3] 2039.9m 104.9s
+	// truck: [1 4 3] 3018.7m 434.7s
+}
+
+func ExampleRouteGraphStt_AddRestrictions() {
+	var nodes = routeTestNodesStt{
+		1: {-46.630, -23.550},
+		2: {-46.620, -23.550},
+		3: {-46.610, -23.550},
+		4: {-46.620, -23.540},
+	}
+
+	var residential = map[string]string{"highway": "residential"}
+	var ways = []*WayStt{nodes.way(residential, 1, 2), nodes.way(residential, 2, 3), nodes.way(residential, 2, 4), nodes.way(residential, 3, 4)}
+	for k, id := range []int64{10, 11, 12, 13} {
+		ways[k].Id = id
+	}
+
+	// coming from the west, the left turn to the north is forbidden, except for the bicycles
+	var relations = []*RelationStt{
+		{
+			Tag: map[string]string{"type": "restriction", "restriction": "no_left_turn", "except": "bicycle"},
+			Members: []MembersStt{
+				{Type: "way", Ref: 10, Role: "from"},
+				{Type: "node", Ref: 2, Role: "via"},
+				{Type: "way", Ref: 12, Role: "to"},
+			},
+		},
+	}
+
+	var pointA, pointB PointStt
+	pointA.SetLngLatDegrees(-46.630, -23.550)
+	pointB.SetLngLatDegrees(-46.620, -23.540)
+
+	for _, profile := range []RouteProfileStt{ROUTE_PROFILE_CAR, ROUTE_PROFILE_BIKE} {
+		var graph RouteGraphStt
+		_ = graph.BuildWithProfile(ways, profile)
+		added, _ := graph.AddRestrictions(relations)
+
+		path, _ := graph.Dijkstra(pointA, pointB)
+		fmt.Printf("%v: %v restrictions, %v %.1fm\n", profile.Name, added, path.IdNode, path.DistanceTotal.GetMeters())
+	}
+
+	// Output:
+	// car: 1 restrictions, [1 2 3 4] 3549.3m
+	// bike: 0 restrictions, [1 2 4] 2132.5m
+}
+
+func ExampleRouteGraphStt_Contract() {
+	var nodes = routeTestNodesStt{
+		1: {-46.630, -23.550},
+		2: {-46.620, -23.550},
+		3: {-46.620, -23.540},
+		4: {-46.630, -23.540},
+	}
+
+	var graph RouteGraphStt
+	_ = graph.Build([]*WayStt{
+		nodes.way(map[string]string{"highway": "primary"}, 1, 2, 3),
+		nodes.way(map[string]string{"highway": "residential", "oneway": "yes"}, 3, 4, 1),
+		nodes.way(map[string]string{"highway": "residential", "oneway": "-1"}, 1, 3),
+	})
+	_ = graph.Contract()
+
+	// the graph prepared is written once and read by each instance of the service
+	var file bytes.Buffer
+	_ = graph.ToFile(&file)
+
+	var loaded RouteGraphStt
+	_ = loaded.FromFile(&file)
+
+	var pointA, pointB PointStt
+	pointA.SetLngLatDegrees(-46.6301, -23.5501)
+	pointB.SetLngLatDegrees(-46.6199, -23.5399)
+
+	path, _ := loaded.ContractionHierarchy(pointA, pointB)
+	fmt.Printf("a to b: %v %.1fm\n", path.IdNode, path.DistanceTotal.GetMeters())
+
+	path, _ = loaded.ContractionHierarchy(pointB, pointA)
+	fmt.Printf("b to a: %v %.1fm\n", path.IdNode, path.DistanceTotal.GetMeters())
+
+	// Output:
+	// a to b: [1 2 3] 2132.5m
+	// b to a: [3 1] 1509.4m
+}
+
+func ExampleRouteGraphStt_FromFile() {
+	var nodes = routeTestNodesStt{
+		1: {-46.630, -23.550},
+		2: {-46.620, -23.550},
+		3: {-46.620, -23.540},
+	}
+
+	var graph RouteGraphStt
+	_ = graph.Build([]*WayStt{nodes.way(map[string]string{"highway": "primary"}, 1, 2, 3)})
+
+	var file bytes.Buffer
+	_ = graph.ToFile(&file)
+	var data = file.Bytes()
+
+	var loaded RouteGraphStt
+	fmt.Printf("whole: %v\n", loaded.FromFile(bytes.NewReader(data)))
+	fmt.Printf("truncated: %v\n", loaded.FromFile(bytes.NewReader(data[:len(data)/2])))
+
+	// the number of the vehicles, after the magic and four numbers, made too big to be read
+	var corrupt = append([]byte{}, data...)
+	binary.LittleEndian.PutUint64(corrupt[len("IOTROUTE1")+4*8:], 1<<62)
+	fmt.Printf("corrupt: %v\n", loaded.FromFile(bytes.NewReader(corrupt)))
+	fmt.Printf("corrupt stream: %v\n", loaded.FromFile(io.MultiReader(bytes.NewReader(corrupt))))
+
+	// Output:
+	// whole: <nil>
+	// truncated: route: the file has the length 2, more than the 15 bytes that remain
+	// corrupt: route: the file has the length 4611686018427387904, more than the 416 bytes that remain
+	// corrupt stream: route: the file has the length 4611686018427387904, more than the maximum of 268435456
+}
+
+func ExampleRouteGraphStt_Isodistance() {
+	// a grid of five by five streets, about a hundred meters apart
+	var ways = make([]*WayStt, 0)
+	for i := 0; i != 5; i += 1 {
+		var street, avenue = &WayStt{Tag: map[string]string{"highway": "residential"}}, &WayStt{Tag: map[string]string{"highway": "residential"}}
+		for j := 0; j != 5; j += 1 {
+			street.Loc = append(street.Loc, [2]float64{-46.630 + float64(j)*0.001, -23.550 + float64(i)*0.001})
+			avenue.Loc = append(avenue.Loc, [2]float64{-46.630 + float64(i)*0.001, -23.550 + float64(j)*0.001})
+		}
+		ways = append(ways, street, avenue)
+	}
+
+	var graph RouteGraphStt
+	_ = graph.Build(ways)
+
+	var point PointStt
+	point.SetLngLatDegrees(-46.630, -23.550)
+
+	var near, far, resolution DistanceStt
+	near.SetMeters(250)
+	far.SetMeters(600)
+	resolution.SetMeters(80)
+
+	list, _ := graph.Isodistance(point, []DistanceStt{far, near}, resolution)
+	for _, polygon := range list.List {
+		area, _ := polygon.GeodesicAreaKarney()
+		fmt.Printf("%vm: %.1fha\n", polygon.Data["distance"], area.GetHectares())
+	}
+
+	// Output:
+	// 250.0m: 3.1ha
+	// 600.0m: 14.9ha
+}
@@ -0,0 +1,365 @@
+package iotmaker_geo_osm
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// number of vertices settled by the search of a path that makes a shortcut unnecessary; a search that stops before
+// finding it only adds a shortcut that is not needed
+const routeHierarchySettled = 100
+
+// routeShortcutStt is an arc of the hierarchy from the vertex from to the vertex to: an arc of the network, along the
+// edge, or a shortcut made of the arcs first and second, -1 when it is not a shortcut
+type routeShortcutStt struct {
+	from, to      int
+	weight        float64
+	edge          int
+	first, second int
+}
+
+// routeHierarchyStt is the contraction hierarchy of the network: the arcs of each vertex to the vertices contracted
+// after it, up, for the search from the source, and the arcs from the vertices contracted after it, down, for the
+// search from the target
+type routeHierarchyStt struct {
+	arcs []routeShortcutStt
+	up   [][]int
+	down [][]int
+}
+
+// routeContractorStt is the state of the contraction: the arcs between the vertices not yet contracted and the
+// weights of the search of the paths that make the shortcuts unnecessary
+type routeContractorStt struct {
+	hierarchy *routeHierarchyStt
+	outgoing  [][]int
+	incoming  [][]int
+	deleted   []int
+
+	weight []float64
+	round  []int
+	rounds int
+	target []int
+	queue  routeQueueStt
+}
+
+// arc adds the arc, or replaces the one between the same vertices when it is lighter
+func (el *routeContractorStt) arc(arc routeShortcutStt) {
+	if arc.from == arc.to {
+		return
+	}
+
+	for k, index := range el.outgoing[arc.from] {
+		if el.hierarchy.arcs[index].to == arc.to {
+			if el.hierarchy.arcs[index].weight <= arc.weight {
+				return
+			}
+
+			el.outgoing[arc.from] = append(el.outgoing[arc.from][:k], el.outgoing[arc.from][k+1:]...)
+			el.incoming[arc.to] = routeRemoveArc(el.incoming[arc.to], index)
+			break
+		}
+	}
+
+	el.hierarchy.arcs = append(el.hierarchy.arcs, arc)
+	el.outgoing[arc.from] = append(el.outgoing[arc.from], len(el.hierarchy.arcs)-1)
+	el.incoming[arc.to] = append(el.incoming[arc.to], len(el.hierarchy.arcs)-1)
+}
+
+// routeRemoveArc removes the arc from the list
+func routeRemoveArc(list []int, arc int) []int {
+	for k := range list {
+		if list[k] == arc {
+			return append(list[:k], list[k+1:]...)
+		}
+	}
+
+	return list
+}
+
+// witness searches the paths from the source that do not pass through the vertex skipped, up to the limit of weight
+// or until the targets, the vertices marked in el.target for the round, are settled, and leaves the weights found in
+// el.weight for the vertices of the round
+func (el *routeContractorStt) witness(source, skipped int, limit float64, targets int) {
+	el.weight[source], el.round[source] = 0, el.rounds
+
+	var queue = append(el.queue[:0], routeQueueEntryStt{vertex: source})
+	var settled = 0
+
+	defer func() { el.queue = queue }()
+
+	for queue.Len() != 0 && settled != routeHierarchySettled && targets != 0 {
+		var entry = heap.Pop(&queue).(routeQueueEntryStt)
+		if entry.priority > el.weight[entry.vertex] {
+			continue
+		}
+
+		if entry.priority > limit {
+			return
+		}
+
+		if el.target[entry.vertex] == el.rounds {
+			targets -= 1
+		}
+
+		settled += 1
+		for _, index := range el.outgoing[entry.vertex] {
+			var arc = &el.hierarchy.arcs[index]
+			if arc.to == skipped {
+				continue
+			}
+
+			var weight = entry.priority + arc.weight
+			if el.round[arc.to] != el.rounds || weight < el.weight[arc.to] {
+				el.weight[arc.to], el.round[arc.to] = weight, el.rounds
+				heap.Push(&queue, routeQueueEntryStt{vertex: arc.to, priority: weight})
+			}
+		}
+	}
+}
+
+// shortcuts are the shortcuts needed to contract the vertex: for each arc arriving at it and each one leaving it, when
+// there is no other path as light between the vertices
+func (el *routeContractorStt) shortcuts(vertex int) []routeShortcutStt {
+	var shortcuts = make([]routeShortcutStt, 0)
+
+	var heaviest = 0.0
+	for _, index := range el.outgoing[vertex] {
+		heaviest = math.Max(heaviest, el.hierarchy.arcs[index].weight)
+	}
+
+	for _, first := range el.incoming[vertex] {
+		var arrival = el.hierarchy.arcs[first]
+
+		el.rounds += 1
+		var targets = 0
+		for _, second := range el.outgoing[vertex] {
+			if to := el.hierarchy.arcs[second].to; to != arrival.from && el.target[to] != el.rounds {
+				el.target[to] = el.rounds
+				targets += 1
+			}
+		}
+
+		el.witness(arrival.from, vertex, arrival.weight+heaviest, targets)
+
+		for _, second := range el.outgoing[vertex] {
+			var departure = el.hierarchy.arcs[second]
+			if departure.to == arrival.from {
+				continue
+			}
+
+			var weight = arrival.weight + departure.weight
+			if el.round[departure.to] == el.rounds && el.weight[departure.to] <= weight {
+				continue
+			}
+
+			shortcuts = append(shortcuts, routeShortcutStt{
+				from:   arrival.from,
+				to:     departure.to,
+				weight: weight,
+				edge:   -1,
+				first:  first,
+				second: second,
+			})
+		}
+	}
+
+	return shortcuts
+}
+
+// priority is the edge difference of the vertex, the shortcuts added less the arcs removed by its contraction, plus the
+// neighbors already contracted, to spread the contraction over the network
+func (el *routeContractorStt) priority(vertex int) float64 {
+	var removed = len(el.incoming[vertex]) + len(el.outgoing[vertex])
+
+	return float64(len(el.shortcuts(vertex)) - removed + el.deleted[vertex])
+}
+
+// contract removes the vertex from the network, keeps its arcs in the hierarchy and adds the shortcuts between its
+// neighbors
+func (el *routeContractorStt) contract(vertex int) {
+	var shortcuts = el.shortcuts(vertex)
+
+	el.hierarchy.up[vertex] = el.outgoing[vertex]
+	el.hierarchy.down[vertex] = el.incoming[vertex]
+
+	for _, index := range el.outgoing[vertex] {
+		var to = el.hierarchy.arcs[index].to
+		el.incoming[to] = routeRemoveArc(el.incoming[to], index)
+		el.deleted[to] += 1
+	}
+
+	for _, index := range el.incoming[vertex] {
+		var from = el.hierarchy.arcs[index].from
+		el.outgoing[from] = routeRemoveArc(el.outgoing[from], index)
+		el.deleted[from] += 1
+	}
+
+	el.outgoing[vertex], el.incoming[vertex] = nil, nil
+
+	for _, shortcut := range shortcuts {
+		el.arc(shortcut)
+	}
+}
+
+// English: Prepares the graph for ContractionHierarchy(), which finds the same paths as Dijkstra() visiting a small
+// part of the graph. The preparation contracts the nodes one by one, from the one whose contraction adds the fewest
+// shortcuts between its neighbors less the arcs it removes, the edge difference, and takes much longer than a search:
+// it is done once, and ToFile() keeps the graph prepared.
+//
+// Build(), BuildWithProfile() and AddRestrictions() discard the preparation; with the turn restrictions, Contract()
+// must be called after AddRestrictions().
+//
+// Português: Prepara o grafo para ContractionHierarchy(), que acha os mesmos caminhos que Dijkstra() visitando uma
+// pequena parte do grafo. A preparação contrai os nodes um a um, a partir daquele cuja contração adiciona menos atalhos
+// entre os seus vizinhos menos os arcos que ela remove, a diferença de arestas, e leva muito mais tempo que uma busca:
+// ela é feita uma vez, e ToFile() guarda o grafo preparado.
+//
+// Build(), BuildWithProfile() e AddRestrictions() descartam a preparação; com as restrições de conversão, Contract()
+// deve ser chamado depois de AddRestrictions().
+func (el *RouteGraphStt) Contract() error {
+	if len(el.nodes) == 0 {
+		return fmt.Errorf("route: the graph must be built before the contraction")
+	}
+
+	var vertices = len(el.network.outgoing)
+	var contractor = routeContractorStt{
+		hierarchy: &routeHierarchyStt{up: make([][]int, vertices), down: make([][]int, vertices)},
+		outgoing:  make([][]int, vertices),
+		incoming:  make([][]int, vertices),
+		deleted:   make([]int, vertices),
+		weight:    make([]float64, vertices),
+		round:     make([]int, vertices),
+		target:    make([]int, vertices),
+	}
+
+	for vertex, arcs := range el.network.outgoing {
+		for _, arc := range arcs {
+			contractor.arc(routeShortcutStt{from: vertex, to: arc.vertex, weight: arc.weight, edge: arc.edge, first: -1, second: -1})
+		}
+	}
+
+	var queue = make(routeQueueStt, vertices)
+	for vertex := range queue {
+		queue[vertex] = routeQueueEntryStt{vertex: vertex, priority: contractor.priority(vertex)}
+	}
+	heap.Init(&queue)
+
+	// the priorities change as the neighbors are contracted, so the vertex of the least priority is measured again and
+	// goes back to the queue when it is no longer the least
+	for queue.Len() != 0 {
+		var entry = heap.Pop(&queue).(routeQueueEntryStt)
+		var priority = contractor.priority(entry.vertex)
+
+		if queue.Len() != 0 && priority > queue[0].priority {
+			heap.Push(&queue, routeQueueEntryStt{vertex: entry.vertex, priority: priority})
+			continue
+		}
+
+		contractor.contract(entry.vertex)
+	}
+
+	el.hierarchy = contractor.hierarchy
+
+	return nil
+}
+
+// unpack are the edges of the graph along the arcs of the hierarchy, with the shortcuts replaced by the arcs they are
+// made of
+func (el *routeHierarchyStt) unpack(arcs []int) []int {
+	var edges = make([]int, 0)
+	var stack = routeReverse(append([]int{}, arcs...))
+
+	for len(stack) != 0 {
+		var arc = el.arcs[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+
+		if arc.first != -1 {
+			stack = append(stack, arc.second, arc.first)
+		} else if arc.edge != -1 {
+			edges = append(edges, arc.edge)
+		}
+	}
+
+	return edges
+}
+
+// hierarchySearch searches from the source upwards in the hierarchy and from the target, backwards, also upwards,
+// until the least weight of each queue is not less than the best path found through a vertex reached by both
+func (el *RouteGraphStt) hierarchySearch(source, target int) ([]int, error) {
+	if el.hierarchy == nil {
+		return nil, fmt.Errorf("route: the graph must be prepared by Contract()")
+	}
+
+	if source == target {
+		return []int{}, nil
+	}
+
+	var hierarchy = el.hierarchy
+	var forward = newRouteSearch(el.network.departure + source)
+	var backward = newRouteSearch(el.network.arrival + target)
+	var best, meeting = math.Inf(1), -1
+
+	var meet = func(vertex int) {
+		weightForward, foundForward := forward.weight[vertex]
+		weightBackward, foundBackward := backward.weight[vertex]
+		if foundForward && foundBackward && weightForward+weightBackward < best {
+			best, meeting = weightForward+weightBackward, vertex
+		}
+	}
+
+	for {
+		var forwardOpen = forward.queue.Len() != 0 && forward.queue[0].priority < best
+		var backwardOpen = backward.queue.Len() != 0 && backward.queue[0].priority < best
+		if !forwardOpen && !backwardOpen {
+			break
+		}
+
+		if forwardOpen && (!backwardOpen || forward.queue[0].priority <= backward.queue[0].priority) {
+			var vertex = forward.next()
+			if vertex == -1 {
+				continue
+			}
+
+			for _, index := range hierarchy.up[vertex] {
+				var arc = &hierarchy.arcs[index]
+				forward.relax(vertex, routeArcStt{vertex: arc.to, weight: arc.weight, edge: index}, 0)
+				meet(arc.to)
+			}
+		} else {
+			var vertex = backward.next()
+			if vertex == -1 {
+				continue
+			}
+
+			for _, index := range hierarchy.down[vertex] {
+				var arc = &hierarchy.arcs[index]
+				backward.relax(vertex, routeArcStt{vertex: arc.from, weight: arc.weight, edge: index}, 0)
+				meet(arc.from)
+			}
+		}
+	}
+
+	if meeting == -1 {
+		return nil, fmt.Errorf("route: there is no path between the points")
+	}
+
+	return hierarchy.unpack(append(routeReverse(forward.edges(meeting)), backward.edges(meeting)...)), nil
+}
+
+// English: Same as Dijkstra(), on the graph prepared by Contract(), which visits a small part of the graph.
+//
+// Português: O mesmo que Dijkstra(), no grafo preparado por Contract(), que visita uma pequena parte do grafo.
+func (el *RouteGraphStt) ContractionHierarchy(from, to PointStt) (WayStt, error) {
+	source, target, err := el.endpoints(from, to)
+	if err != nil {
+		return WayStt{}, err
+	}
+
+	edges, err := el.hierarchySearch(source, target)
+	if err != nil {
+		return WayStt{}, err
+	}
+
+	return el.way(source, edges)
+}
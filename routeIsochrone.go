@@ -0,0 +1,222 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// routeReachedStt is an edge reached by the search, with the least weight at its start and the points of the edge on
+// the plane, with the length along the edge up to each one
+type routeReachedStt struct {
+	weight float64
+	points [][2]float64
+	length []float64
+}
+
+// reach is the bounded Dijkstra from the node, with the cost of each edge given by the function, which also runs on
+// the turn restrictions; returns the edges that start at a weight less than the limit
+func (el *RouteGraphStt) reach(source int, cost func(edge int) float64, limit float64) map[int]float64 {
+	var network = &el.network
+	var search = newRouteSearch(network.departure + source)
+	var edges = make(map[int]float64)
+
+	for vertex := search.next(); vertex != -1; vertex = search.next() {
+		var weight = search.weight[vertex]
+		if weight >= limit {
+			break
+		}
+
+		for _, arc := range network.outgoing[vertex] {
+			if arc.edge == -1 {
+				continue
+			}
+
+			if known, found := edges[arc.edge]; !found || weight < known {
+				edges[arc.edge] = weight
+			}
+
+			search.relax(vertex, routeArcStt{vertex: arc.vertex, weight: cost(arc.edge), edge: arc.edge}, 0)
+		}
+	}
+
+	return edges
+}
+
+// isoline is the region reached from the point within each band, made by the alpha shape of the points along the
+// edges reached, with the band in the data of the polygons under the key
+func (el *RouteGraphStt) isoline(from PointStt, bands []float64, cost func(edge int) float64, key string, resolution DistanceStt) (PolygonListStt, error) {
+	var alpha = resolution.GetMeters()
+	if !(alpha > 0) {
+		return PolygonListStt{}, fmt.Errorf("route: the resolution must be greater than zero")
+	}
+
+	if len(bands) == 0 {
+		return PolygonListStt{}, fmt.Errorf("route: there must be at least one band")
+	}
+
+	var sorted = append([]float64{}, bands...)
+	sort.Float64s(sorted)
+	if !(sorted[0] > 0) || math.IsInf(sorted[len(sorted)-1], 1) {
+		return PolygonListStt{}, fmt.Errorf("route: the bands must be greater than zero and finite")
+	}
+
+	source, err := el.nearestNode(from)
+	if err != nil {
+		return PolygonListStt{}, err
+	}
+
+	// the points are on the plane tangent to the Earth at the node of departure
+	var plane = LocalTangentPlaneStt{}
+	plane.Origin.SetLngLatDegrees(el.nodes[source].loc[0], el.nodes[source].loc[1])
+
+	var reached = make(map[int]*routeReachedStt)
+	for edge, weight := range el.reach(source, cost, sorted[len(sorted)-1]) {
+		var item = &routeReachedStt{weight: weight}
+		var step = 1
+		if el.edges[edge].last < el.edges[edge].first {
+			step = -1
+		}
+
+		var way = el.edges[edge].way
+		for index := el.edges[edge].first; index != el.edges[edge].last+step; index += step {
+			var x, y = plane.Forward(way.Loc[index][0], way.Loc[index][1])
+			var length = 0.0
+			if len(item.points) != 0 {
+				var last = item.points[len(item.points)-1]
+				length = item.length[len(item.length)-1] + math.Hypot(x-last[0], y-last[1])
+			}
+
+			item.points = append(item.points, [2]float64{x, y})
+			item.length = append(item.length, length)
+		}
+
+		reached[edge] = item
+	}
+
+	var list PolygonListStt
+	for _, band := range sorted {
+		var points = el.isolinePoints(reached, cost, band, alpha)
+		var outer, holes = alphaShape(points, alpha)
+
+		for k, ring := range outer {
+			var polygon PolygonStt
+			polygon.Data = map[string]string{key: strconv.FormatFloat(band, 'f', 1, 64)}
+			for _, point := range ring {
+				polygon.AddLngLatDegrees(plane.Inverse(point[0], point[1]))
+			}
+
+			for _, hole := range holes[k] {
+				var inner PolygonStt
+				for _, point := range hole {
+					inner.AddLngLatDegrees(plane.Inverse(point[0], point[1]))
+				}
+				polygon.Inner = append(polygon.Inner, inner)
+			}
+
+			if err := polygon.Init(); err != nil {
+				return PolygonListStt{}, err
+			}
+
+			list.AddPolygon(&polygon)
+		}
+	}
+
+	if len(list.List) != 0 {
+		list.Initialize()
+	}
+
+	return list, nil
+}
+
+// isolinePoints are the points along the edges reached within the band, no farther apart than the resolution, up to
+// the point where the band ends along the edge; the points repeated are removed
+func (el *RouteGraphStt) isolinePoints(reached map[int]*routeReachedStt, cost func(edge int) float64, band, resolution float64) [][2]float64 {
+	var points = make([][2]float64, 0)
+	var seen = make(map[[2]float64]bool)
+
+	var add = func(point [2]float64) {
+		// a tenth of a millimeter apart is the same point
+		var key = [2]float64{math.Round(point[0] * 1e4), math.Round(point[1] * 1e4)}
+		if !seen[key] {
+			seen[key] = true
+			points = append(points, point)
+		}
+	}
+
+	var edges = make([]int, 0, len(reached))
+	for edge := range reached {
+		edges = append(edges, edge)
+	}
+	sort.Ints(edges)
+
+	for _, edge := range edges {
+		var item = reached[edge]
+		if item.weight >= band {
+			continue
+		}
+
+		// the part of the edge within the band, along its length
+		var total = item.length[len(item.length)-1]
+		var within = total
+		if weight := cost(edge); weight > 0 {
+			within = math.Min(total, total*(band-item.weight)/weight)
+		}
+
+		add(item.points[0])
+		for k := 1; k != len(item.points) && item.length[k-1] < within; k += 1 {
+			var a, b = item.points[k-1], item.points[k]
+			var start, end = item.length[k-1], math.Min(item.length[k], within)
+			var segment = item.length[k] - item.length[k-1]
+			if segment == 0 {
+				continue
+			}
+
+			var steps = math.Ceil((end - start) / resolution)
+			for step := 1.0; step <= steps; step += 1 {
+				var fraction = (start + (end-start)*step/steps - item.length[k-1]) / segment
+				add([2]float64{a[0] + (b[0]-a[0])*fraction, a[1] + (b[1]-a[1])*fraction})
+			}
+		}
+	}
+
+	return points
+}
+
+// English: Regions reached from the point within each duration, in seconds, along the graph built by
+// BuildWithProfile(), with the turn restrictions. Each region is the alpha shape of the points along the streets
+// reached: the gaps between the streets narrower than about twice the resolution are filled, so a resolution near half
+// the size of the blocks of the city makes a region without holes between the streets, and a street alone, far from
+// the others, does not make a region.
+//
+// The polygons have the duration of the band in Data["duration"], from the shortest. A band can have more than one
+// polygon, when the streets reached are apart, or none, when too few streets are reached.
+//
+// Português: Regiões alcançadas a partir do ponto dentro de cada duração, em segundos, ao longo do grafo montado por
+// BuildWithProfile(), com as restrições de conversão. Cada região é a alpha shape dos pontos ao longo das ruas
+// alcançadas: os vãos entre as ruas mais estreitos que cerca de duas vezes a resolução são preenchidos, então uma
+// resolução perto da metade do tamanho das quadras da cidade faz uma região sem buracos entre as ruas, e uma rua
+// sozinha, longe das outras, não faz uma região.
+//
+// Os polígonos têm a duração da faixa em Data["duration"], a partir da mais curta. Uma faixa pode ter mais de um
+// polígono, quando as ruas alcançadas estão separadas, ou nenhum, quando poucas ruas são alcançadas.
+func (el *RouteGraphStt) Isochrone(from PointStt, durations []float64, resolution DistanceStt) (PolygonListStt, error) {
+	if !el.timed {
+		return PolygonListStt{}, fmt.Errorf("route: the graph must be built by BuildWithProfile() for the durations")
+	}
+
+	return el.isoline(from, durations, func(edge int) float64 { return el.edges[edge].duration }, "duration", resolution)
+}
+
+// English: Same as Isochrone(), within each distance along the graph, in Data["distance"] in meters.
+//
+// Português: O mesmo que Isochrone(), dentro de cada distância ao longo do grafo, em Data["distance"] em metros.
+func (el *RouteGraphStt) Isodistance(from PointStt, distances []DistanceStt, resolution DistanceStt) (PolygonListStt, error) {
+	var bands = make([]float64, len(distances))
+	for k := range distances {
+		bands[k] = distances[k].GetMeters()
+	}
+
+	return el.isoline(from, bands, func(edge int) float64 { return el.edges[edge].distance }, "distance", resolution)
+}
@@ -0,0 +1,399 @@
+package iotmaker_geo_osm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// English: Routing profile, the rules that turn the tags of a way into the speed, the cost and the directions allowed
+// for a vehicle. The profile can be declared in Go or read from JSON by FromJSon(), with the names of the fields in
+// the tags json.
+//
+// Português: Perfil de roteamento, as regras que transformam as tags de um way na velocidade, no custo e nos sentidos
+// permitidos para um veículo. O perfil pode ser declarado em Go ou lido de JSON por FromJSon(), com os nomes dos
+// campos nas tags json.
+type RouteProfileStt struct {
+	// English: name used by the registry, as "car"
+	//
+	// Português: nome usado pelo registro, como "car"
+	Name string `json:"name"`
+
+	// English: speed, in km/h, for each value of the tag highway; the ways of the other values are not accessible
+	//
+	// Português: velocidade, em km/h, para cada valor da tag highway; os ways dos outros valores não são acessíveis
+	HighwaySpeed map[string]float64 `json:"highwaySpeed"`
+
+	// English: factor of the cost of the time on each value of the tag highway, one when missing. Greater than one
+	// avoids the ways, less than one prefers them.
+	//
+	// Português: fator do custo do tempo em cada valor da tag highway, um quando ausente. Maior que um evita os ways,
+	// menor que um os prefere.
+	HighwayCost map[string]float64 `json:"highwayCost"`
+
+	// English: factor of the speed for each value of the tag surface, one when missing; zero makes the way not
+	// accessible
+	//
+	// Português: fator da velocidade para cada valor da tag surface, um quando ausente; zero torna o way não acessível
+	SurfaceFactor map[string]float64 `json:"surfaceFactor"`
+
+	// English: tags of access, from the most specific, as motor_vehicle, to the most general, access. The first tag of
+	// the way found in the list decides the access.
+	//
+	// Português: tags de acesso, da mais específica, como motor_vehicle, até a mais geral, access. A primeira tag do way
+	// achada na lista decide o acesso.
+	AccessTags []string `json:"accessTags"`
+
+	// English: values of the tags of access that deny the access, as "no" and "private"
+	//
+	// Português: valores das tags de acesso que negam o acesso, como "no" e "private"
+	AccessDenied []string `json:"accessDenied"`
+
+	// English: values of the tags of access that grant the access, as "yes" and "designated", on the values of the tag
+	// highway missing in HighwaySpeed, at AccessSpeed
+	//
+	// Português: valores das tags de acesso que permitem o acesso, como "yes" e "designated", nos valores da tag
+	// highway ausentes em HighwaySpeed, na AccessSpeed
+	AccessAllowed []string `json:"accessAllowed"`
+
+	// English: speed, in km/h, on the ways whose access is granted by AccessAllowed; zero to grant no access
+	//
+	// Português: velocidade, em km/h, nos ways cujo acesso é permitido por AccessAllowed; zero para não permitir acesso
+	AccessSpeed float64 `json:"accessSpeed"`
+
+	// English: tags of one way, from the most specific, as oneway:bicycle, to oneway. Empty for the profiles that go in
+	// both directions of every way.
+	//
+	// Português: tags de mão única, da mais específica, como oneway:bicycle, até oneway. Vazia para os perfis que andam
+	// nos dois sentidos de todos os ways.
+	OneWayTags []string `json:"oneWayTags"`
+
+	// English: the speed of the way is limited by the tag maxspeed
+	//
+	// Português: a velocidade do way é limitada pela tag maxspeed
+	UseMaxSpeed bool `json:"useMaxSpeed"`
+
+	// English: maximum speed of the vehicle, in km/h, zero for no limit
+	//
+	// Português: velocidade máxima do veículo, em km/h, zero para sem limite
+	MaxSpeed float64 `json:"maxSpeed"`
+
+	// English: weight of the vehicle, in tonnes, compared to the tag maxweight; zero to ignore the tag
+	//
+	// Português: peso do veículo, em toneladas, comparado com a tag maxweight; zero para ignorar a tag
+	Weight float64 `json:"weight"`
+
+	// English: height of the vehicle, in meters, compared to the tag maxheight; zero to ignore the tag
+	//
+	// Português: altura do veículo, em metros, comparada com a tag maxheight; zero para ignorar a tag
+	Height float64 `json:"height"`
+}
+
+var (
+	ROUTE_PROFILE_CAR = RouteProfileStt{
+		Name: "car",
+		HighwaySpeed: map[string]float64{
+			"motorway": 110, "motorway_link": 60, "trunk": 90, "trunk_link": 50, "primary": 70, "primary_link": 40,
+			"secondary": 60, "secondary_link": 40, "tertiary": 50, "tertiary_link": 30, "unclassified": 40,
+			"residential": 30, "living_street": 10, "service": 20, "road": 30,
+		},
+		SurfaceFactor: map[string]float64{
+			"cobblestone": 0.7, "sett": 0.8, "compacted": 0.8, "unpaved": 0.6, "gravel": 0.6, "fine_gravel": 0.6,
+			"dirt": 0.5, "earth": 0.5, "ground": 0.5, "grass": 0.3, "mud": 0.2, "sand": 0.2,
+		},
+		AccessTags:    []string{"motorcar", "motor_vehicle", "vehicle", "access"},
+		AccessDenied:  []string{"no", "private", "agricultural", "forestry"},
+		AccessAllowed: []string{"yes", "designated", "permissive"},
+		AccessSpeed:   20,
+		OneWayTags:    []string{"oneway"},
+		UseMaxSpeed:   true,
+	}
+
+	// English: delivery truck of 7.5 tonnes and 3.5 meters, which avoids the small streets
+	//
+	// Português: caminhão de entrega de 7,5 toneladas e 3,5 metros, que evita as ruas pequenas
+	ROUTE_PROFILE_TRUCK = RouteProfileStt{
+		Name: "truck",
+		HighwaySpeed: map[string]float64{
+			"motorway": 80, "motorway_link": 50, "trunk": 80, "trunk_link": 45, "primary": 65, "primary_link": 40,
+			"secondary": 55, "secondary_link": 35, "tertiary": 45, "tertiary_link": 30, "unclassified": 35,
+			"residential": 25, "service": 15, "road": 25,
+		},
+		HighwayCost: map[string]float64{"unclassified": 1.5, "residential": 2, "service": 2},
+		SurfaceFactor: map[string]float64{
+			"cobblestone": 0.6, "sett": 0.7, "compacted": 0.7, "unpaved": 0.5, "gravel": 0.5, "fine_gravel": 0.5,
+			"dirt": 0.3, "earth": 0.3, "ground": 0.3, "grass": 0, "mud": 0, "sand": 0,
+		},
+		AccessTags:    []string{"hgv", "goods", "motor_vehicle", "vehicle", "access"},
+		AccessDenied:  []string{"no", "private", "agricultural", "forestry"},
+		AccessAllowed: []string{"yes", "designated", "permissive"},
+		AccessSpeed:   15,
+		OneWayTags:    []string{"oneway"},
+		UseMaxSpeed:   true,
+		MaxSpeed:      80,
+		Weight:        7.5,
+		Height:        3.5,
+	}
+
+	ROUTE_PROFILE_BIKE = RouteProfileStt{
+		Name: "bike",
+		HighwaySpeed: map[string]float64{
+			"cycleway": 18, "path": 12, "track": 12, "living_street": 10, "residential": 16, "service": 14,
+			"unclassified": 16, "tertiary": 16, "tertiary_link": 16, "secondary": 16, "secondary_link": 16,
+			"primary": 14, "primary_link": 14, "road": 14,
+		},
+		HighwayCost: map[string]float64{"primary": 1.5, "primary_link": 1.5, "secondary": 1.2, "secondary_link": 1.2},
+		SurfaceFactor: map[string]float64{
+			"cobblestone": 0.6, "sett": 0.7, "compacted": 0.9, "unpaved": 0.7, "gravel": 0.7, "fine_gravel": 0.8,
+			"dirt": 0.6, "earth": 0.6, "ground": 0.6, "grass": 0.4, "mud": 0.3, "sand": 0.3,
+		},
+		AccessTags:    []string{"bicycle", "vehicle", "access"},
+		AccessDenied:  []string{"no", "private", "dismount"},
+		AccessAllowed: []string{"yes", "designated", "permissive"},
+		AccessSpeed:   12,
+		OneWayTags:    []string{"oneway:bicycle", "oneway"},
+	}
+
+	ROUTE_PROFILE_FOOT = RouteProfileStt{
+		Name: "foot",
+		HighwaySpeed: map[string]float64{
+			"footway": 5, "pedestrian": 5, "path": 4.5, "steps": 2, "track": 4.5, "cycleway": 5, "living_street": 5,
+			"residential": 5, "service": 5, "unclassified": 5, "tertiary": 5, "tertiary_link": 5, "secondary": 5,
+			"secondary_link": 5, "primary": 5, "primary_link": 5, "road": 5,
+		},
+		AccessTags:    []string{"foot", "access"},
+		AccessDenied:  []string{"no", "private"},
+		AccessAllowed: []string{"yes", "designated", "permissive"},
+		AccessSpeed:   4.5,
+	}
+)
+
+var routeProfileRegistry = struct {
+	sync.RWMutex
+	list map[string]RouteProfileStt
+}{
+	list: map[string]RouteProfileStt{
+		ROUTE_PROFILE_CAR.Name:   ROUTE_PROFILE_CAR,
+		ROUTE_PROFILE_TRUCK.Name: ROUTE_PROFILE_TRUCK,
+		ROUTE_PROFILE_BIKE.Name:  ROUTE_PROFILE_BIKE,
+		ROUTE_PROFILE_FOOT.Name:  ROUTE_PROFILE_FOOT,
+	},
+}
+
+// English: Adds the profile to the registry, replacing another with the same name.
+//
+// Português: Adiciona o perfil ao registro, substituindo outro com o mesmo nome.
+func RegisterRouteProfile(profile RouteProfileStt) error {
+	if err := profile.check(); err != nil {
+		return err
+	}
+
+	routeProfileRegistry.Lock()
+	defer routeProfileRegistry.Unlock()
+
+	routeProfileRegistry.list[profile.Name] = profile
+
+	return nil
+}
+
+// English: Returns the profile registered with the name.
+//
+// Português: Devolve o perfil registrado com o nome.
+func FindRouteProfile(name string) (RouteProfileStt, error) {
+	routeProfileRegistry.RLock()
+	defer routeProfileRegistry.RUnlock()
+
+	profile, found := routeProfileRegistry.list[name]
+	if !found {
+		return profile, fmt.Errorf("route profile '%v' not found", name)
+	}
+
+	return profile, nil
+}
+
+func (el RouteProfileStt) check() error {
+	if el.Name == "" {
+		return fmt.Errorf("route profile: the name must be set")
+	}
+
+	var accessible = false
+	for highway, speed := range el.HighwaySpeed {
+		if speed < 0 || math.IsNaN(speed) {
+			return fmt.Errorf("route profile '%v': the speed of highway=%v must not be negative", el.Name, highway)
+		}
+		accessible = accessible || speed > 0
+	}
+
+	if !accessible {
+		return fmt.Errorf("route profile '%v': at least one value of highway must have a speed", el.Name)
+	}
+
+	if el.AccessSpeed < 0 || math.IsNaN(el.AccessSpeed) {
+		return fmt.Errorf("route profile '%v': the access speed must not be negative", el.Name)
+	}
+
+	for highway, cost := range el.HighwayCost {
+		if !(cost > 0) {
+			return fmt.Errorf("route profile '%v': the cost of highway=%v must be positive", el.Name, highway)
+		}
+	}
+
+	return nil
+}
+
+// English: Writes the profile as JSON, read back by FromJSon().
+//
+// Português: Escreve o perfil como JSON, lido de volta por FromJSon().
+func (el *RouteProfileStt) ToJSon() ([]byte, error) {
+	return json.Marshal(el)
+}
+
+// English: Reads the profile from JSON and checks it.
+//
+// Português: Lê o perfil de JSON e o verifica.
+func (el *RouteProfileStt) FromJSon(in []byte) error {
+	var profile RouteProfileStt
+	if err := json.Unmarshal(in, &profile); err != nil {
+		return fmt.Errorf("route profile: %v", err)
+	}
+
+	if err := profile.check(); err != nil {
+		return err
+	}
+
+	*el = profile
+
+	return nil
+}
+
+// English: Speed on the way, in km/h, and the directions allowed, along the points of the way and against them. The
+// speed is zero when the way is not accessible.
+//
+// The first of the AccessTags found on the way decides the access: a value of AccessDenied denies it, and a value of
+// AccessAllowed grants it at AccessSpeed when the value of highway is not in HighwaySpeed, as bicycle=designated on a
+// footway.
+//
+// Português: Velocidade no way, em km/h, e os sentidos permitidos, ao longo dos pontos do way e contra eles. A
+// velocidade é zero quando o way não é acessível.
+//
+// A primeira das AccessTags achada no way decide o acesso: um valor de AccessDenied o nega, e um valor de
+// AccessAllowed o permite na AccessSpeed quando o valor de highway não está em HighwaySpeed, como bicycle=designated
+// em uma footway.
+func (el RouteProfileStt) Speed(tag map[string]string) (speed float64, forward, backward bool) {
+	var highway = tag["highway"]
+	var listed bool
+	speed, listed = el.HighwaySpeed[highway]
+
+	for _, key := range el.AccessTags {
+		if value, found := tag[key]; found {
+			for _, denied := range el.AccessDenied {
+				if value == denied {
+					return 0, false, false
+				}
+			}
+
+			for _, allowed := range el.AccessAllowed {
+				if value == allowed && !listed && highway != "" {
+					speed = el.AccessSpeed
+				}
+			}
+			break
+		}
+	}
+
+	if !(speed > 0) {
+		return 0, false, false
+	}
+
+	if el.Weight > 0 {
+		if limit, ok := routeParseQuantity(tag["maxweight"], routeWeightUnits); ok && limit < el.Weight {
+			return 0, false, false
+		}
+	}
+
+	if el.Height > 0 {
+		if limit, ok := routeParseQuantity(tag["maxheight"], routeHeightUnits); ok && limit < el.Height {
+			return 0, false, false
+		}
+	}
+
+	if factor, found := el.SurfaceFactor[tag["surface"]]; found {
+		speed *= factor
+	}
+
+	if el.UseMaxSpeed {
+		if limit, ok := routeParseQuantity(tag["maxspeed"], routeSpeedUnits); ok && limit > 0 {
+			speed = math.Min(speed, limit)
+		}
+	}
+
+	if el.MaxSpeed > 0 {
+		speed = math.Min(speed, el.MaxSpeed)
+	}
+
+	if !(speed > 0) {
+		return 0, false, false
+	}
+
+	if len(el.OneWayTags) == 0 {
+		return speed, true, true
+	}
+
+	forward, backward = routeWayDirection(tag, el.OneWayTags)
+	if !forward && !backward {
+		return 0, false, false
+	}
+
+	return speed, forward, backward
+}
+
+// cost is the factor of the time on the way
+func (el RouteProfileStt) cost(tag map[string]string) float64 {
+	if cost, found := el.HighwayCost[tag["highway"]]; found {
+		return cost
+	}
+
+	return 1
+}
+
+// units of the tags maxspeed, to km/h, maxweight, to tonnes, and maxheight, to meters
+var (
+	routeSpeedUnits  = map[string]float64{"": 1, "km/h": 1, "kmh": 1, "kph": 1, "mph": 1.609344, "knots": 1.852}
+	routeWeightUnits = map[string]float64{"": 1, "t": 1, "kg": 0.001, "lbs": 0.00045359237}
+	routeHeightUnits = map[string]float64{"": 1, "m": 1, "ft": 0.3048}
+)
+
+// routeParseQuantity reads the number and the unit of the first value of the tag, as "30 mph", and the height in
+// feet and inches, as 12'6"
+func routeParseQuantity(value string, units map[string]float64) (float64, bool) {
+	value = strings.TrimSpace(strings.SplitN(value, ";", 2)[0])
+
+	if strings.Contains(value, "'") {
+		var feet, inches float64
+		if count, _ := fmt.Sscanf(value, "%g'%g\"", &feet, &inches); count == 0 {
+			return 0, false
+		}
+		return feet*0.3048 + inches*0.0254, true
+	}
+
+	var end = strings.IndexFunc(value, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	if end == -1 {
+		end = len(value)
+	}
+
+	number, err := strconv.ParseFloat(value[:end], 64)
+	if err != nil {
+		return 0, false
+	}
+
+	factor, found := units[strings.TrimSpace(value[end:])]
+	if !found {
+		return 0, false
+	}
+
+	return number * factor, true
+}
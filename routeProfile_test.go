@@ -0,0 +1,52 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+func ExampleRouteProfileStt_Speed() {
+	var tags = []map[string]string{
+		{"highway": "residential", "maxspeed": "15 mph"},
+		{"highway": "primary", "maxheight": "3.2"},
+		{"highway": "secondary", "oneway": "yes", "oneway:bicycle": "no"},
+		{"highway": "footway"},
+		{"highway": "service", "access": "private"},
+		{"highway": "unclassified", "surface": "gravel", "maxweight": "3.5 t"},
+		{"highway": "footway", "bicycle": "designated"},
+		{"highway": "track", "motor_vehicle": "yes"},
+	}
+
+	for _, name := range []string{"car", "truck", "bike", "foot"} {
+		profile, _ := FindRouteProfile(name)
+
+		fmt.Printf("%v:", name)
+		for _, tag := range tags {
+			speed, forward, backward := profile.Speed(tag)
+			fmt.Printf(" %.1f %v %v,", speed, forward, backward)
+		}
+		fmt.Println()
+	}
+
+	// a profile declared in JSON, for a scooter that must not go on the primary roads
+	var scooter RouteProfileStt
+	err := scooter.FromJSon([]byte(`{
+		"name": "scooter",
+		"highwaySpeed": {"residential": 25, "secondary": 25, "cycleway": 20},
+		"accessTags": ["moped", "motor_vehicle", "vehicle", "access"],
+		"accessDenied": ["no", "private"],
+		"oneWayTags": ["oneway"],
+		"useMaxSpeed": true
+	}`))
+	fmt.Printf("scooter: %v\n", err)
+
+	speed, forward, backward := scooter.Speed(tags[2])
+	fmt.Printf("scooter: %.1f %v %v\n", speed, forward, backward)
+
+	// Output:
+	// car: 24.1 true true, 70.0 true true, 60.0 true false, 0.0 false false, 0.0 false false, 24.0 true true, 0.0 false false, 20.0 true true,
+	// truck: 24.1 true true, 0.0 false false, 55.0 true false, 0.0 false false, 0.0 false false, 0.0 false false, 0.0 false false, 15.0 true true,
+	// bike: 16.0 true true, 14.0 true true, 16.0 true true, 0.0 false false, 0.0 false false, 11.2 true true, 12.0 true true, 12.0 true true,
+	// foot: 5.0 true true, 5.0 true true, 5.0 true true, 5.0 true true, 0.0 false false, 5.0 true true, 5.0 true true, 4.5 true true,
+	// scooter: <nil>
+	// scooter: 25.0 true false
+}
@@ -0,0 +1,375 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// routeRestrictionStt forbids the turn from one of the edges from to one of the edges to, after going along the edges
+// via in this order, or, when only is true, forbids all the other turns
+type routeRestrictionStt struct {
+	only bool
+	from map[int]bool
+	via  []int
+	to   map[int]bool
+}
+
+// forbids tells if the turn to the edge is not allowed at the end of the restriction
+func (el *routeRestrictionStt) forbids(edge int) bool {
+	return el.to[edge] != el.only
+}
+
+// English: Adds the turn restrictions of the relations with type=restriction to the graph, built before by Build() or
+// BuildWithProfile(). The searches go, from then on, from edge to edge of the graph, to honour them.
+//
+// The members from, via and to are the ways and the node of the restriction, and the ways must have IdNode. The via
+// can be a node or ways, as in the restrictions that forbid a U-turn through a short way between two roads. The
+// restrictions no_* forbid the turn, the restrictions only_* forbid all the other turns.
+//
+// The tag restriction applies to the vehicles, so to Build() and to the profiles with the tag vehicle in AccessTags;
+// the tag restriction:<tag>, as restriction:hgv, applies to the profiles with the tag in AccessTags. The restrictions
+// with one of the AccessTags of the profile in the tag except, as except=bicycle, do not apply. Returns the number of
+// restrictions added; the relations that are not restrictions to the graph are ignored.
+//
+// Português: Adiciona as restrições de conversão das relações com type=restriction ao grafo, montado antes por Build()
+// ou BuildWithProfile(). As buscas vão, a partir de então, de aresta em aresta do grafo, para respeitá-las.
+//
+// Os membros from, via e to são os ways e o node da restrição, e os ways devem ter IdNode. O via pode ser um node ou
+// ways, como nas restrições que proíbem o retorno por um way curto entre duas pistas. As restrições no_* proíbem a
+// conversão, as restrições only_* proíbem todas as outras conversões.
+//
+// A tag restriction se aplica aos veículos, então ao Build() e aos perfis com a tag vehicle em AccessTags; a tag
+// restriction:<tag>, como restriction:hgv, se aplica aos perfis com a tag em AccessTags. As restrições com uma das
+// AccessTags do perfil na tag except, como except=bicycle, não se aplicam. Devolve a quantidade de restrições
+// adicionadas; as relações que não são restrições para o grafo são ignoradas.
+func (el *RouteGraphStt) AddRestrictions(relations []*RelationStt) (int, error) {
+	if len(el.nodes) == 0 {
+		return 0, fmt.Errorf("route: the graph must be built before the restrictions")
+	}
+
+	var wayEdges = make(map[int64][]int)
+	for k, edge := range el.edges {
+		wayEdges[edge.way.Id] = append(wayEdges[edge.way.Id], k)
+	}
+
+	var nodes = make(map[int64]int)
+	for k, node := range el.nodes {
+		if node.id != 0 {
+			nodes[node.id] = k
+		}
+	}
+
+	var added = 0
+	for _, relation := range relations {
+		if relation == nil || !el.restrictionApplies(relation.Tag) {
+			continue
+		}
+
+		restriction, ok := el.restriction(relation, wayEdges, nodes)
+		if ok {
+			el.restrictions = append(el.restrictions, restriction)
+			added += 1
+		}
+	}
+
+	el.network = newRouteTurnNetwork(el)
+	el.hierarchy = nil
+
+	return added, nil
+}
+
+// restrictionValue is the value of the tag restriction for the vehicles of the graph
+func (el *RouteGraphStt) restrictionValue(tag map[string]string) string {
+	for _, vehicle := range el.vehicles {
+		if value := tag["restriction:"+vehicle]; value != "" {
+			return value
+		}
+	}
+
+	if el.vehicles == nil {
+		return tag["restriction"]
+	}
+
+	for _, vehicle := range el.vehicles {
+		if vehicle == "vehicle" {
+			return tag["restriction"]
+		}
+	}
+
+	return ""
+}
+
+// restrictionApplies tells if the relation is a restriction for the vehicles of the graph
+func (el *RouteGraphStt) restrictionApplies(tag map[string]string) bool {
+	if tag["type"] != "restriction" {
+		return false
+	}
+
+	var value = el.restrictionValue(tag)
+	if !strings.HasPrefix(value, "no_") && !strings.HasPrefix(value, "only_") {
+		return false
+	}
+
+	for _, except := range strings.Split(tag["except"], ";") {
+		for _, vehicle := range el.vehicles {
+			if strings.TrimSpace(except) == vehicle {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// restriction turns the members of the relation into edges of the graph
+func (el *RouteGraphStt) restriction(relation *RelationStt, wayEdges map[int64][]int, nodes map[int64]int) (routeRestrictionStt, bool) {
+	var restriction = routeRestrictionStt{
+		only: strings.HasPrefix(el.restrictionValue(relation.Tag), "only_"),
+		from: make(map[int]bool),
+		to:   make(map[int]bool),
+	}
+
+	var from, to, viaEdges = make([]int, 0), make([]int, 0), make(map[int]bool)
+	var viaNode = -1
+
+	for _, member := range relation.Members {
+		switch {
+		case member.Role == "from" && member.Type == "way":
+			from = append(from, wayEdges[member.Ref]...)
+
+		case member.Role == "to" && member.Type == "way":
+			to = append(to, wayEdges[member.Ref]...)
+
+		case member.Role == "via" && member.Type == "node":
+			node, found := nodes[member.Ref]
+			if !found {
+				return restriction, false
+			}
+			viaNode = node
+
+		case member.Role == "via" && member.Type == "way":
+			for _, edge := range wayEdges[member.Ref] {
+				viaEdges[edge] = true
+			}
+		}
+	}
+
+	if viaNode != -1 {
+		for _, edge := range from {
+			if el.edges[edge].to == viaNode {
+				restriction.from[edge] = true
+			}
+		}
+
+		for _, edge := range to {
+			if el.edges[edge].from == viaNode {
+				restriction.to[edge] = true
+			}
+		}
+
+		return restriction, len(restriction.from) != 0 && len(restriction.to) != 0
+	}
+
+	if len(viaEdges) == 0 {
+		return restriction, false
+	}
+
+	// the edges via go from the end of an edge from to the start of an edge to, found by a search along the ways via
+	var ends = make(map[int]bool)
+	for _, edge := range to {
+		ends[el.edges[edge].from] = true
+	}
+
+	for _, edge := range from {
+		var start = el.edges[edge].to
+		var via = el.viaEdges(start, viaEdges, ends)
+		if via == nil {
+			continue
+		}
+
+		var end = el.edges[via[len(via)-1]].to
+		for _, fromEdge := range from {
+			if el.edges[fromEdge].to == start {
+				restriction.from[fromEdge] = true
+			}
+		}
+
+		for _, toEdge := range to {
+			if el.edges[toEdge].from == end {
+				restriction.to[toEdge] = true
+			}
+		}
+
+		restriction.via = via
+
+		return restriction, true
+	}
+
+	return restriction, false
+}
+
+// viaEdges is the shortest sequence of the edges allowed from the node to one of the ends, or nil
+func (el *RouteGraphStt) viaEdges(start int, allowed map[int]bool, ends map[int]bool) []int {
+	var parent = map[int]int{start: -1}
+	var queue = []int{start}
+
+	for len(queue) != 0 {
+		var node = queue[0]
+		queue = queue[1:]
+
+		if ends[node] && node != start {
+			var edges = make([]int, 0)
+			for edge := parent[node]; edge != -1; edge = parent[el.edges[edge].from] {
+				edges = append(edges, edge)
+			}
+
+			return routeReverse(edges)
+		}
+
+		for _, edge := range el.outgoing[node] {
+			var next = el.edges[edge].to
+			if _, seen := parent[next]; allowed[edge] && !seen {
+				parent[next] = edge
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return nil
+}
+
+// routeTurnStateStt is a vertex of the network of the turns: the edge of the graph and the restrictions with ways via
+// that the path follows, with the position of the edge in their edges via
+type routeTurnStateStt struct {
+	edge   int
+	active [][2]int
+}
+
+// key identifies the state by the edge and the restrictions followed
+func (el *routeTurnStateStt) key() string {
+	var key = strconv.Itoa(el.edge)
+	for _, active := range el.active {
+		key += ":" + strconv.Itoa(active[0]) + "," + strconv.Itoa(active[1])
+	}
+
+	return key
+}
+
+// newRouteTurnNetwork is the network of the turns from edge to edge. Each edge of the graph is a vertex, and the edges
+// via of the restrictions have one more vertex for each set of restrictions the path can follow on them, to remember
+// it. The vertices of departure only leave a node and the ones of arrival only arrive, so that no path goes through
+// them.
+func newRouteTurnNetwork(graph *RouteGraphStt) routeNetworkStt {
+	var nodes, edges = len(graph.nodes), len(graph.edges)
+
+	var byFromNode = make(map[int][]int)
+	var byFromWay = make(map[int][]int)
+	for r := range graph.restrictions {
+		for edge := range graph.restrictions[r].from {
+			if len(graph.restrictions[r].via) == 0 {
+				byFromNode[edge] = append(byFromNode[edge], r)
+			} else {
+				byFromWay[edge] = append(byFromWay[edge], r)
+			}
+		}
+	}
+
+	var states = make([]routeTurnStateStt, 0, edges)
+	var vertices = make(map[string]int)
+	for k := 0; k != edges; k += 1 {
+		states = append(states, routeTurnStateStt{edge: k})
+	}
+
+	var vertex = func(state routeTurnStateStt) int {
+		if len(state.active) == 0 {
+			return 2*nodes + state.edge
+		}
+
+		var key = state.key()
+		if index, found := vertices[key]; found {
+			return index
+		}
+
+		vertices[key] = 2*nodes + len(states)
+		states = append(states, state)
+		return vertices[key]
+	}
+
+	type arcStt struct {
+		from, to, edge int
+	}
+
+	// the states are visited as they are found, the path enters a restriction with ways via from the edge from to the
+	// first edge via, follows it along the next edges via and, at the last one, the restriction decides the turn
+	var arcs = make([]arcStt, 0)
+	for k := 0; k != len(states); k += 1 {
+		var state = states[k]
+		var node = graph.edges[state.edge].to
+
+		for _, next := range graph.outgoing[node] {
+			var forbidden = false
+			for _, r := range byFromNode[state.edge] {
+				forbidden = forbidden || graph.restrictions[r].forbids(next)
+			}
+
+			var nextState = routeTurnStateStt{edge: next}
+			for _, active := range state.active {
+				var restriction = &graph.restrictions[active[0]]
+				if active[1]+1 == len(restriction.via) {
+					forbidden = forbidden || restriction.forbids(next)
+				} else if restriction.via[active[1]+1] == next {
+					nextState.active = append(nextState.active, [2]int{active[0], active[1] + 1})
+				}
+			}
+
+			for _, r := range byFromWay[state.edge] {
+				if graph.restrictions[r].via[0] == next {
+					nextState.active = append(nextState.active, [2]int{r, 0})
+				}
+			}
+
+			if !forbidden {
+				sort.Slice(nextState.active, func(i, j int) bool {
+					var a, b = nextState.active[i], nextState.active[j]
+					return a[0] < b[0] || a[0] == b[0] && a[1] < b[1]
+				})
+				arcs = append(arcs, arcStt{from: 2*nodes + k, to: vertex(nextState), edge: next})
+			}
+		}
+	}
+
+	var network = routeNetworkStt{
+		outgoing:  make([][]routeArcStt, 2*nodes+len(states)),
+		incoming:  make([][]routeArcStt, 2*nodes+len(states)),
+		node:      make([]int, 2*nodes+len(states)),
+		departure: 0,
+		arrival:   nodes,
+	}
+
+	var addArc = func(from, to, edge int, weight float64) {
+		network.outgoing[from] = append(network.outgoing[from], routeArcStt{vertex: to, weight: weight, edge: edge})
+		network.incoming[to] = append(network.incoming[to], routeArcStt{vertex: from, weight: weight, edge: edge})
+	}
+
+	for node := 0; node != nodes; node += 1 {
+		network.node[node], network.node[nodes+node] = node, node
+
+		for _, edge := range graph.outgoing[node] {
+			addArc(node, 2*nodes+edge, edge, graph.edges[edge].weight)
+		}
+	}
+
+	for _, arc := range arcs {
+		addArc(arc.from, arc.to, arc.edge, graph.edges[arc.edge].weight)
+	}
+
+	for k, state := range states {
+		var node = graph.edges[state.edge].to
+		network.node[2*nodes+k] = node
+		addArc(2*nodes+k, nodes+node, -1, 0)
+	}
+
+	return network
+}
@@ -0,0 +1,371 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// English: Identifier of a cell of the cube of S2: the six faces of a cube projected on the sphere, each divided as a
+// quadtree numbered along a Hilbert curve. The cells of every level, from 0, the face, to 30, about one centimeter,
+// are numbered so that all the descendants of a cell are between RangeMin() and RangeMax(), allowing the points and
+// the coverings of the polygons to be joined by integer ranges in any key-value store. The numbers are the same of
+// the S2 library.
+//
+// Português: Identificador de uma célula do cubo do S2: as seis faces de um cubo projetadas na esfera, cada uma
+// dividida como uma quadtree numerada ao longo de uma curva de Hilbert. As células de todos os níveis, de 0, a face, a
+// 30, cerca de um centímetro, são numeradas de modo que todos os descendentes de uma célula estão entre RangeMin() e
+// RangeMax(), permitindo que os pontos e as coberturas dos polígonos sejam relacionados por intervalos de inteiros em
+// qualquer banco chave-valor. Os números são os mesmos da biblioteca S2.
+type S2CellId uint64
+
+const (
+	S2_MAX_LEVEL = 30
+
+	s2FaceBits   = 3
+	s2PosBits    = 2*S2_MAX_LEVEL + 1
+	s2MaxSize    = 1 << S2_MAX_LEVEL
+	s2LookupBits = 4
+	s2SwapMask   = 1
+	s2InvertMask = 2
+)
+
+var (
+	// position of each quadrant on the Hilbert curve, by orientation, and the opposite
+	s2IJToPos = [4][4]int{{0, 1, 3, 2}, {0, 3, 1, 2}, {2, 3, 1, 0}, {2, 1, 3, 0}}
+	s2PosToIJ = [4][4]int{{0, 1, 3, 2}, {0, 2, 3, 1}, {3, 2, 0, 1}, {3, 1, 0, 2}}
+
+	// change of orientation of the curve in each position
+	s2PosToOrientation = [4]int{s2SwapMask, 0, 0, s2InvertMask | s2SwapMask}
+
+	// tables that convert four levels of the quadtree at a time
+	s2LookupPos [1 << (2*s2LookupBits + 2)]int
+	s2LookupIJ  [1 << (2*s2LookupBits + 2)]int
+)
+
+func init() {
+	s2InitLookupCell(0, 0, 0, 0, 0, 0)
+	s2InitLookupCell(0, 0, 0, s2SwapMask, 0, s2SwapMask)
+	s2InitLookupCell(0, 0, 0, s2InvertMask, 0, s2InvertMask)
+	s2InitLookupCell(0, 0, 0, s2SwapMask|s2InvertMask, 0, s2SwapMask|s2InvertMask)
+}
+
+func s2InitLookupCell(level, i, j, originalOrientation, pos, orientation int) {
+	if level == s2LookupBits {
+		var ij = (i << s2LookupBits) + j
+		s2LookupPos[(ij<<2)+originalOrientation] = (pos << 2) + orientation
+		s2LookupIJ[(pos<<2)+originalOrientation] = (ij << 2) + orientation
+		return
+	}
+
+	level += 1
+	i <<= 1
+	j <<= 1
+	pos <<= 2
+
+	var r = s2PosToIJ[orientation]
+	for k := 0; k != 4; k += 1 {
+		s2InitLookupCell(level, i+(r[k]>>1), j+(r[k]&1), originalOrientation, pos+k, orientation^s2PosToOrientation[k])
+	}
+}
+
+// s2FaceUV projects the unit vector on the face of the cube that contains it.
+func s2FaceUV(xyz [3]float64) (int, float64, float64) {
+	var face = 0
+	if math.Abs(xyz[1]) > math.Abs(xyz[face]) {
+		face = 1
+	}
+	if math.Abs(xyz[2]) > math.Abs(xyz[face]) {
+		face = 2
+	}
+	if xyz[face] < 0 {
+		face += 3
+	}
+
+	var x, y, z = xyz[0], xyz[1], xyz[2]
+	switch face {
+	case 0:
+		return face, y / x, z / x
+	case 1:
+		return face, -x / y, z / y
+	case 2:
+		return face, -x / z, -y / z
+	case 3:
+		return face, z / x, y / x
+	case 4:
+		return face, z / y, -x / y
+	}
+
+	return face, -y / z, -x / z
+}
+
+// s2FaceUVToXYZ is the vector, not unit, of the position of the face.
+func s2FaceUVToXYZ(face int, u, v float64) [3]float64 {
+	switch face {
+	case 0:
+		return [3]float64{1, u, v}
+	case 1:
+		return [3]float64{-u, 1, v}
+	case 2:
+		return [3]float64{-u, -v, 1}
+	case 3:
+		return [3]float64{-1, -v, -u}
+	case 4:
+		return [3]float64{v, -1, -u}
+	}
+
+	return [3]float64{v, u, -1}
+}
+
+// s2UVToST applies the quadratic transformation that makes the cells of each level of similar areas.
+func s2UVToST(u float64) float64 {
+	if u >= 0 {
+		return 0.5 * math.Sqrt(1+3*u)
+	}
+
+	return 1 - 0.5*math.Sqrt(1-3*u)
+}
+
+func s2STToUV(s float64) float64 {
+	if s >= 0.5 {
+		return (4*s*s - 1) / 3
+	}
+
+	return (1 - 4*(1-s)*(1-s)) / 3
+}
+
+func s2STToIJ(s float64) int {
+	return int(math.Max(0, math.Min(s2MaxSize-1, math.Floor(s2MaxSize*s))))
+}
+
+// s2PointToXYZ is the unit vector of the point.
+func s2PointToXYZ(pointAStt PointStt) [3]float64 {
+	var sinLatitude, cosLatitude = math.Sincos(DegreesToRadians(pointAStt.Loc[1]))
+	var sinLongitude, cosLongitude = math.Sincos(DegreesToRadians(pointAStt.Loc[0]))
+
+	return [3]float64{cosLatitude * cosLongitude, cosLatitude * sinLongitude, sinLatitude}
+}
+
+// s2XYZToPoint is the point of the vector.
+func s2XYZToPoint(xyz [3]float64) PointStt {
+	var returnLStt PointStt
+	returnLStt.SetLngLatDegrees(RadiansToDegrees(math.Atan2(xyz[1], xyz[0])), RadiansToDegrees(math.Atan2(xyz[2], math.Hypot(xyz[0], xyz[1]))))
+
+	return returnLStt
+}
+
+// s2CellIdFromFaceIJ is the leaf cell of the position i, j of the face.
+func s2CellIdFromFaceIJ(face, i, j int) S2CellId {
+	var n = uint64(face) << (s2PosBits - 1)
+	var bits = face & s2SwapMask
+	var mask = (1 << s2LookupBits) - 1
+
+	for k := 7; k >= 0; k -= 1 {
+		bits += ((i >> uint(k*s2LookupBits)) & mask) << (s2LookupBits + 2)
+		bits += ((j >> uint(k*s2LookupBits)) & mask) << 2
+		bits = s2LookupPos[bits]
+		n |= uint64(bits>>2) << (uint(k) * 2 * s2LookupBits)
+		bits &= s2SwapMask | s2InvertMask
+	}
+
+	return S2CellId(n*2 + 1)
+}
+
+// English: Cell of the point at the level, between 0 and 30. Levels out of these limits are taken as the nearest
+// limit.
+//
+// Português: Célula do ponto no nível, entre 0 e 30. Níveis fora destes limites são tomados como o limite mais
+// próximo.
+func (el *PointStt) S2CellId(level int) S2CellId {
+	face, u, v := s2FaceUV(s2PointToXYZ(*el))
+	var leaf = s2CellIdFromFaceIJ(face, s2STToIJ(s2UVToST(u)), s2STToIJ(s2UVToST(v)))
+
+	return leaf.Parent(level)
+}
+
+// English: Cell from its token, the hexadecimal number without the zeros at the right.
+//
+// Português: Célula a partir do seu token, o número hexadecimal sem os zeros à direita.
+func S2CellIdFromToken(token string) (S2CellId, error) {
+	if len(token) == 0 || len(token) > 16 {
+		return 0, fmt.Errorf("s2: invalid token '%v'", token)
+	}
+
+	id, err := strconv.ParseUint(token+strings.Repeat("0", 16-len(token)), 16, 64)
+	if err != nil || !S2CellId(id).IsValid() {
+		return 0, fmt.Errorf("s2: invalid token '%v'", token)
+	}
+
+	return S2CellId(id), nil
+}
+
+// English: Token of the cell, the hexadecimal number without the zeros at the right.
+//
+// Português: Token da célula, o número hexadecimal sem os zeros à direita.
+func (e S2CellId) ToToken() string {
+	if e == 0 {
+		return "X"
+	}
+
+	return strings.TrimRight(fmt.Sprintf("%016x", uint64(e)), "0")
+}
+
+func (e S2CellId) String() string {
+	return e.ToToken()
+}
+
+// lsb is the lowest bit set, which marks the level of the cell.
+func (e S2CellId) lsb() uint64 {
+	return uint64(e) & -uint64(e)
+}
+
+func s2LsbForLevel(level int) uint64 {
+	return 1 << uint(2*(S2_MAX_LEVEL-level))
+}
+
+// English: True when the number is a cell of a valid face and level.
+//
+// Português: Verdadeiro quando o número é uma célula de face e nível válidos.
+func (e S2CellId) IsValid() bool {
+	return e.Face() < 6 && e.lsb()&0x1555555555555555 != 0
+}
+
+func (e S2CellId) Face() int {
+	return int(uint64(e) >> s2PosBits)
+}
+
+// English: Level of the cell, from 0, the face, to 30.
+//
+// Português: Nível da célula, de 0, a face, a 30.
+func (e S2CellId) Level() int {
+	var level = S2_MAX_LEVEL
+	for lsb := e.lsb(); lsb > 1 && level > 0; lsb >>= 2 {
+		level -= 1
+	}
+
+	return level
+}
+
+// English: The cell of the level that contains this cell. Levels below zero or above the level of the cell are taken
+// as the nearest limit.
+//
+// Português: A célula do nível que contém esta célula. Níveis abaixo de zero ou acima do nível da célula são tomados
+// como o limite mais próximo.
+func (e S2CellId) Parent(level int) S2CellId {
+	if level < 0 {
+		level = 0
+	}
+	if level >= e.Level() {
+		return e
+	}
+
+	var lsb = s2LsbForLevel(level)
+
+	return S2CellId((uint64(e) & -lsb) | lsb)
+}
+
+// English: The four cells of the next level, in the order of the Hilbert curve. The leaf cells have no children and
+// return themselves.
+//
+// Português: As quatro células do próximo nível, na ordem da curva de Hilbert. As células folha não têm filhos e
+// devolvem elas mesmas.
+func (e S2CellId) Children() [4]S2CellId {
+	var children [4]S2CellId
+	var lsb = e.lsb()
+
+	if lsb == 1 {
+		return [4]S2CellId{e, e, e, e}
+	}
+
+	var childLsb = lsb >> 2
+	var child = uint64(e) - lsb + childLsb
+	for k := range children {
+		children[k] = S2CellId(child)
+		child += childLsb << 1
+	}
+
+	return children
+}
+
+// English: The smallest leaf cell contained in this cell.
+//
+// Português: A menor célula folha contida nesta célula.
+func (e S2CellId) RangeMin() S2CellId {
+	return S2CellId(uint64(e) - (e.lsb() - 1))
+}
+
+// English: The largest leaf cell contained in this cell.
+//
+// Português: A maior célula folha contida nesta célula.
+func (e S2CellId) RangeMax() S2CellId {
+	return S2CellId(uint64(e) + (e.lsb() - 1))
+}
+
+// English: True when the other cell is this cell or one of its descendants.
+//
+// Português: Verdadeiro quando a outra célula é esta célula ou um dos seus descendentes.
+func (e S2CellId) Contains(other S2CellId) bool {
+	return e.RangeMin() <= other && other <= e.RangeMax()
+}
+
+// faceIJOrientation is the face, the position of the leaf cell at the start of the cell and the orientation of the
+// Hilbert curve inside it.
+func (e S2CellId) faceIJOrientation() (int, int, int, int) {
+	var face = e.Face()
+	var orientation = face & s2SwapMask
+	var i, j = 0, 0
+	var nbits = S2_MAX_LEVEL - 7*s2LookupBits
+
+	for k := 7; k >= 0; k -= 1 {
+		orientation += (int(uint64(e)>>uint(k*2*s2LookupBits+1)) & ((1 << uint(2*nbits)) - 1)) << 2
+		orientation = s2LookupIJ[orientation]
+		i += (orientation >> (s2LookupBits + 2)) << uint(k*s2LookupBits)
+		j += ((orientation >> 2) & ((1 << s2LookupBits) - 1)) << uint(k*s2LookupBits)
+		orientation &= s2SwapMask | s2InvertMask
+		nbits = s2LookupBits
+	}
+
+	if e.lsb()&0x1111111111111110 != 0 {
+		orientation ^= s2SwapMask
+	}
+
+	return face, i, j, orientation
+}
+
+// bounds returns the face and the limits of the cell in the coordinates s and t of the face.
+func (e S2CellId) bounds() (int, [2]float64, [2]float64) {
+	face, i, j, _ := e.faceIJOrientation()
+	var size = 1 << uint(S2_MAX_LEVEL-e.Level())
+
+	// i and j are of some leaf cell inside the cell, the start of the cell is found with the mask of its size
+	i &= -size
+	j &= -size
+
+	return face,
+		[2]float64{float64(i) / s2MaxSize, float64(i+size) / s2MaxSize},
+		[2]float64{float64(j) / s2MaxSize, float64(j+size) / s2MaxSize}
+}
+
+// English: Center of the cell.
+//
+// Português: Centro da célula.
+func (e S2CellId) Center() PointStt {
+	face, s, t := e.bounds()
+
+	return s2XYZToPoint(s2FaceUVToXYZ(face, s2STToUV((s[0]+s[1])/2), s2STToUV((t[0]+t[1])/2)))
+}
+
+// English: The four corners of the cell, counterclockwise.
+//
+// Português: Os quatro cantos da célula, em sentido anti-horário.
+func (e S2CellId) Vertices() [4]PointStt {
+	face, s, t := e.bounds()
+
+	return [4]PointStt{
+		s2XYZToPoint(s2FaceUVToXYZ(face, s2STToUV(s[0]), s2STToUV(t[0]))),
+		s2XYZToPoint(s2FaceUVToXYZ(face, s2STToUV(s[1]), s2STToUV(t[0]))),
+		s2XYZToPoint(s2FaceUVToXYZ(face, s2STToUV(s[1]), s2STToUV(t[1]))),
+		s2XYZToPoint(s2FaceUVToXYZ(face, s2STToUV(s[0]), s2STToUV(t[1]))),
+	}
+}
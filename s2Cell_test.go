@@ -0,0 +1,61 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+func ExamplePointStt_S2CellId() {
+	var point PointStt
+	point.SetLngLatDegrees(0, 0)
+	fmt.Printf("origin: %x\n", uint64(point.S2CellId(S2_MAX_LEVEL)))
+
+	point.SetLngLatDegrees(2.2945, 48.8584)
+	var cell = point.S2CellId(12)
+	fmt.Printf("cell: %v, level %v, face %v, parent %v\n", cell, cell.Level(), cell.Face(), cell.Parent(8))
+
+	// the leaf cell of the point is inside the range of the cell
+	var leaf = point.S2CellId(S2_MAX_LEVEL)
+	fmt.Printf("range: %v\n", cell.RangeMin() <= leaf && leaf <= cell.RangeMax())
+
+	token, _ := S2CellIdFromToken(cell.ToToken())
+	fmt.Printf("token: %v\n", token == cell)
+
+	// Output:
+	// origin: 1000000000000001
+	// cell: 47e66ff, level 12, face 2, parent 47e67
+	// range: true
+	// token: true
+}
+
+func ExamplePolygonStt_S2Covering() {
+	var polygon PolygonStt
+	polygon.AddLngLatDegrees(-48, -16)
+	polygon.AddLngLatDegrees(-47, -16)
+	polygon.AddLngLatDegrees(-47.5, -15)
+	polygon.Init()
+
+	covering, _ := polygon.S2Covering(16, 200)
+	interior, _ := polygon.S2InteriorCovering(16, 200)
+
+	var levels = make(map[int]int)
+	for _, cell := range covering {
+		levels[cell.Level()] += 1
+	}
+	fmt.Printf("covering: %v cells, %v\n", len(covering), levels)
+
+	// every cell of the interior covering is in the covering
+	var contained = 0
+	for _, inner := range interior {
+		for _, cell := range covering {
+			if cell.Contains(inner) {
+				contained += 1
+				break
+			}
+		}
+	}
+	fmt.Printf("interior: %v cells, %v in the covering\n", len(interior), contained)
+
+	// Output:
+	// covering: 186 cells, map[8:1 9:5 10:24 11:156]
+	// interior: 77 cells, 77 in the covering
+}
@@ -0,0 +1,252 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// number of segments of each edge of a cell when it is compared to the polygon in longitude and latitude
+const s2EdgeSegments = 8
+
+// s2Boundary is the border of the cell, counterclockwise, in longitude and latitude, with the longitudes continuous
+// around the one of the center, and the center. polar is true when the cell touches a pole and has no such border.
+func (e S2CellId) s2Boundary() (ring [][2]float64, center [2]float64, polar bool) {
+	face, s, t := e.bounds()
+
+	if (face == 2 || face == 5) && s[0] <= 0.5 && 0.5 <= s[1] && t[0] <= 0.5 && 0.5 <= t[1] {
+		polar = true
+	}
+
+	var corners = [5][2]float64{{s[0], t[0]}, {s[1], t[0]}, {s[1], t[1]}, {s[0], t[1]}, {s[0], t[0]}}
+	var centerPoint = e.Center()
+	center = centerPoint.Loc
+
+	ring = make([][2]float64, 0, 4*s2EdgeSegments)
+	for k := 0; k != 4; k += 1 {
+		for step := 0; step != s2EdgeSegments; step += 1 {
+			var fraction = float64(step) / s2EdgeSegments
+			var sValue = corners[k][0] + (corners[k+1][0]-corners[k][0])*fraction
+			var tValue = corners[k][1] + (corners[k+1][1]-corners[k][1])*fraction
+
+			var point = s2XYZToPoint(s2FaceUVToXYZ(face, s2STToUV(sValue), s2STToUV(tValue)))
+			var longitude = center[0] + math.Remainder(point.Loc[0]-center[0], 360)
+
+			ring = append(ring, [2]float64{longitude, point.Loc[1]})
+		}
+	}
+
+	return ring, center, polar
+}
+
+// s2PointInRing is the even-odd rule of the point in the ring in the plane.
+func s2PointInRing(point [2]float64, ring [][2]float64) bool {
+	var inside = false
+
+	for i, j := 0, len(ring)-1; i != len(ring); j, i = i, i+1 {
+		if (ring[i][1] > point[1]) != (ring[j][1] > point[1]) &&
+			point[0] < (ring[j][0]-ring[i][0])*(point[1]-ring[i][1])/(ring[j][1]-ring[i][1])+ring[i][0] {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// s2PolygonClassifier returns the function that finds if a cell intersects or is contained in the polygon, with the
+// edges of the polygon as straight lines in longitude and latitude, as PointInPolygon() does.
+func s2PolygonClassifier(polygon *PolygonStt) func(cell S2CellId) (intersects, contains bool) {
+	var rings = [][]PointStt{polygon.PointsList}
+	for k := range polygon.Inner {
+		rings = append(rings, polygon.Inner[k].PointsList)
+	}
+
+	var minimum = [2]float64{math.Inf(1), math.Inf(1)}
+	var maximum = [2]float64{math.Inf(-1), math.Inf(-1)}
+	for _, point := range polygon.PointsList {
+		minimum[0], maximum[0] = math.Min(minimum[0], point.Loc[0]), math.Max(maximum[0], point.Loc[0])
+		minimum[1], maximum[1] = math.Min(minimum[1], point.Loc[1]), math.Max(maximum[1], point.Loc[1])
+	}
+
+	var classifyRing = func(ring [][2]float64, center [2]float64) (bool, bool) {
+		var cellMinimum = [2]float64{math.Inf(1), math.Inf(1)}
+		var cellMaximum = [2]float64{math.Inf(-1), math.Inf(-1)}
+		for _, point := range ring {
+			cellMinimum[0], cellMaximum[0] = math.Min(cellMinimum[0], point[0]), math.Max(cellMaximum[0], point[0])
+			cellMinimum[1], cellMaximum[1] = math.Min(cellMinimum[1], point[1]), math.Max(cellMaximum[1], point[1])
+		}
+
+		if cellMaximum[0] < minimum[0] || cellMinimum[0] > maximum[0] ||
+			cellMaximum[1] < minimum[1] || cellMinimum[1] > maximum[1] {
+			return false, false
+		}
+
+		for _, polygonRing := range rings {
+			for i := range polygonRing {
+				var pointA = polygonRing[i].Loc
+				var pointB = polygonRing[(i+1)%len(polygonRing)].Loc
+
+				if s2PointInRing(pointA, ring) {
+					return true, false
+				}
+
+				for k := range ring {
+					if segmentsIntersect(pointA, pointB, ring[k], ring[(k+1)%len(ring)]) {
+						return true, false
+					}
+				}
+			}
+		}
+
+		// no border of the polygon inside the cell, the cell is all inside or all outside
+		var centerPoint PointStt
+		centerPoint.SetLngLatDegrees(center[0], center[1])
+
+		var inside = polygon.PointInPolygon(centerPoint)
+
+		return inside, inside
+	}
+
+	return func(cell S2CellId) (bool, bool) {
+		ring, center, polar := cell.s2Boundary()
+
+		if polar {
+			// the cell goes around the pole, it is divided until the pole is only a corner
+			var latitude = 90.0
+			for _, point := range ring {
+				latitude = math.Min(latitude, math.Abs(point[1]))
+			}
+
+			if center[1] > 0 {
+				return maximum[1] >= latitude, false
+			}
+
+			return minimum[1] <= -latitude, false
+		}
+
+		intersects, contains := classifyRing(ring, center)
+
+		// the longitudes of the cell go beyond the antimeridian, the polygon is compared on the other side too
+		for _, shift := range []float64{-360, 360} {
+			var shifted = make([][2]float64, len(ring))
+			var outside = true
+
+			for k := range ring {
+				shifted[k] = [2]float64{ring[k][0] + shift, ring[k][1]}
+				if shifted[k][0] >= -180 && shifted[k][0] <= 180 {
+					outside = false
+				}
+			}
+
+			if outside {
+				continue
+			}
+
+			shiftedIntersects, shiftedContains := classifyRing(shifted, [2]float64{center[0] + shift, center[1]})
+			intersects = intersects || shiftedIntersects
+			contains = contains || shiftedContains
+		}
+
+		return intersects, contains
+	}
+}
+
+// s2Cover divides the cells that the classify function finds on the border of the shape, from the faces of the cube,
+// until maxLevel or until one more division goes over maxCells. The cells inside the shape are not divided.
+func s2Cover(maxLevel, maxCells int, classify func(cell S2CellId) (intersects, contains bool)) (inside, border []S2CellId, err error) {
+	if maxCells < 1 {
+		return nil, nil, fmt.Errorf("s2: the covering must have at least one cell")
+	}
+
+	if maxLevel < 0 || maxLevel > S2_MAX_LEVEL {
+		return nil, nil, fmt.Errorf("s2: the level must be between 0 and %v", S2_MAX_LEVEL)
+	}
+
+	inside = make([]S2CellId, 0)
+	border = make([]S2CellId, 0)
+
+	for face := uint64(0); face != 6; face += 1 {
+		var cell = S2CellId(face<<s2PosBits | s2LsbForLevel(0))
+
+		intersects, contains := classify(cell)
+		if contains {
+			inside = append(inside, cell)
+		} else if intersects {
+			border = append(border, cell)
+		}
+	}
+
+	if len(inside)+len(border) > maxCells {
+		return nil, nil, fmt.Errorf("s2: the covering needs more than %v cells at the level of the faces", maxCells)
+	}
+
+	for level := 1; level <= maxLevel && len(border) != 0; level += 1 {
+		var nextInside = append(make([]S2CellId, 0, len(inside)), inside...)
+		var nextBorder = make([]S2CellId, 0)
+
+		for _, parent := range border {
+			for _, cell := range parent.Children() {
+				intersects, contains := classify(cell)
+				if contains {
+					nextInside = append(nextInside, cell)
+				} else if intersects {
+					nextBorder = append(nextBorder, cell)
+				}
+			}
+		}
+
+		if len(nextInside)+len(nextBorder) > maxCells {
+			break
+		}
+
+		inside, border = nextInside, nextBorder
+	}
+
+	return inside, border, nil
+}
+
+// s2Sort puts the cells in the order of the Hilbert curve.
+func s2Sort(cells []S2CellId) []S2CellId {
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+
+	return cells
+}
+
+// English: Set of cells, of several levels up to maxLevel and at most maxCells, that covers the polygon, holes
+// included. A point is in one of the cells when its leaf cell, S2CellId(30), is between RangeMin() and RangeMax() of
+// the cell. The edges of the polygon are straight lines in longitude and latitude, as in PointInPolygon().
+//
+// Português: Conjunto de células, de vários níveis até maxLevel e no máximo maxCells, que cobre o polígono, buracos
+// incluídos. Um ponto está em uma das células quando a sua célula folha, S2CellId(30), está entre RangeMin() e
+// RangeMax() da célula. As arestas do polígono são retas em longitude e latitude, como em PointInPolygon().
+func (el *PolygonStt) S2Covering(maxLevel, maxCells int) ([]S2CellId, error) {
+	if len(el.PointsList) < 3 {
+		return nil, fmt.Errorf("s2: the polygon must have at least three points")
+	}
+
+	inside, border, err := s2Cover(maxLevel, maxCells, s2PolygonClassifier(el))
+	if err != nil {
+		return nil, err
+	}
+
+	return s2Sort(append(inside, border...)), nil
+}
+
+// English: Set of cells all inside the polygon, out of the holes, found by the same division of S2Covering(). A point
+// in one of the cells is inside the polygon; the set is empty when the polygon is smaller than the cells.
+//
+// Português: Conjunto de células todas dentro do polígono, fora dos buracos, achadas pela mesma divisão de
+// S2Covering(). Um ponto em uma das células está dentro do polígono; o conjunto é vazio quando o polígono é menor que
+// as células.
+func (el *PolygonStt) S2InteriorCovering(maxLevel, maxCells int) ([]S2CellId, error) {
+	if len(el.PointsList) < 3 {
+		return nil, fmt.Errorf("s2: the polygon must have at least three points")
+	}
+
+	inside, _, err := s2Cover(maxLevel, maxCells, s2PolygonClassifier(el))
+	if err != nil {
+		return nil, err
+	}
+
+	return s2Sort(inside), nil
+}
@@ -0,0 +1,41 @@
+package iotmaker_geo_osm
+
+// Area with its value in square meters. The sign, when present, follows the orientation of the polygon.
+type AreaStt struct {
+	SquareMeters float64 // area
+}
+
+// Get area as square meters
+func (a *AreaStt) GetSquareMeters() float64 {
+	return a.SquareMeters
+}
+
+// Get area as square kilometers
+func (a *AreaStt) GetSquareKilometers() float64 {
+	return a.SquareMeters / 1000000
+}
+
+// Get area as hectares
+func (a *AreaStt) GetHectares() float64 {
+	return a.SquareMeters / 10000
+}
+
+// Set area as square meters
+func (a *AreaStt) SetSquareMeters(m2 float64) {
+	a.SquareMeters = m2
+}
+
+// Set area as square kilometers
+func (a *AreaStt) SetSquareKilometers(km2 float64) {
+	a.SquareMeters = km2 * 1000000
+}
+
+// Set area as hectares
+func (a *AreaStt) SetHectares(ha float64) {
+	a.SquareMeters = ha * 10000
+}
+
+// Add square meters to the area
+func (a *AreaStt) AddSquareMeters(m2 float64) {
+	a.SquareMeters += m2
+}
@@ -0,0 +1,194 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// English: Helmert transformation of seven parameters, in the position vector convention (EPSG method 9606).
+//
+// Português: Transformação de Helmert de sete parâmetros, na convenção de vetor posição (método EPSG 9606).
+type HelmertStt struct {
+	// English: translations, in meters
+	//
+	// Português: translações, em metros
+	Tx, Ty, Tz float64
+
+	// English: rotations, in arc seconds
+	//
+	// Português: rotações, em segundos de arco
+	Rx, Ry, Rz float64
+
+	// English: scale correction, in parts per million
+	//
+	// Português: correção de escala, em partes por milhão
+	Scale float64
+}
+
+// apply transforms the earth-centered, earth-fixed coordinates, in meters.
+func (el HelmertStt) apply(cartesian [3]float64) [3]float64 {
+	var arcSecond = math.Pi / (180 * 3600)
+	var rx = el.Rx * arcSecond
+	var ry = el.Ry * arcSecond
+	var rz = el.Rz * arcSecond
+	var scale = 1 + el.Scale*1e-6
+
+	return [3]float64{
+		el.Tx + scale*(cartesian[0]-rz*cartesian[1]+ry*cartesian[2]),
+		el.Ty + scale*(rz*cartesian[0]+cartesian[1]-rx*cartesian[2]),
+		el.Tz + scale*(-ry*cartesian[0]+rx*cartesian[1]+cartesian[2]),
+	}
+}
+
+// inverse is the transformation in the opposite direction, with the parameters negated, as done by the EPSG.
+func (el HelmertStt) inverse() HelmertStt {
+	return HelmertStt{
+		Tx:    -el.Tx,
+		Ty:    -el.Ty,
+		Tz:    -el.Tz,
+		Rx:    -el.Rx,
+		Ry:    -el.Ry,
+		Rz:    -el.Rz,
+		Scale: -el.Scale,
+	}
+}
+
+// English: Geodetic datum, the ellipsoid and the transformation of its coordinates to WGS84.
+//
+// Português: Datum geodésico, o elipsoide e a transformação das suas coordenadas para o WGS84.
+type DatumStt struct {
+	// English: name used by the registry, as "SIRGAS2000"
+	//
+	// Português: nome usado pelo registro, como "SIRGAS2000"
+	Name string
+
+	Ellipsoid EllipsoidStt
+
+	// English: transformation from this datum to WGS84
+	//
+	// Português: transformação deste datum para o WGS84
+	ToWGS84 HelmertStt
+}
+
+var (
+	DATUM_WGS84 = DatumStt{Name: "WGS84", Ellipsoid: ELLIPSOID_WGS84}
+
+	// English: Brazilian datum, the same as WGS84 at the level of centimeters
+	//
+	// Português: datum brasileiro, o mesmo que o WGS84 em nível de centímetros
+	DATUM_SIRGAS2000 = DatumStt{Name: "SIRGAS2000", Ellipsoid: ELLIPSOID_GRS80}
+
+	// English: South American Datum of 1969, with the parameters of the IBGE to SIRGAS2000
+	//
+	// Português: South American Datum de 1969, com os parâmetros do IBGE para o SIRGAS2000
+	DATUM_SAD69 = DatumStt{Name: "SAD69", Ellipsoid: ELLIPSOID_GRS67_MODIFIED, ToWGS84: HelmertStt{Tx: -67.35, Ty: 3.88, Tz: -38.22}}
+
+	// English: sphere centered on WGS84, for calculations where the ellipsoid is not wanted
+	//
+	// Português: esfera centrada no WGS84, para cálculos onde o elipsoide não é desejado
+	DATUM_SPHERE = DatumStt{Name: "SPHERE", Ellipsoid: ELLIPSOID_SPHERE}
+)
+
+var datumRegistry = struct {
+	sync.RWMutex
+	list map[string]DatumStt
+}{
+	list: map[string]DatumStt{
+		DATUM_WGS84.Name:      DATUM_WGS84,
+		DATUM_SIRGAS2000.Name: DATUM_SIRGAS2000,
+		DATUM_SAD69.Name:      DATUM_SAD69,
+		DATUM_SPHERE.Name:     DATUM_SPHERE,
+	},
+}
+
+// English: Adds the datum to the registry, replacing another with the same name.
+//
+// Português: Adiciona o datum ao registro, substituindo outro com o mesmo nome.
+func RegisterDatum(datum DatumStt) error {
+	if datum.Name == "" {
+		return fmt.Errorf("datum: the name must be set")
+	}
+
+	if err := RegisterEllipsoid(datum.Ellipsoid); err != nil {
+		return fmt.Errorf("datum '%v': %v", datum.Name, err)
+	}
+
+	datumRegistry.Lock()
+	defer datumRegistry.Unlock()
+
+	datumRegistry.list[datum.Name] = datum
+
+	return nil
+}
+
+// English: Returns the datum registered with the name.
+//
+// Português: Devolve o datum registrado com o nome.
+func FindDatum(name string) (DatumStt, error) {
+	datumRegistry.RLock()
+	defer datumRegistry.RUnlock()
+
+	datum, found := datumRegistry.list[name]
+	if !found {
+		return datum, fmt.Errorf("datum '%v' not found", name)
+	}
+
+	return datum, nil
+}
+
+var datumSelected = struct {
+	sync.RWMutex
+	datum DatumStt
+}{
+	datum: DATUM_WGS84,
+}
+
+// English: Selects the datum of the coordinates, whose ellipsoid is used by EarthRadius(), the geodesic functions and
+// the distance models. The default is DATUM_WGS84.
+//
+// The datum is global and can be selected by any goroutine, but the work done by the others at the same time may use
+// both datums. To work on other datums at the same time, use the methods of EllipsoidStt, as EarthRadius() and
+// GeodesicInverseKarney().
+//
+// Português: Seleciona o datum das coordenadas, cujo elipsoide é usado por EarthRadius(), pelas funções geodésicas e
+// pelos modelos de distância. O padrão é DATUM_WGS84.
+//
+// O datum é global e pode ser selecionado por qualquer goroutine, mas o trabalho feito pelas outras ao mesmo tempo pode
+// usar os dois datums. Para trabalhar em outros datums ao mesmo tempo, use os métodos de EllipsoidStt, como
+// EarthRadius() e GeodesicInverseKarney().
+func SetDatum(datumAStt DatumStt) {
+	datumSelected.Lock()
+	defer datumSelected.Unlock()
+
+	datumSelected.datum = datumAStt
+}
+
+// English: Returns the datum selected by SetDatum().
+//
+// Português: Devolve o datum selecionado por SetDatum().
+func GetDatum() DatumStt {
+	datumSelected.RLock()
+	defer datumSelected.RUnlock()
+
+	return datumSelected.datum
+}
+
+// English: Converts the coordinates of the point, and its altitude above the ellipsoid, from one datum to another,
+// passing through WGS84 by the Helmert transformations. The other fields of the point are kept.
+//
+// Português: Converte as coordenadas do ponto, e a sua altitude sobre o elipsoide, de um datum para outro, passando
+// pelo WGS84 pelas transformações de Helmert. Os outros campos do ponto são mantidos.
+func TransformDatum(pointAStt PointStt, fromAStt, toAStt DatumStt) PointStt {
+	var cartesian = fromAStt.Ellipsoid.toCartesian(pointAStt.Loc[1], pointAStt.Loc[0], pointAStt.Alt)
+
+	cartesian = fromAStt.ToWGS84.apply(cartesian)
+	cartesian = toAStt.ToWGS84.inverse().apply(cartesian)
+
+	latitude, longitude, height := toAStt.Ellipsoid.fromCartesian(cartesian)
+
+	pointAStt.SetLngLatDegrees(longitude, latitude)
+	pointAStt.Alt = height
+
+	return pointAStt
+}
@@ -0,0 +1,60 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"sync"
+)
+
+func ExampleTransformDatum() {
+	// Brasília, in SAD69
+	var point PointStt
+	point.SetLngLatDegrees(-47.8825, -15.7942)
+
+	var sirgas = TransformDatum(point, DATUM_SAD69, DATUM_SIRGAS2000)
+	fmt.Printf("SIRGAS2000: %.6f %.6f, %.2f m\n", sirgas.Loc[0], sirgas.Loc[1], sirgas.Alt)
+
+	var back = TransformDatum(sirgas, DATUM_SIRGAS2000, DATUM_SAD69)
+	fmt.Printf("SAD69: %.6f %.6f, %.2f m\n", back.Loc[0], back.Loc[1], back.Alt)
+
+	// the same line on two ellipsoids
+	var pointA, pointB PointStt
+	pointA.SetLngLatDegrees(-47.8825, -15.7942)
+	pointB.SetLngLatDegrees(-43.1729, -22.9068)
+
+	for _, name := range []string{"WGS84", "SAD69", "SPHERE"} {
+		datum, _ := FindDatum(name)
+		distance, _, _ := datum.Ellipsoid.GeodesicInverseKarney(pointA, pointB)
+		fmt.Printf("%v: %.3f m\n", datum.Name, distance.GetMeters())
+	}
+
+	// Output:
+	// SIRGAS2000: -47.882942 -15.794648, -12.87 m
+	// SAD69: -47.882500 -15.794200, 0.00 m
+	// WGS84: 929694.427 m
+	// SAD69: 929697.691 m
+	// SPHERE: 932304.561 m
+}
+
+func ExampleSetDatum() {
+	// the datum can be selected while other goroutines read it
+	var group sync.WaitGroup
+	for k := 0; k != 4; k += 1 {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			for i := 0; i != 100; i += 1 {
+				SetDatum(DATUM_SIRGAS2000)
+				_ = GetDatum().Ellipsoid.Major
+			}
+		}()
+	}
+	group.Wait()
+
+	fmt.Printf("selected: %v\n", GetDatum().Name)
+	SetDatum(DATUM_WGS84)
+	fmt.Printf("selected: %v\n", GetDatum().Name)
+
+	// Output:
+	// selected: SIRGAS2000
+	// selected: WGS84
+}
@@ -0,0 +1,41 @@
+package iotmaker_geo_osm
+
+type Direction int
+
+const (
+	DIRECTION_NORTH Direction = iota
+	DIRECTION_NORTH_EAST
+	DIRECTION_EAST
+	DIRECTION_SOUTH_EAST
+	DIRECTION_SOUTH
+	DIRECTION_SOUTH_WEST
+	DIRECTION_WEST
+	DIRECTION_NORTH_WEST
+)
+
+var directions = [...]string{
+	"N",
+	"NE",
+	"E",
+	"SE",
+	"S",
+	"SW",
+	"W",
+	"NW",
+}
+
+// steps of longitude and latitude of each direction
+var directionSteps = [...][2]float64{
+	{0, 1},
+	{1, 1},
+	{1, 0},
+	{1, -1},
+	{0, -1},
+	{-1, -1},
+	{-1, 0},
+	{-1, 1},
+}
+
+func (e Direction) String() string {
+	return directions[e]
+}
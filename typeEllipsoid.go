@@ -0,0 +1,188 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// English: Reference ellipsoid, defined by the semi-major axis and the flattening. A sphere has flattening zero.
+//
+// Português: Elipsoide de referência, definido pelo semieixo maior e pelo achatamento. Uma esfera tem achatamento
+// zero.
+type EllipsoidStt struct {
+	// English: name used by the registry, as "WGS84"
+	//
+	// Português: nome usado pelo registro, como "WGS84"
+	Name string
+
+	// English: semi-major axis, in meters
+	//
+	// Português: semieixo maior, em metros
+	Major float64
+
+	// English: flattening, (major - minor) / major
+	//
+	// Português: achatamento, (maior - menor) / maior
+	Flattening float64
+}
+
+var (
+	ELLIPSOID_WGS84 = EllipsoidStt{Name: "WGS84", Major: 6378137.0, Flattening: 1 / 298.257223563}
+	ELLIPSOID_GRS80 = EllipsoidStt{Name: "GRS80", Major: 6378137.0, Flattening: 1 / 298.257222101}
+
+	// English: GRS 1967 Modified, the ellipsoid of SAD69
+	//
+	// Português: GRS 1967 Modificado, o elipsoide do SAD69
+	ELLIPSOID_GRS67_MODIFIED = EllipsoidStt{Name: "GRS67_MODIFIED", Major: 6378160.0, Flattening: 1 / 298.25}
+
+	// English: sphere with the mean radius of the Earth, as defined by the IUGG
+	//
+	// Português: esfera com o raio médio da Terra, como definido pela IUGG
+	ELLIPSOID_SPHERE = EllipsoidStt{Name: "SPHERE", Major: 6371008.8, Flattening: 0}
+)
+
+var ellipsoidRegistry = struct {
+	sync.RWMutex
+	list map[string]EllipsoidStt
+}{
+	list: map[string]EllipsoidStt{
+		ELLIPSOID_WGS84.Name:          ELLIPSOID_WGS84,
+		ELLIPSOID_GRS80.Name:          ELLIPSOID_GRS80,
+		ELLIPSOID_GRS67_MODIFIED.Name: ELLIPSOID_GRS67_MODIFIED,
+		ELLIPSOID_SPHERE.Name:         ELLIPSOID_SPHERE,
+	},
+}
+
+// ellipsoidOrSelected is the ellipsoid or, when it is the zero value, the ellipsoid of the datum selected by SetDatum()
+func ellipsoidOrSelected(ellipsoid EllipsoidStt) EllipsoidStt {
+	if ellipsoid.Major == 0 {
+		return GetDatum().Ellipsoid
+	}
+
+	return ellipsoid
+}
+
+// English: Adds the ellipsoid to the registry, replacing another with the same name.
+//
+// Português: Adiciona o elipsoide ao registro, substituindo outro com o mesmo nome.
+func RegisterEllipsoid(ellipsoid EllipsoidStt) error {
+	if ellipsoid.Name == "" || !(ellipsoid.Major > 0) || !(ellipsoid.Flattening >= 0 && ellipsoid.Flattening < 1) {
+		return fmt.Errorf("ellipsoid '%v': the name must be set, the major axis must be positive and the flattening between 0 and 1", ellipsoid.Name)
+	}
+
+	ellipsoidRegistry.Lock()
+	defer ellipsoidRegistry.Unlock()
+
+	ellipsoidRegistry.list[ellipsoid.Name] = ellipsoid
+
+	return nil
+}
+
+// English: Returns the ellipsoid registered with the name.
+//
+// Português: Devolve o elipsoide registrado com o nome.
+func FindEllipsoid(name string) (EllipsoidStt, error) {
+	ellipsoidRegistry.RLock()
+	defer ellipsoidRegistry.RUnlock()
+
+	ellipsoid, found := ellipsoidRegistry.list[name]
+	if !found {
+		return ellipsoid, fmt.Errorf("ellipsoid '%v' not found", name)
+	}
+
+	return ellipsoid, nil
+}
+
+// English: semi-minor axis, in meters
+//
+// Português: semieixo menor, em metros
+func (el EllipsoidStt) Minor() float64 {
+	return el.Major * (1 - el.Flattening)
+}
+
+// English: square of the first eccentricity
+//
+// Português: quadrado da primeira excentricidade
+func (el EllipsoidStt) EccentricitySquared() float64 {
+	return el.Flattening * (2 - el.Flattening)
+}
+
+// English: Earth radius at the latitude of the point, in meters.
+//
+// Português: Raio da Terra na latitude do ponto, em metros.
+func (el EllipsoidStt) EarthRadius(pointAStt PointStt) DistanceStt {
+	var returnLStt DistanceStt
+
+	var latitude = pointAStt.Rad[1]
+	var major = el.Major
+	var minor = el.Minor()
+
+	returnLStt.SetMeters(
+		math.Sqrt(
+			(math.Pow(major*major*math.Cos(latitude), 2.0) + math.Pow(minor*minor*math.Sin(latitude), 2.0)) /
+				(math.Pow(major*math.Cos(latitude), 2.0) + math.Pow(minor*math.Sin(latitude), 2.0))))
+
+	return returnLStt
+}
+
+// toCartesian converts latitude and longitude, in degrees, and height, in meters, into earth-centered, earth-fixed
+// coordinates, in meters.
+func (el EllipsoidStt) toCartesian(latitude, longitude, height float64) [3]float64 {
+	var e2 = el.EccentricitySquared()
+	var sinLatitude, cosLatitude = math.Sincos(DegreesToRadians(latitude))
+	var sinLongitude, cosLongitude = math.Sincos(DegreesToRadians(longitude))
+
+	// radius of curvature in the prime vertical
+	var n = el.Major / math.Sqrt(1-e2*sinLatitude*sinLatitude)
+
+	return [3]float64{
+		(n + height) * cosLatitude * cosLongitude,
+		(n + height) * cosLatitude * sinLongitude,
+		(n*(1-e2) + height) * sinLatitude,
+	}
+}
+
+// fromCartesian converts earth-centered, earth-fixed coordinates into latitude and longitude, in degrees, and height,
+// in meters, iterating on the latitude.
+func (el EllipsoidStt) fromCartesian(cartesian [3]float64) (float64, float64, float64) {
+	var e2 = el.EccentricitySquared()
+	var p = math.Hypot(cartesian[0], cartesian[1])
+	var longitude = math.Atan2(cartesian[1], cartesian[0])
+	var latitude = math.Atan2(cartesian[2], p*(1-e2))
+	var height = 0.0
+
+	if p < 1e-9 {
+		// on the polar axis
+		return math.Copysign(90, cartesian[2]), RadiansToDegrees(longitude), math.Abs(cartesian[2]) - el.Minor()
+	}
+
+	for i := 0; i != 10; i += 1 {
+		var sinLatitude = math.Sin(latitude)
+		var n = el.Major / math.Sqrt(1-e2*sinLatitude*sinLatitude)
+		height = p/math.Cos(latitude) - n
+
+		var next = math.Atan2(cartesian[2], p*(1-e2*n/(n+height)))
+		if math.Abs(next-latitude) < 1e-14 {
+			latitude = next
+			break
+		}
+		latitude = next
+	}
+
+	return RadiansToDegrees(latitude), RadiansToDegrees(longitude), height
+}
+
+var geodesicCache sync.Map
+
+// geodesic is the solver of geodesic problems on the ellipsoid, made once for each ellipsoid.
+func (el EllipsoidStt) geodesic() *geodesicStt {
+	var key = [2]float64{el.Major, el.Flattening}
+
+	solver, found := geodesicCache.Load(key)
+	if !found {
+		solver, _ = geodesicCache.LoadOrStore(key, newGeodesic(el.Major, el.Flattening))
+	}
+
+	return solver.(*geodesicStt)
+}
@@ -21,7 +21,8 @@ type geometry struct {
 	Type        string      `bson:"type" json:"type"`
 	typeConst   GeoJSonType `bson:"-" json:"-"`
 	BoundingBox interface{} `bson:"bbox,omitempty" json:"bbox,omitempty"`
-	Coordinates interface{} `bson:"coordinates" json:"coordinates"`
+	Coordinates interface{} `bson:"coordinates,omitempty" json:"coordinates,omitempty"`
+	Geometries  []geometry  `bson:"geometries,omitempty" json:"geometries,omitempty"`
 }
 
 type features struct {
@@ -50,6 +51,22 @@ type GeoJSon struct {
 	Type          string            `bson:"-" json:"type"`
 	Features      []features        `bson:"-" json:"features"`
 	Tag           map[string]string `bson:"tag" json:"-"`
+
+	// English: writes RFC 7946 GeoJSON: outer rings counterclockwise and holes clockwise, lines and polygons that cross
+	// the antimeridian split in pieces, bbox with west greater than east when the geometry crosses the antimeridian and
+	// positions with two values when no altitude is set.
+	//
+	// Português: escreve GeoJSON RFC 7946: anéis externos no sentido anti-horário e buracos no sentido horário, linhas e
+	// polígonos que cruzam o antimeridiano divididos em pedaços, bbox com oeste maior que leste quando a geometria cruza
+	// o antimeridiano e posições com dois valores quando nenhuma altitude foi definida.
+	Rfc7946 bool `bson:"-" json:"-"`
+
+	// English: number of decimal places of the coordinates written; zero keeps all of them. RFC 7946 suggests 6,
+	// about 10 centimeters.
+	//
+	// Português: número de casas decimais das coordenadas escritas; zero mantém todas. A RFC 7946 sugere 6, cerca de
+	// 10 centímetros.
+	Precision int `bson:"-" json:"-"`
 }
 
 func (e *GeoJSon) Init() {
@@ -65,8 +82,12 @@ func (e *GeoJSon) AddTag(key, value string) {
 
 func (e *GeoJSon) AddGeoMathWay(id string, way *WayStt) {
 	e.NewFeature(id, GEOJSON_LINE_STRING)
-	for _, coordinates := range way.Loc {
-		e.AddLngLat(coordinates[0], coordinates[1])
+	for k, coordinates := range way.Loc {
+		if len(way.Alt) == len(way.Loc) {
+			e.AddLngLatAlt(coordinates[0], coordinates[1], way.Alt[k])
+		} else {
+			e.AddLngLat(coordinates[0], coordinates[1])
+		}
 	}
 	for tagKey, tagValue := range way.Tag {
 		e.AddProperties(tagKey, tagValue)
@@ -75,47 +96,92 @@ func (e *GeoJSon) AddGeoMathWay(id string, way *WayStt) {
 	e.MakeBoundingBox()
 }
 
+// English: Adds a route made of several ways as one MultiLineString feature, one line for each way. The properties of
+// the feature are the tag given, the tags of the ways are not merged, as ways with different names would overwrite
+// each other.
+//
+// Português: Adiciona uma rota feita de vários ways como uma feature MultiLineString, uma linha para cada way. As
+// propriedades da feature são a tag passada, as tags dos ways não são mescladas, pois ways com nomes diferentes se
+// sobrescreveriam.
+func (e *GeoJSon) AddGeoMathWayList(id string, wayList []WayStt, tag map[string]string) {
+	e.NewFeature(id, GEOJSON_MULTI_LINE_STRING)
+	for _, way := range wayList {
+		e.NewLine()
+		for k, coordinates := range way.Loc {
+			if len(way.Alt) == len(way.Loc) {
+				e.AddLngLatAlt(coordinates[0], coordinates[1], way.Alt[k])
+			} else {
+				e.AddLngLat(coordinates[0], coordinates[1])
+			}
+		}
+	}
+	for tagKey, tagValue := range tag {
+		e.AddProperties(tagKey, tagValue)
+		e.AddTag(tagKey, tagValue)
+	}
+	e.MakeBoundingBox()
+}
+
 func (e *GeoJSon) AddGeoMathPoint(id string, point *PointStt) {
 	e.NewFeature(id, GEOJSON_POINT)
 	for tagKey, tagValue := range point.Tag {
 		e.AddProperties(tagKey, tagValue)
 		e.AddTag(tagKey, tagValue)
 	}
-	e.AddLngLat(point.Loc[0], point.Loc[1])
+	e.AddLngLatAlt(point.Loc[0], point.Loc[1], point.Alt)
+	e.MakeBoundingBox()
 }
 
+// AddGeoMathPolygon adds the polygon as a Polygon feature, the outer ring followed by the Inner rings (holes).
 func (e *GeoJSon) AddGeoMathPolygon(id string, polygon *PolygonStt) {
 	e.NewFeature(id, GEOJSON_POLYGON)
 	for _, point := range polygon.PointsList {
-		e.AddLngLat(point.Loc[0], point.Loc[1])
+		e.AddLngLatAlt(point.Loc[0], point.Loc[1], point.Alt)
 	}
 	for tagKey, tagValue := range polygon.Tag {
 		e.AddProperties(tagKey, tagValue)
 		e.AddTag(tagKey, tagValue)
 	}
 	e.ClosePolygon()
+	for _, inner := range polygon.Inner {
+		e.NewSetOfCoordinates()
+		for _, point := range inner.PointsList {
+			e.AddLngLatAlt(point.Loc[0], point.Loc[1], point.Alt)
+		}
+		e.ClosePolygon()
+	}
 	e.MakeBoundingBox()
 }
 
+// AddGeoMathPolygonList adds the list as a MultiPolygon feature, one polygon for each element of the list, each one
+// with its Inner rings (holes).
 func (e *GeoJSon) AddGeoMathPolygonList(id string, polygon *PolygonListStt) {
 	e.NewFeature(id, GEOJSON_MULTI_POLYGON)
-	e.SetOfMultiPolygons(len(polygon.List))
 	for k, listOfPolygons := range polygon.List {
 
 		if k != 0 {
-			e.NewPolygon()
+			e.NewSetOfCoordinates()
 		}
+		e.SetOfMultiPolygons(1 + len(listOfPolygons.Inner))
 
 		for _, point := range listOfPolygons.PointsList {
-			e.AddLngLat(point.Loc[0], point.Loc[1])
+			e.AddLngLatAlt(point.Loc[0], point.Loc[1], point.Alt)
 		}
 		for tagKey, tagValue := range listOfPolygons.Tag {
 			e.AddProperties(tagKey, tagValue)
 			e.AddTag(tagKey, tagValue)
 		}
 		e.ClosePolygon()
-		e.MakeBoundingBox()
+
+		for _, inner := range listOfPolygons.Inner {
+			e.NewPolygon()
+			for _, point := range inner.PointsList {
+				e.AddLngLatAlt(point.Loc[0], point.Loc[1], point.Alt)
+			}
+			e.ClosePolygon()
+		}
 	}
+	e.MakeBoundingBox()
 }
 
 func (e *GeoJSon) NewFeature(id string, geoType GeoJSonType) {
@@ -131,28 +197,63 @@ func (e *GeoJSon) NewFeature(id string, geoType GeoJSonType) {
 		setOfPolygons:    1,
 		Id:               id,
 		Type:             "Feature",
-		Geometry: geometry{
-			typeConst: geoType,
-			Type:      geoType.String(),
-		},
+		Geometry:         newGeometry(geoType),
+	}
+
+	e.Features = append(e.Features, f)
+}
+
+func newGeometry(geoType GeoJSonType) geometry {
+	var g = geometry{
+		typeConst: geoType,
+		Type:      geoType.String(),
 	}
 
 	switch geoType {
 	case GEOJSON_POINT:
-		f.Geometry.Coordinates = []point{}
+		g.Coordinates = []point{}
 	case GEOJSON_LINE_STRING:
-		f.Geometry.Coordinates = []lineString{}
+		g.Coordinates = []lineString{}
 	case GEOJSON_POLYGON:
-		f.Geometry.Coordinates = []polygon{}
+		g.Coordinates = []polygon{}
 	case GEOJSON_MULTI_POINT:
-		f.Geometry.Coordinates = []multiPoint{}
+		g.Coordinates = []multiPoint{}
 	case GEOJSON_MULTI_LINE_STRING:
-		f.Geometry.Coordinates = []multiLineString{}
+		g.Coordinates = []multiLineString{}
 	case GEOJSON_MULTI_POLYGON:
-		f.Geometry.Coordinates = []multiPolygon{}
+		g.Coordinates = []multiPolygon{}
+	case GEOJSON_GEOMETRY_COLLECTION:
+		g.Geometries = make([]geometry, 0)
 	}
 
-	e.Features = append(e.Features, f)
+	return g
+}
+
+// English: Adds a geometry to the GeometryCollection of the last feature. The next points, lines and polygons are
+// added to this geometry, until the next call. Collections cannot be nested.
+//
+// Português: Adiciona uma geometria na GeometryCollection da última feature. Os próximos pontos, linhas e polígonos
+// são adicionados nesta geometria, até a próxima chamada. Coleções não podem ser aninhadas.
+func (e *GeoJSon) NewGeometry(geoType GeoJSonType) {
+	if e.Features[e.setOfFeatures].Geometry.typeConst != GEOJSON_GEOMETRY_COLLECTION || geoType == GEOJSON_GEOMETRY_COLLECTION {
+		return
+	}
+
+	e.Features[e.setOfFeatures].setOfCoordinates = 0
+	e.Features[e.setOfFeatures].setOfLines = 0
+	e.Features[e.setOfFeatures].setOfPolygons = 1
+	e.Features[e.setOfFeatures].Geometry.Geometries = append(e.Features[e.setOfFeatures].Geometry.Geometries, newGeometry(geoType))
+}
+
+// currentGeometry is the geometry of the last feature or, for a GeometryCollection, its last geometry.
+func (e *GeoJSon) currentGeometry() *geometry {
+	var g = &e.Features[e.setOfFeatures].Geometry
+
+	if g.typeConst == GEOJSON_GEOMETRY_COLLECTION && len(g.Geometries) != 0 {
+		return &g.Geometries[len(g.Geometries)-1]
+	}
+
+	return g
 }
 
 func (e *GeoJSon) AddProperties(key, value string) {
@@ -174,55 +275,74 @@ func (e *GeoJSon) NewSetOfCoordinates() {
 	e.Features[e.setOfFeatures].setOfCoordinates += 1
 	e.Features[e.setOfFeatures].setOfLines = 0
 
-	switch e.Features[e.setOfFeatures].Geometry.typeConst {
+	switch e.currentGeometry().typeConst {
 	case GEOJSON_POINT:
-		e.Features[e.setOfFeatures].Geometry.Coordinates = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]point), point{})
+		e.currentGeometry().Coordinates = append(e.currentGeometry().Coordinates.([]point), point{})
 
 	case GEOJSON_LINE_STRING:
-		e.Features[e.setOfFeatures].Geometry.Coordinates = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]lineString), lineString{})
+		e.currentGeometry().Coordinates = append(e.currentGeometry().Coordinates.([]lineString), lineString{})
 
 	case GEOJSON_POLYGON:
-		e.Features[e.setOfFeatures].Geometry.Coordinates = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]polygon), polygon{})
+		e.currentGeometry().Coordinates = append(e.currentGeometry().Coordinates.([]polygon), polygon{})
 
 	case GEOJSON_MULTI_POINT:
-		e.Features[e.setOfFeatures].Geometry.Coordinates = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPoint), multiPoint{})
+		e.currentGeometry().Coordinates = append(e.currentGeometry().Coordinates.([]multiPoint), multiPoint{})
 
 	case GEOJSON_MULTI_LINE_STRING:
-		e.Features[e.setOfFeatures].Geometry.Coordinates = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiLineString), multiLineString{})
+		e.currentGeometry().Coordinates = append(e.currentGeometry().Coordinates.([]multiLineString), multiLineString{})
 
 	case GEOJSON_MULTI_POLYGON:
-		e.Features[e.setOfFeatures].Geometry.Coordinates = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPolygon), multiPolygon{})
+		e.currentGeometry().Coordinates = append(e.currentGeometry().Coordinates.([]multiPolygon), multiPolygon{})
 	}
 }
 
+// English: Starts a new line in the MultiLineString. Nothing is done while the current line has no points.
+//
+// Português: Começa uma nova linha na MultiLineString. Nada é feito enquanto a linha atual não tem pontos.
+func (e *GeoJSon) NewLine() {
+	lines, ok := e.currentGeometry().Coordinates.([]multiLineString)
+	if !ok || len(lines) == 0 || len(lines[e.Features[e.setOfFeatures].setOfCoordinates]) == 0 {
+		return
+	}
+
+	e.NewSetOfCoordinates()
+}
+
 func (e *GeoJSon) ClosePolygon() {
-	switch e.Features[e.setOfFeatures].Geometry.typeConst {
+	switch e.currentGeometry().typeConst {
 	case GEOJSON_POLYGON:
-		firstPointPolygon := e.Features[e.setOfFeatures].Geometry.Coordinates.([]polygon)[0]
-		e.Features[e.setOfFeatures].Geometry.Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates] = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates], firstPointPolygon[0])
+		firstPointPolygon := e.currentGeometry().Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates]
+		e.currentGeometry().Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates] = append(e.currentGeometry().Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates], firstPointPolygon[0])
 
 	case GEOJSON_MULTI_POLYGON:
-		firstPointMultiPolygon := e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates][e.Features[e.setOfFeatures].setOfLines][0]
-		e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates][e.Features[e.setOfFeatures].setOfLines] = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates][e.Features[e.setOfFeatures].setOfLines], firstPointMultiPolygon)
+		firstPointMultiPolygon := e.currentGeometry().Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates][e.Features[e.setOfFeatures].setOfLines][0]
+		e.currentGeometry().Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates][e.Features[e.setOfFeatures].setOfLines] = append(e.currentGeometry().Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates][e.Features[e.setOfFeatures].setOfLines], firstPointMultiPolygon)
 	}
 }
 
-/*
-  todo: The four lines of the bounding box are defined fully within the
-  coordinate reference system; that is, for a box bounded by the values
-  "west", "south", "east", and "north", every point on the northernmost
-  line can be expressed as
-
-  (lon, lat) = (west + (east - west) * t, north)
-
-  with 0 <= t <= 1.
-*/
+// MakeBoundingBox sets the bbox of the geometry of the last feature as [west, south, east, north].
+//
+// The four lines of the bounding box are defined fully within the coordinate reference system, so a geometry that
+// crosses the antimeridian has a box with west greater than east. This form is only written by the Rfc7946 output,
+// that splits the geometry and computes the box again.
 func (e *GeoJSon) MakeBoundingBox() {
 	var latMin, latMax, lngMin, lngMax float64
 
-	switch e.Features[e.setOfFeatures].Geometry.typeConst {
+	switch e.currentGeometry().typeConst {
+	case GEOJSON_POINT:
+		v, ok := e.currentGeometry().Coordinates.([3]float64)
+		if !ok {
+			return
+		}
+
+		latMin = v[1]
+		latMax = v[1]
+
+		lngMin = v[0]
+		lngMax = v[0]
+
 	case GEOJSON_LINE_STRING:
-		for k, v := range e.Features[e.setOfFeatures].Geometry.Coordinates.([]lineString) {
+		for k, v := range e.currentGeometry().Coordinates.([]lineString) {
 			if k == 0 {
 				latMin = v[1]
 				latMax = v[1]
@@ -239,24 +359,26 @@ func (e *GeoJSon) MakeBoundingBox() {
 		}
 
 	case GEOJSON_POLYGON:
-		for k, v := range e.Features[e.setOfFeatures].Geometry.Coordinates.([]polygon) {
-			if k == 0 {
-				latMin = v[k][1]
-				latMax = v[k][1]
-
-				lngMin = v[k][0]
-				lngMax = v[k][0]
-			} else {
-				latMin = math.Min(latMin, v[k][1])
-				latMax = math.Max(latMax, v[k][1])
-
-				lngMin = math.Min(lngMin, v[k][0])
-				lngMax = math.Max(lngMax, v[k][0])
+		for kp, vp := range e.currentGeometry().Coordinates.([]polygon) {
+			for k, v := range vp {
+				if k == 0 && kp == 0 {
+					latMin = v[1]
+					latMax = v[1]
+
+					lngMin = v[0]
+					lngMax = v[0]
+				} else {
+					latMin = math.Min(latMin, v[1])
+					latMax = math.Max(latMax, v[1])
+
+					lngMin = math.Min(lngMin, v[0])
+					lngMax = math.Max(lngMax, v[0])
+				}
 			}
 		}
 
 	case GEOJSON_MULTI_POINT:
-		for k, v := range e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPoint) {
+		for k, v := range e.currentGeometry().Coordinates.([]multiPoint) {
 			if k == 0 {
 				latMin = v[1]
 				latMax = v[1]
@@ -273,24 +395,26 @@ func (e *GeoJSon) MakeBoundingBox() {
 		}
 
 	case GEOJSON_MULTI_LINE_STRING:
-		for k, v := range e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiLineString) {
-			if k == 0 {
-				latMin = v[k][1]
-				latMax = v[k][1]
-
-				lngMin = v[k][0]
-				lngMax = v[k][0]
-			} else {
-				latMin = math.Min(latMin, v[k][1])
-				latMax = math.Max(latMax, v[k][1])
-
-				lngMin = math.Min(lngMin, v[k][0])
-				lngMax = math.Max(lngMax, v[k][0])
+		for kl, vl := range e.currentGeometry().Coordinates.([]multiLineString) {
+			for k, v := range vl {
+				if k == 0 && kl == 0 {
+					latMin = v[1]
+					latMax = v[1]
+
+					lngMin = v[0]
+					lngMax = v[0]
+				} else {
+					latMin = math.Min(latMin, v[1])
+					latMax = math.Max(latMax, v[1])
+
+					lngMin = math.Min(lngMin, v[0])
+					lngMax = math.Max(lngMax, v[0])
+				}
 			}
 		}
 
 	case GEOJSON_MULTI_POLYGON:
-		for kmp, vmp := range e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPolygon) {
+		for kmp, vmp := range e.currentGeometry().Coordinates.([]multiPolygon) {
 			for kp, vp := range vmp {
 				for k, v := range vp {
 					if k == 0 && kmp == 0 && kp == 0 {
@@ -312,7 +436,35 @@ func (e *GeoJSon) MakeBoundingBox() {
 
 	}
 
-	e.Features[e.setOfFeatures].Geometry.BoundingBox = [4]float64{lngMin, latMin, lngMax, latMax}
+	e.currentGeometry().BoundingBox = [4]float64{lngMin, latMin, lngMax, latMax}
+
+	// the box of a collection contains the boxes of all of its geometries
+	var collection = &e.Features[e.setOfFeatures].Geometry
+	if collection.typeConst != GEOJSON_GEOMETRY_COLLECTION {
+		return
+	}
+
+	var found = false
+	for _, g := range collection.Geometries {
+		box, ok := g.BoundingBox.([4]float64)
+		if !ok {
+			continue
+		}
+
+		if found == false {
+			lngMin, latMin, lngMax, latMax = box[0], box[1], box[2], box[3]
+			found = true
+		} else {
+			lngMin = math.Min(lngMin, box[0])
+			latMin = math.Min(latMin, box[1])
+			lngMax = math.Max(lngMax, box[2])
+			latMax = math.Max(latMax, box[3])
+		}
+	}
+
+	if found {
+		collection.BoundingBox = [4]float64{lngMin, latMin, lngMax, latMax}
+	}
 }
 
 func (e *GeoJSon) AddLatLng(lat, lng float64) {
@@ -328,69 +480,87 @@ func (e *GeoJSon) AddLngLatAlt(lng, lat, alt float64) {
 }
 
 func (e *GeoJSon) AddLatLngAlt(lat, lng, alt float64) {
-	switch e.Features[e.setOfFeatures].Geometry.typeConst {
+	switch e.currentGeometry().typeConst {
 	case GEOJSON_POINT:
-		e.Features[e.setOfFeatures].Geometry.Coordinates = point{}
-		e.Features[e.setOfFeatures].Geometry.Coordinates = [3]float64{lng, lat, alt}
+		e.currentGeometry().Coordinates = point{}
+		e.currentGeometry().Coordinates = [3]float64{lng, lat, alt}
 
 	case GEOJSON_LINE_STRING:
-		if len(e.Features[e.setOfFeatures].Geometry.Coordinates.([]lineString)) == 0 {
-			e.Features[e.setOfFeatures].Geometry.Coordinates = make([]lineString, 0)
+		if len(e.currentGeometry().Coordinates.([]lineString)) == 0 {
+			e.currentGeometry().Coordinates = make([]lineString, 0)
 		}
 
-		e.Features[e.setOfFeatures].Geometry.Coordinates = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]lineString), [3]float64{lng, lat, alt})
+		e.currentGeometry().Coordinates = append(e.currentGeometry().Coordinates.([]lineString), [3]float64{lng, lat, alt})
 
 	case GEOJSON_POLYGON:
-		if len(e.Features[e.setOfFeatures].Geometry.Coordinates.([]polygon)) == 0 {
-			e.Features[e.setOfFeatures].Geometry.Coordinates = make([]polygon, 1)
+		if len(e.currentGeometry().Coordinates.([]polygon)) == 0 {
+			e.currentGeometry().Coordinates = make([]polygon, 1)
 		}
 
-		if len(e.Features[e.setOfFeatures].Geometry.Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates]) == 0 {
-			e.Features[e.setOfFeatures].Geometry.Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates] = make(polygon, 0)
+		if len(e.currentGeometry().Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates]) == 0 {
+			e.currentGeometry().Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates] = make(polygon, 0)
 		}
 
-		e.Features[e.setOfFeatures].Geometry.Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates] = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates], [3]float64{lng, lat, alt})
+		e.currentGeometry().Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates] = append(e.currentGeometry().Coordinates.([]polygon)[e.Features[e.setOfFeatures].setOfCoordinates], [3]float64{lng, lat, alt})
 
 	case GEOJSON_MULTI_POINT:
-		if len(e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPoint)) == 0 {
-			e.Features[e.setOfFeatures].Geometry.Coordinates = make([]multiPoint, 0)
+		if len(e.currentGeometry().Coordinates.([]multiPoint)) == 0 {
+			e.currentGeometry().Coordinates = make([]multiPoint, 0)
 		}
 
-		e.Features[e.setOfFeatures].Geometry.Coordinates = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPoint), [3]float64{lng, lat, alt})
+		e.currentGeometry().Coordinates = append(e.currentGeometry().Coordinates.([]multiPoint), [3]float64{lng, lat, alt})
 
 	case GEOJSON_MULTI_LINE_STRING:
-		if len(e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiLineString)) == 0 {
-			e.Features[e.setOfFeatures].Geometry.Coordinates = make([]multiLineString, 1)
+		if len(e.currentGeometry().Coordinates.([]multiLineString)) == 0 {
+			e.currentGeometry().Coordinates = make([]multiLineString, 1)
 		}
 
-		if len(e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiLineString)[e.Features[e.setOfFeatures].setOfCoordinates]) == 0 {
-			e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiLineString)[e.Features[e.setOfFeatures].setOfCoordinates] = make(multiLineString, 0)
+		if len(e.currentGeometry().Coordinates.([]multiLineString)[e.Features[e.setOfFeatures].setOfCoordinates]) == 0 {
+			e.currentGeometry().Coordinates.([]multiLineString)[e.Features[e.setOfFeatures].setOfCoordinates] = make(multiLineString, 0)
 		}
 
-		e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiLineString)[e.Features[e.setOfFeatures].setOfCoordinates] = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiLineString)[e.Features[e.setOfFeatures].setOfCoordinates], [3]float64{lng, lat, alt})
+		e.currentGeometry().Coordinates.([]multiLineString)[e.Features[e.setOfFeatures].setOfCoordinates] = append(e.currentGeometry().Coordinates.([]multiLineString)[e.Features[e.setOfFeatures].setOfCoordinates], [3]float64{lng, lat, alt})
 
 	case GEOJSON_MULTI_POLYGON:
-		if len(e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPolygon)) == 0 {
-			e.Features[e.setOfFeatures].Geometry.Coordinates = make([]multiPolygon, 1)
+		if len(e.currentGeometry().Coordinates.([]multiPolygon)) == 0 {
+			e.currentGeometry().Coordinates = make([]multiPolygon, 1)
 		}
 
-		if len(e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates]) == 0 {
-			e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates] = make(multiPolygon, e.Features[e.setOfFeatures].setOfPolygons)
+		if len(e.currentGeometry().Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates]) == 0 {
+			e.currentGeometry().Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates] = make(multiPolygon, e.Features[e.setOfFeatures].setOfPolygons)
 		}
 
-		e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates][e.Features[e.setOfFeatures].setOfLines] = append(e.Features[e.setOfFeatures].Geometry.Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates][e.Features[e.setOfFeatures].setOfLines], [3]float64{lng, lat, alt})
+		e.currentGeometry().Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates][e.Features[e.setOfFeatures].setOfLines] = append(e.currentGeometry().Coordinates.([]multiPolygon)[e.Features[e.setOfFeatures].setOfCoordinates][e.Features[e.setOfFeatures].setOfLines], [3]float64{lng, lat, alt})
 
 	}
 }
 
 func (e *GeoJSon) String() (string, error) {
-	byteJSon, err := json.Marshal(e)
+	if e.Rfc7946 == false && e.Precision <= 0 {
+		byteJSon, err := json.Marshal(e)
+
+		return string(byteJSon), err
+	}
+
+	var output = struct {
+		Type     string     `json:"type"`
+		Features []features `json:"features"`
+	}{
+		Type:     e.Type,
+		Features: make([]features, len(e.Features)),
+	}
+
+	for k := range e.Features {
+		output.Features[k] = e.outputFeature(e.Features[k])
+	}
+
+	byteJSon, err := json.Marshal(output)
 
 	return string(byteJSon), err
 }
 
 func (e *GeoJSon) StringLastFeature() (string, error) {
-	byteJSon, err := json.Marshal(e.Features[len(e.Features)-1])
+	byteJSon, err := json.Marshal(e.outputFeature(e.Features[len(e.Features)-1]))
 
 	return string(byteJSon), err
 }
@@ -407,7 +577,7 @@ func (e *GeoJSon) StringAllFeatures() (string, error) {
 			features += ","
 		}
 
-		byteJSon, err = json.Marshal(feature)
+		byteJSon, err = json.Marshal(e.outputFeature(feature))
 		if err != nil {
 			return "", err
 		}
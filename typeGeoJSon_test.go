@@ -0,0 +1,58 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+func ExampleGeoJSon_AddGeoMathWayList() {
+	var route = make([]WayStt, 2)
+	route[0].AddLngLatDegrees(0, 0)
+	route[0].AddLngLatDegrees(1, 1)
+	route[0].AddTag("highway", "primary")
+	route[0].AddTag("name", "Avenida Atlântica")
+	route[1].AddLngLatDegrees(1, 1)
+	route[1].AddLngLatDegrees(2, 0)
+	route[1].AddTag("highway", "residential")
+	route[1].AddTag("name", "Rua Figueiredo Magalhães")
+
+	// the names of the ways are not merged into the feature, only the tag given is
+	var geoJSon = GeoJSon{}
+	geoJSon.Init()
+	geoJSon.AddGeoMathWayList("route", route, map[string]string{"ref": "BR-101"})
+
+	output, err := geoJSon.StringLastFeature()
+	fmt.Printf("error: %v\n", err)
+	fmt.Println(output)
+
+	// Output:
+	// error: <nil>
+	// {"type":"Feature","id":"route","properties":{"ref":"BR-101"},"geometry":{"type":"MultiLineString","bbox":[0,0,2,1],"coordinates":[[[0,0,0],[1,1,0]],[[1,1,0],[2,0,0]]]}}
+}
+
+func ExampleGeoJSon_NewGeometry() {
+	var geoJSon = GeoJSon{}
+	geoJSon.Init()
+	geoJSon.Rfc7946 = true
+
+	geoJSon.NewFeature("1", GEOJSON_GEOMETRY_COLLECTION)
+
+	geoJSon.NewGeometry(GEOJSON_POINT)
+	geoJSon.AddLngLat(-1, 2)
+	geoJSon.MakeBoundingBox()
+
+	geoJSon.NewGeometry(GEOJSON_MULTI_LINE_STRING)
+	geoJSon.AddLngLat(0, 0)
+	geoJSon.AddLngLat(1, 1)
+	geoJSon.NewLine()
+	geoJSon.AddLngLat(3, 0)
+	geoJSon.AddLngLat(4, 1)
+	geoJSon.MakeBoundingBox()
+
+	output, err := geoJSon.StringLastFeature()
+	fmt.Printf("error: %v\n", err)
+	fmt.Println(output)
+
+	// Output:
+	// error: <nil>
+	// {"type":"Feature","id":"1","properties":null,"geometry":{"type":"GeometryCollection","bbox":[-1,0,4,2],"geometries":[{"type":"Point","bbox":[-1,2,-1,2],"coordinates":[-1,2]},{"type":"MultiLineString","bbox":[0,0,4,1],"coordinates":[[[0,0],[1,1]],[[3,0],[4,1]]]}]}}
+}
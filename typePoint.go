@@ -16,6 +16,7 @@ import (
 	"math"
 	"os"
 	"strconv"
+	"time"
 )
 
 // point struct based on osm file
@@ -26,13 +27,28 @@ type PointStt struct {
 	// Este campo deve obrigatoriamente ser um array devido a indexação do MongoDB
 	Loc [2]float64 `bson:"loc"`
 	Rad [2]float64 `bson:"rad"`
+	// Altitude em metros, quando a fonte a tem (ex.: GeoJSON com três dimensões)
+	Alt float64 `bson:"alt,omitempty"`
+
+	// Versão dentro do Open Street Maps
+	Version int64 `bson:"version"`
+	// TimeStamp dentro do Open Street Maps
+	TimeStamp time.Time `bson:"timeStamp"`
+	// ChangeSet dentro do Open Street Maps
+	ChangeSet int64 `bson:"changeSet"`
 
 	Visible bool `bson:"visible"`
 
+	// User Id dentro do Open Street Maps
+	UId int64 `bson:"userId"`
+	// User Name dentro do Open Street Maps
+	User string `bson:"-"`
+
 	// Tags do Open Street Maps
 	// As Tags contêm _todo tipo de informação, desde como elas foram importadas, ao nome de um estabelecimento comercial,
 	// por exemplo.
-	Tag map[string]string `bson:"tag"`
+	Tag           map[string]string `bson:"tag"`
+	International map[string]string `bson:"international"`
 
 	// Dados do usuário
 	// Como o GO é fortemente tipado, eu obtive problemas em estender o struct de forma satisfatória e permitir ao usuário
@@ -60,6 +76,7 @@ func (el *PointStt) CopyFrom(pointABStt PointStt) {
 	el.Id = pointABStt.Id
 	el.Loc = pointABStt.Loc
 	el.Rad = pointABStt.Rad
+	el.Alt = pointABStt.Alt
 	el.Tag = pointABStt.Tag
 	el.Data = pointABStt.Data
 	el.Md5 = pointABStt.Md5
@@ -290,6 +307,17 @@ func (el PointStt) GetBoundingBox(distanceAStt DistanceStt) BoxStt {
 	return BoundingBox(el, distanceAStt)
 }
 
+// English: Box of the point alone, with both corners on the point, as used by RTreeStt.
+//
+// Português: Caixa só do ponto, com os dois cantos sobre o ponto, como usada pelo RTreeStt.
+func (el *PointStt) GetBox() BoxStt {
+	var box BoxStt
+	box.BottomLeft.Loc, box.BottomLeft.Rad = el.Loc, el.Rad
+	box.UpperRight = box.BottomLeft
+
+	return box
+}
+
 func (el PointStt) GetDestinationPoint(distanceAStt DistanceStt, angleAStt AngleStt) PointStt {
 	return DestinationPoint(el, distanceAStt, angleAStt)
 }
@@ -78,14 +78,19 @@ type PolygonStt struct {
 	// Português: Lista dos pontos formadores do polígono
 	PointsList []PointStt `bson:"pointList"`
 
+	// English: inner rings (holes) of the polygon, as the role=inner ways of a multipolygon relation
+	//
+	// Português: anéis internos (buracos) do polígono, como os ways com role=inner de uma relação multipolygon
+	Inner []PolygonStt `bson:"inner"`
+
 	// English: The amount of forming points of the polygon
 	//
 	// Português: Quantidade de pontos formadores do polígono
 	Length int `bson:"length"`
 
-	// English: The area of the polygon used for the calculation of the centroid. I do not recommend the use for calculation of the geographic area in this version.
+	// English: The area of the polygon used for the calculation of the centroid. For the geographic area use GeodesicAreaKarney().
 	//
-	// Português: Área do polígono usada para o calculo da centroide. Não recomendo o uso para calculo de área geográfica nessa versão.
+	// Português: Área do polígono usada para o calculo da centroide. Para a área geográfica use GeodesicAreaKarney().
 	Area float64 `bson:"area"`
 
 	// English: Centroid of polygon
@@ -309,7 +314,15 @@ func (el *PolygonStt) AddWayAsPolygon(way *WayStt) {
 
 // English: Adds all ways that are part of a polygon, then process and generate a single polygon
 //
+// The open ways are joined by Init() where their endpoints are the same node, or the same coordinates when IdNode is
+// not filled, and must make exactly one closed ring. Use AssembleMultipolygon() for relations with holes or more than
+// one outer ring.
+//
 // Português: Adiciona todos os ways que fazem parte de um polígono para depois processar e gerar um polígono único
+//
+// Os ways abertos são unidos por Init() onde as suas extremidades são o mesmo node, ou as mesmas coordenadas quando
+// IdNode não está preenchido, e devem formar exatamente um anel fechado. Use AssembleMultipolygon() para relações com
+// buracos ou mais de um anel externo.
 func (el *PolygonStt) AddWayAsPreProcessingPolygon(way *WayStt) {
 	if len(el.TagFromWay) == 0 {
 		el.TagFromWay = make(map[string]map[string]string)
@@ -466,106 +479,17 @@ func (el *PolygonStt) AddTag(keyAStr, valueAStr string) {
 //
 // Note que esta função deve ser chamada a cada alteração nos pontos do polígono.
 func (el *PolygonStt) Init() error {
-	var distanceList []float64
-	var distance float64
-	var distanceKey int
-	//var distanceAStartBStart float64 = math.MaxFloat64
-	//var distanceAStartBEnd float64   = math.MaxFloat64
-	//var distanceAEndBStart float64   = math.MaxFloat64
-	//var distanceAEndBEnd float64     = math.MaxFloat64
-	var k1, k2, lengthStart, lengthTmp int
-	//var inverter bool = false
-	var pass bool = false
-	//var addToEndOfTheSet bool = false
-	var pythagorasAStartBStart, pythagorasAStartBEnd, pythagorasAEndBStart, pythagorasAEndBEnd []float64
-	var xStart, xEnd, xTmpStart, xTmpEnd float64
-	var yStart, yEnd, yTmpStart, yTmpEnd float64
-
 	if len(el.tmp) > 0 {
+		ring, err := osmJoinWaysIntoRing(el.tmp)
+		if err != nil {
+			return err
+		}
 
-		for _, loc := range el.tmp[0].Loc {
+		for _, loc := range ring {
 			el.AddLngLatDegrees(loc[0], loc[1])
 		}
 
-		pythagorasAStartBStart = make([]float64, len(el.tmp))
-		pythagorasAStartBEnd = make([]float64, len(el.tmp))
-		pythagorasAEndBStart = make([]float64, len(el.tmp))
-		pythagorasAEndBEnd = make([]float64, len(el.tmp))
-
-		distanceList = make([]float64, len(el.tmp))
-
-		for k2 = 1; k2 != len(el.tmp); k2 += 1 {
-			distance = math.MaxFloat64
-
-			for k1 = 0; k1 != len(el.tmp); k1 += 1 {
-				distanceList[k1] = math.MaxFloat64
-			}
-
-			lengthStart = len(el.PointsList) - 1
-
-			xStart = el.PointsList[0].Loc[0]
-			yStart = el.PointsList[0].Loc[1]
-			xEnd = el.PointsList[lengthStart].Loc[0]
-			yEnd = el.PointsList[lengthStart].Loc[1]
-
-			for k1 = 1; k1 != len(el.tmp); k1 += 1 {
-
-				lengthTmp = len(el.tmp[k1].Loc) - 1
-
-				xTmpStart = el.tmp[k1].Loc[0][0]
-				yTmpStart = el.tmp[k1].Loc[0][1]
-				xTmpEnd = el.tmp[k1].Loc[lengthTmp][0]
-				yTmpEnd = el.tmp[k1].Loc[lengthTmp][1]
-
-				pythagorasAStartBStart[k1] = Pythagoras(xStart, yStart, xTmpStart, yTmpStart)
-				pythagorasAStartBEnd[k1] = Pythagoras(xStart, yStart, xTmpEnd, yTmpEnd)
-				pythagorasAEndBStart[k1] = Pythagoras(xEnd, yEnd, xTmpStart, yTmpStart)
-				pythagorasAEndBEnd[k1] = Pythagoras(xEnd, yEnd, xTmpEnd, yTmpEnd)
-
-				distanceList[k1] = math.Min(distanceList[k1], pythagorasAStartBStart[k1])
-				distanceList[k1] = math.Min(distanceList[k1], pythagorasAStartBEnd[k1])
-				distanceList[k1] = math.Min(distanceList[k1], pythagorasAEndBStart[k1])
-				distanceList[k1] = math.Min(distanceList[k1], pythagorasAEndBEnd[k1])
-
-				if distanceList[k1] < distance {
-					distance = distanceList[k1]
-					distanceKey = k1
-				}
-			}
-
-			if pythagorasAStartBStart[distanceKey] < pythagorasAStartBEnd[distanceKey] && pythagorasAStartBStart[distanceKey] < pythagorasAEndBStart[distanceKey] && pythagorasAStartBStart[distanceKey] < pythagorasAEndBEnd[distanceKey] {
-				pass = true
-				for _, loc := range el.tmp[distanceKey].Loc {
-					el.AddLngLatDegreesAtStart(loc[0], loc[1])
-				}
-			} else if pythagorasAStartBStart[distanceKey] < pythagorasAStartBEnd[distanceKey] && pythagorasAEndBStart[distanceKey] < pythagorasAStartBEnd[distanceKey] && pythagorasAEndBEnd[distanceKey] < pythagorasAStartBEnd[distanceKey] {
-				pass = true
-				for _, loc := range el.tmp[distanceKey].Loc {
-					el.AddLngLatDegrees(loc[0], loc[1])
-				}
-			} else if pythagorasAStartBStart[distanceKey] < pythagorasAEndBStart[distanceKey] && pythagorasAStartBEnd[distanceKey] < pythagorasAEndBStart[distanceKey] && pythagorasAEndBEnd[distanceKey] < pythagorasAEndBStart[distanceKey] {
-				pass = true
-				//for _, loc := range el.tmp[k1].Loc {
-				for i := len(el.tmp[distanceKey].Loc) - 1; i != -1; i -= 1 {
-					el.AddLngLatDegreesAtStart(el.tmp[distanceKey].Loc[i][0], el.tmp[distanceKey].Loc[i][1])
-				}
-			} else if pythagorasAStartBStart[distanceKey] < pythagorasAEndBEnd[distanceKey] && pythagorasAStartBEnd[distanceKey] < pythagorasAEndBEnd[distanceKey] && pythagorasAEndBStart[distanceKey] < pythagorasAEndBEnd[distanceKey] {
-				pass = true
-				for i := len(el.tmp[distanceKey].Loc) - 1; i != -1; i -= 1 {
-					el.AddLngLatDegrees(el.tmp[distanceKey].Loc[i][0], el.tmp[distanceKey].Loc[i][1])
-				}
-			} else {
-				pass = true
-				//for _, loc := range el.tmp[k1].Loc {
-				for i := len(el.tmp[distanceKey].Loc) - 1; i != -1; i -= 1 {
-					el.AddLngLatDegrees(el.tmp[distanceKey].Loc[i][0], el.tmp[distanceKey].Loc[i][1])
-				}
-			}
-
-			if pass == true {
-				log.Critical("")
-			}
-		}
+		el.tmp = nil
 	}
 
 	if len(el.PointsList) == 0 {
@@ -603,6 +527,13 @@ func (el *PolygonStt) Init() error {
 		lastCornerLUInt = i
 	}
 
+	for k := range el.Inner {
+		err := el.Inner[k].Init()
+		if err != nil {
+			return err
+		}
+	}
+
 	el.centroid()
 	el.area()
 
@@ -628,20 +559,28 @@ func (el *PolygonStt) Init() error {
 			pointA.SetLngLatRadians(el.PointsList[keyRefLInt64-1].Rad[0], el.PointsList[keyRefLInt64-1].Rad[1])
 			pointB.SetLngLatRadians(el.PointsList[keyRefLInt64].Rad[0], el.PointsList[keyRefLInt64].Rad[1])
 
-			angleList[keyRefLInt64-1] = DirectionBetweenTwoPoints(pointA, pointB)
-
-			distanceListLAStt[keyRefLInt64] = DistanceBetweenTwoPoints(pointA, pointB)
+			distanceListLAStt[keyRefLInt64], angle = distanceAndDirection(pointA, pointB)
+			angleList[keyRefLInt64-1] = angle
 			distanceLStt.AddMeters(distanceListLAStt[keyRefLInt64].GetMeters())
 
 			k = keyRefLInt64
 		}
-		angleList[k] = DirectionBetweenTwoPoints(pointA, pointB)
+		angleList[k] = angle
 	}
 
 	el.Distance = distanceListLAStt
 	el.DistanceTotal = distanceLStt
 	el.Angle = angleList
-	el.BBox = GetBox(&el.PointsList)
+	if len(el.Inner) == 0 {
+		el.BBox = GetBox(&el.PointsList)
+	} else {
+		var pointsList = make([]PointStt, 0, len(el.PointsList))
+		pointsList = append(pointsList, el.PointsList...)
+		for _, inner := range el.Inner {
+			pointsList = append(pointsList, inner.PointsList...)
+		}
+		el.BBox = GetBox(&pointsList)
+	}
 	//el.BBoxBSon = GetBSonBoxInDegrees(&el.PointsList)
 	//el.BBoxSearch = [2][2]float64{el.BBox.UpperRight.Loc, el.BBox.BottomLeft.Loc}
 
@@ -650,10 +589,14 @@ func (el *PolygonStt) Init() error {
 
 // English: Tests if the point is contained within the polygon.
 //
+// Points inside one of the Inner rings (holes) are not contained within the polygon.
+//
 // If the point is above the line of the edge, the same can give a response undetermined because of the lease of the decimals.
 //
 // Português: Testa se o ponto está contido dentro do polígono.
 //
+// Pontos dentro de um dos anéis Inner (buracos) não estão contidos no polígono.
+//
 // Se o ponto estiver em cima da linha da borda, o mesmo pode dá uma resposta indeterminada devido ao arrendamento das casas decimais
 func (el *PolygonStt) PointInPolygon(pointAStt PointStt) bool {
 	if el.Initialize == false {
@@ -679,57 +622,109 @@ func (el *PolygonStt) PointInPolygon(pointAStt PointStt) bool {
 		lastCornerLUInt = i
 	}
 
-	return oddNodesLBoo
+	if oddNodesLBoo == false {
+		return false
+	}
+
+	for k := range el.Inner {
+		if el.Inner[k].PointInPolygon(pointAStt) {
+			return false
+		}
+	}
+
+	return true
 }
 
+// centroid of the polygon, with the holes removed. The centroid of each ring is weighted by its area.
 func (el *PolygonStt) centroid() {
-	el.Centroid.Loc = [2]float64{0.0, 0.0}
-	el.Centroid.Rad = [2]float64{0.0, 0.0}
+	var areaLFlt float64
+	var centroidRad [2]float64
+
+	centroidRad, areaLFlt = polygonRingCentroid(el.PointsList)
 
+	if len(el.Inner) != 0 {
+		areaLFlt = math.Abs(areaLFlt)
+		centroidRad[0] *= areaLFlt
+		centroidRad[1] *= areaLFlt
+
+		for k := range el.Inner {
+			innerRad, innerArea := polygonRingCentroid(el.Inner[k].PointsList)
+			innerArea = math.Abs(innerArea)
+
+			areaLFlt -= innerArea
+			centroidRad[0] -= innerRad[0] * innerArea
+			centroidRad[1] -= innerRad[1] * innerArea
+		}
+
+		centroidRad[0] /= areaLFlt
+		centroidRad[1] /= areaLFlt
+	}
+
+	el.Centroid.Rad = centroidRad
+	el.Centroid.Loc[0] = utilMath.RadiansToDegrees(el.Centroid.Rad[0])
+	el.Centroid.Loc[1] = utilMath.RadiansToDegrees(el.Centroid.Rad[1])
+}
+
+// polygonRingCentroid returns the centroid, in radians, and the signed area of one ring.
+func polygonRingCentroid(pointsList []PointStt) ([2]float64, float64) {
+	var centroidRad = [2]float64{0.0, 0.0}
 	var areaLFlt float64 = 0.0
 	var a float64 = 0.0
 
 	var i = 0
-	for ; i != len(el.PointsList)-1; i += 1 {
-		a = el.PointsList[i].Rad[0]*el.PointsList[i+1].Rad[1] - el.PointsList[i+1].Rad[0]*el.PointsList[i].Rad[1]
+	for ; i != len(pointsList)-1; i += 1 {
+		a = pointsList[i].Rad[0]*pointsList[i+1].Rad[1] - pointsList[i+1].Rad[0]*pointsList[i].Rad[1]
 		areaLFlt += a
-		el.Centroid.Rad[0] += (el.PointsList[i].Rad[0] + el.PointsList[i+1].Rad[0]) * a
-		el.Centroid.Rad[1] += (el.PointsList[i].Rad[1] + el.PointsList[i+1].Rad[1]) * a
+		centroidRad[0] += (pointsList[i].Rad[0] + pointsList[i+1].Rad[0]) * a
+		centroidRad[1] += (pointsList[i].Rad[1] + pointsList[i+1].Rad[1]) * a
 	}
 
-	a = el.PointsList[i].Rad[0]*el.PointsList[0].Rad[1] - el.PointsList[0].Rad[0]*el.PointsList[i].Rad[1]
+	a = pointsList[i].Rad[0]*pointsList[0].Rad[1] - pointsList[0].Rad[0]*pointsList[i].Rad[1]
 	areaLFlt += a
-	el.Centroid.Rad[0] += (el.PointsList[i].Rad[0] + el.PointsList[0].Rad[0]) * a
-	el.Centroid.Rad[1] += (el.PointsList[i].Rad[1] + el.PointsList[0].Rad[1]) * a
+	centroidRad[0] += (pointsList[i].Rad[0] + pointsList[0].Rad[0]) * a
+	centroidRad[1] += (pointsList[i].Rad[1] + pointsList[0].Rad[1]) * a
 
 	areaLFlt *= 0.5
-	el.Centroid.Rad[0] /= 6.0 * areaLFlt
-	el.Centroid.Rad[1] /= 6.0 * areaLFlt
+	centroidRad[0] /= 6.0 * areaLFlt
+	centroidRad[1] /= 6.0 * areaLFlt
 
-	el.Centroid.Loc[0] = utilMath.RadiansToDegrees(el.Centroid.Rad[0])
-	el.Centroid.Loc[1] = utilMath.RadiansToDegrees(el.Centroid.Rad[1])
+	return centroidRad, areaLFlt
 }
 
+// area of the polygon, with the area of the holes subtracted. The sign follows the orientation of the outer ring.
 func (el *PolygonStt) area() {
-	el.Area = 0.0
+	el.Area = polygonRingArea(el.PointsList)
+
+	for k := range el.Inner {
+		if el.Area < 0 {
+			el.Area += math.Abs(el.Inner[k].Area)
+		} else {
+			el.Area -= math.Abs(el.Inner[k].Area)
+		}
+	}
+}
+
+func polygonRingArea(pointsList []PointStt) float64 {
+	var area = 0.0
 
 	var polygonLStt PolygonStt
 
-	polygonLStt.PointsList = make([]PointStt, len(el.PointsList))
+	polygonLStt.PointsList = make([]PointStt, len(pointsList))
 
-	for i := 0; i != len(el.PointsList); i += 1 {
-		earthRadiusLStt := EarthRadius(el.PointsList[i])
+	for i := 0; i != len(pointsList); i += 1 {
+		earthRadiusLStt := EarthRadius(pointsList[i])
 		earthRadiusLFlt := earthRadiusLStt.GetKilometers()
-		polygonLStt.PointsList[i].SetLatLngRadiansWithoutCheckingFunction(el.PointsList[i].Rad[1]*earthRadiusLFlt, el.PointsList[i].Rad[0]*earthRadiusLFlt)
+		polygonLStt.PointsList[i].SetLatLngRadiansWithoutCheckingFunction(pointsList[i].Rad[1]*earthRadiusLFlt, pointsList[i].Rad[0]*earthRadiusLFlt)
 	}
 
 	var i = 0
 	for ; i != len(polygonLStt.PointsList)-1; i += 1 {
-		el.Area += polygonLStt.PointsList[i].Rad[0]*polygonLStt.PointsList[i+1].Rad[1] - polygonLStt.PointsList[i+1].Rad[0]*polygonLStt.PointsList[i].Rad[1]
+		area += polygonLStt.PointsList[i].Rad[0]*polygonLStt.PointsList[i+1].Rad[1] - polygonLStt.PointsList[i+1].Rad[0]*polygonLStt.PointsList[i].Rad[1]
 	}
 
-	el.Area += polygonLStt.PointsList[i].Rad[0]*polygonLStt.PointsList[0].Rad[1] - polygonLStt.PointsList[0].Rad[0]*polygonLStt.PointsList[i].Rad[1]
-	el.Area *= 0.5
+	area += polygonLStt.PointsList[i].Rad[0]*polygonLStt.PointsList[0].Rad[1] - polygonLStt.PointsList[0].Rad[0]*polygonLStt.PointsList[i].Rad[1]
+
+	return area * 0.5
 }
 
 // English: Determines the box in which the polygon is contained to be used with the function $box of Mongo DB.
@@ -0,0 +1,36 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+func ExamplePolygonStt_PointInPolygon() {
+	var polygon = PolygonStt{}
+	for _, loc := range [][2]float64{{0, 0}, {4, 0}, {4, 4}, {0, 4}} {
+		polygon.AddLngLatDegrees(loc[0], loc[1])
+	}
+
+	var hole = PolygonStt{}
+	for _, loc := range [][2]float64{{1, 1}, {2, 1}, {2, 2}, {1, 2}} {
+		hole.AddLngLatDegrees(loc[0], loc[1])
+	}
+	polygon.Inner = append(polygon.Inner, hole)
+
+	err := polygon.Init()
+	fmt.Printf("error: %v\n", err)
+
+	for _, loc := range [][2]float64{{3, 3}, {1.5, 1.5}, {5, 5}} {
+		var point = PointStt{}
+		point.SetLngLatDegrees(loc[0], loc[1])
+		fmt.Printf("%v: %v\n", loc, polygon.PointInPolygon(point))
+	}
+
+	fmt.Printf("centroid: %.4f %.4f\n", polygon.Centroid.Loc[0], polygon.Centroid.Loc[1])
+
+	// Output:
+	// error: <nil>
+	// [3 3]: true
+	// [1.5 1.5]: false
+	// [5 5]: false
+	// centroid: 2.0333 2.0333
+}
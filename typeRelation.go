@@ -260,3 +260,19 @@ func (el *RelationStt) FromFilePath(filePath string) error {
 
 	return err
 }
+
+// en: Adds a member to the relation and keeps IdNode, IdWay and IdRelation in sync with the members.
+//
+// pt: Adiciona um membro à relação e mantém IdNode, IdWay e IdRelation sincronizados com os membros.
+func (el *RelationStt) AddMember(member MembersStt) {
+	el.Members = append(el.Members, member)
+
+	switch member.Type {
+	case "node":
+		el.IdNode = append(el.IdNode, member.Ref)
+	case "way":
+		el.IdWay = append(el.IdWay, member.Ref)
+	case "relation":
+		el.IdRelation = append(el.IdRelation, member.Ref)
+	}
+}
@@ -0,0 +1,261 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+	"math"
+)
+
+type Hemisphere int
+
+const (
+	HEMISPHERE_NORTH Hemisphere = iota
+	HEMISPHERE_SOUTH
+)
+
+var hemispheres = [...]string{
+	"N",
+	"S",
+}
+
+func (e Hemisphere) String() string {
+	return hemispheres[e]
+}
+
+const (
+	// scale factor on the central meridian
+	utmScale = 0.9996
+
+	utmFalseEasting        = 500000.0
+	utmFalseNorthingSouth  = 10000000.0
+	utmMinLatitude         = -80.0
+	utmMaxLatitude         = 84.0
+	utmKruegerSeriesLength = 6
+)
+
+// English: Universal Transverse Mercator coordinate, in meters.
+//
+// Português: Coordenada Universal Transversa de Mercator, em metros.
+type UTMStt struct {
+	// English: zone between 1 and 60
+	//
+	// Português: zona entre 1 e 60
+	Zone int
+
+	Hemisphere Hemisphere
+
+	Easting  float64
+	Northing float64
+}
+
+// utmZone is the zone of the point, with the exceptions of south-west Norway and Svalbard.
+func utmZone(longitude, latitude float64) int {
+	var zone = int(math.Floor((longitude+180)/6)) + 1
+	if zone > 60 {
+		zone = 60
+	}
+
+	// south-west Norway, zone 32V is widened to the west
+	if latitude >= 56 && latitude < 64 && longitude >= 3 && longitude < 12 {
+		return 32
+	}
+
+	// Svalbard, zones 32X, 34X and 36X are not used
+	if latitude >= 72 {
+		switch {
+		case longitude >= 0 && longitude < 9:
+			return 31
+		case longitude >= 9 && longitude < 21:
+			return 33
+		case longitude >= 21 && longitude < 33:
+			return 35
+		case longitude >= 33 && longitude < 42:
+			return 37
+		}
+	}
+
+	return zone
+}
+
+// utmCentralMeridian is the longitude of the central meridian of the zone, in degrees.
+func utmCentralMeridian(zone int) float64 {
+	return float64(zone-1)*6 - 180 + 3
+}
+
+// utmKrueger holds the constants of the Krüger series, to the sixth order of the third flattening, of the ellipsoid.
+type utmKrueger struct {
+	e     float64
+	a     float64
+	alpha [utmKruegerSeriesLength]float64
+	beta  [utmKruegerSeriesLength]float64
+}
+
+func newUTMKrueger(ellipsoid EllipsoidStt) utmKrueger {
+	var f = ellipsoid.Flattening
+	var n = f / (2 - f)
+	var n2 = n * n
+	var n3 = n2 * n
+	var n4 = n3 * n
+	var n5 = n4 * n
+	var n6 = n5 * n
+
+	return utmKrueger{
+		e: math.Sqrt(ellipsoid.EccentricitySquared()),
+		// radius of the rectifying sphere
+		a: ellipsoid.Major / (1 + n) * (1 + n2/4 + n4/64 + n6/256),
+		alpha: [utmKruegerSeriesLength]float64{
+			n/2 - 2*n2/3 + 5*n3/16 + 41*n4/180 - 127*n5/288 + 7891*n6/37800,
+			13*n2/48 - 3*n3/5 + 557*n4/1440 + 281*n5/630 - 1983433*n6/1935360,
+			61*n3/240 - 103*n4/140 + 15061*n5/26880 + 167603*n6/181440,
+			49561*n4/161280 - 179*n5/168 + 6601661*n6/7257600,
+			34729*n5/80640 - 3418889*n6/1995840,
+			212378941 * n6 / 319334400,
+		},
+		beta: [utmKruegerSeriesLength]float64{
+			n/2 - 2*n2/3 + 37*n3/96 - n4/360 - 81*n5/512 + 96199*n6/604800,
+			n2/48 + n3/15 - 437*n4/1440 + 46*n5/105 - 1118711*n6/3870720,
+			17*n3/480 - 37*n4/840 - 209*n5/4480 + 5569*n6/90720,
+			4397*n4/161280 - 11*n5/504 - 830251*n6/7257600,
+			4583*n5/161280 - 108847*n6/3991680,
+			20648693 * n6 / 638668800,
+		},
+	}
+}
+
+// conformalTangent is the tangent of the conformal latitude, from the tangent of the geodetic latitude.
+func (el utmKrueger) conformalTangent(tau float64) float64 {
+	var sigma = math.Sinh(el.e * math.Atanh(el.e*tau/math.Sqrt(1+tau*tau)))
+
+	return tau*math.Sqrt(1+sigma*sigma) - sigma*math.Sqrt(1+tau*tau)
+}
+
+// forward projects latitude and longitude relative to the central meridian, in degrees, into easting and northing
+// without the false origin.
+func (el utmKrueger) forward(latitude, longitude float64) (float64, float64) {
+	var tauP = el.conformalTangent(math.Tan(DegreesToRadians(latitude)))
+	var sinLambda, cosLambda = math.Sincos(DegreesToRadians(longitude))
+
+	var xiP = math.Atan2(tauP, cosLambda)
+	var etaP = math.Asinh(sinLambda / math.Sqrt(tauP*tauP+cosLambda*cosLambda))
+
+	var xi = xiP
+	var eta = etaP
+	for j := 1; j <= utmKruegerSeriesLength; j += 1 {
+		var k = 2 * float64(j)
+		xi += el.alpha[j-1] * math.Sin(k*xiP) * math.Cosh(k*etaP)
+		eta += el.alpha[j-1] * math.Cos(k*xiP) * math.Sinh(k*etaP)
+	}
+
+	return utmScale * el.a * eta, utmScale * el.a * xi
+}
+
+// reverse is the inverse of forward, with a few Newton iterations on the tangent of the latitude.
+func (el utmKrueger) reverse(x, y float64) (float64, float64) {
+	var eta = x / (utmScale * el.a)
+	var xi = y / (utmScale * el.a)
+
+	var xiP = xi
+	var etaP = eta
+	for j := 1; j <= utmKruegerSeriesLength; j += 1 {
+		var k = 2 * float64(j)
+		xiP -= el.beta[j-1] * math.Sin(k*xi) * math.Cosh(k*eta)
+		etaP -= el.beta[j-1] * math.Cos(k*xi) * math.Sinh(k*eta)
+	}
+
+	var sinhEtaP = math.Sinh(etaP)
+	var sinXiP, cosXiP = math.Sincos(xiP)
+
+	var tauP = sinXiP / math.Sqrt(sinhEtaP*sinhEtaP+cosXiP*cosXiP)
+	var e2 = el.e * el.e
+
+	var tau = tauP
+	for i := 0; i != 10; i += 1 {
+		var tauI = el.conformalTangent(tau)
+		var delta = (tauP - tauI) / math.Sqrt(1+tauI*tauI) * (1 + (1-e2)*tau*tau) / ((1 - e2) * math.Sqrt(1+tau*tau))
+		tau += delta
+
+		if math.Abs(delta) < 1e-12 {
+			break
+		}
+	}
+
+	return RadiansToDegrees(math.Atan(tau)), RadiansToDegrees(math.Atan2(sinhEtaP, cosXiP))
+}
+
+// English: Converts the point into UTM, on the ellipsoid of the datum selected by SetDatum(), in the zone of the point
+// with the exceptions of Norway and Svalbard. The latitude must be between -80° and 84°.
+//
+// Português: Converte o ponto em UTM, no elipsoide do datum selecionado por SetDatum(), na zona do ponto com as
+// exceções da Noruega e de Svalbard. A latitude deve estar entre -80° e 84°.
+func (el *PointStt) ToUTM() (UTMStt, error) {
+	return GetDatum().Ellipsoid.ToUTM(*el)
+}
+
+// English: Same as PointStt.ToUTM(), on the ellipsoid.
+//
+// Português: O mesmo que PointStt.ToUTM(), no elipsoide.
+func (el EllipsoidStt) ToUTM(pointAStt PointStt) (UTMStt, error) {
+	var longitude = pointAStt.Loc[0]
+	var latitude = pointAStt.Loc[1]
+
+	if latitude < utmMinLatitude || latitude > utmMaxLatitude {
+		return UTMStt{}, fmt.Errorf("utm: latitude %v is outside the limits of %v and %v", latitude, utmMinLatitude, utmMaxLatitude)
+	}
+
+	return pointAStt.toUTMZone(el, utmZone(geodesicAngNormalize(longitude), latitude)), nil
+}
+
+// toUTMZone converts the point into UTM on the given zone of the ellipsoid, even when the point is outside it.
+func (el *PointStt) toUTMZone(ellipsoid EllipsoidStt, zone int) UTMStt {
+	var krueger = newUTMKrueger(ellipsoid)
+	var returnLStt = UTMStt{Zone: zone, Hemisphere: HEMISPHERE_NORTH}
+
+	x, y := krueger.forward(el.Loc[1], geodesicAngNormalize(el.Loc[0]-utmCentralMeridian(zone)))
+
+	returnLStt.Easting = x + utmFalseEasting
+	returnLStt.Northing = y
+	if el.Loc[1] < 0 {
+		returnLStt.Hemisphere = HEMISPHERE_SOUTH
+		returnLStt.Northing += utmFalseNorthingSouth
+	}
+
+	return returnLStt
+}
+
+// English: Sets the point from a UTM coordinate, on the ellipsoid of the datum selected by SetDatum().
+//
+// Português: Define o ponto a partir de uma coordenada UTM, no elipsoide do datum selecionado por SetDatum().
+func (el *PointStt) FromUTM(zone int, hemisphere Hemisphere, easting, northing float64) error {
+	point, err := GetDatum().Ellipsoid.FromUTM(zone, hemisphere, easting, northing)
+	if err != nil {
+		return err
+	}
+
+	return el.SetLngLatDegrees(point.Loc[0], point.Loc[1])
+}
+
+// English: Same as PointStt.FromUTM(), on the ellipsoid, returning the point.
+//
+// Português: O mesmo que PointStt.FromUTM(), no elipsoide, devolvendo o ponto.
+func (el EllipsoidStt) FromUTM(zone int, hemisphere Hemisphere, easting, northing float64) (PointStt, error) {
+	var returnLStt PointStt
+
+	if zone < 1 || zone > 60 {
+		return returnLStt, fmt.Errorf("utm: zone %v is outside the limits of 1 and 60", zone)
+	}
+
+	if hemisphere != HEMISPHERE_NORTH && hemisphere != HEMISPHERE_SOUTH {
+		return returnLStt, fmt.Errorf("utm: invalid hemisphere %v", int(hemisphere))
+	}
+
+	var y = northing
+	if hemisphere == HEMISPHERE_SOUTH {
+		y -= utmFalseNorthingSouth
+	}
+
+	var krueger = newUTMKrueger(el)
+	latitude, longitude := krueger.reverse(easting-utmFalseEasting, y)
+
+	err := returnLStt.SetLngLatDegrees(geodesicAngNormalize(longitude+utmCentralMeridian(zone)), latitude)
+
+	return returnLStt, err
+}
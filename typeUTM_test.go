@@ -0,0 +1,94 @@
+package iotmaker_geo_osm
+
+import (
+	"fmt"
+)
+
+func ExamplePointStt_ToUTM() {
+	// Eiffel Tower
+	var point PointStt
+	point.SetLngLatDegrees(2.2945, 48.8583)
+
+	utm, _ := point.ToUTM()
+	fmt.Printf("utm: %v%v %.3f %.3f\n", utm.Zone, utm.Hemisphere, utm.Easting, utm.Northing)
+
+	for _, precision := range []int{0, 2, 5} {
+		mgrs, _ := point.ToMGRS(precision)
+		fmt.Printf("mgrs: %v\n", mgrs)
+	}
+
+	// the exceptions of Norway and Svalbard
+	var bergen, longyearbyen PointStt
+	bergen.SetLngLatDegrees(5.3221, 60.3913)
+	longyearbyen.SetLngLatDegrees(15.6356, 78.2232)
+
+	for _, point := range []PointStt{bergen, longyearbyen} {
+		mgrs, _ := point.ToMGRS(5)
+		fmt.Printf("mgrs: %v\n", mgrs)
+	}
+
+	var back PointStt
+	back.FromMGRS("31U DQ 48251 11943")
+	fmt.Printf("back: %.5f %.5f\n", back.Loc[0], back.Loc[1])
+
+	// Output:
+	// utm: 31N 448251.898 5411943.794
+	// mgrs: 31UDQ
+	// mgrs: 31UDQ4811
+	// mgrs: 31UDQ4825111943
+	// mgrs: 32VKN9735300648
+	// mgrs: 33XWG1448183357
+	// back: 2.29449 48.85829
+}
+
+func ExamplePointStt_FromUTM() {
+	// round trip over the whole area of UTM
+	var maxUTMError, maxMGRSError float64
+	for latitude := -80.0; latitude <= 84; latitude += 0.75 {
+		for longitude := -180.0; longitude < 180; longitude += 1.3 {
+			var point, back PointStt
+			point.SetLngLatDegrees(longitude, latitude)
+
+			utm, _ := point.ToUTM()
+			back.FromUTM(utm.Zone, utm.Hemisphere, utm.Easting, utm.Northing)
+			distance, _, _ := GeodesicInverseKarney(point, back)
+			if distance.GetMeters() > maxUTMError {
+				maxUTMError = distance.GetMeters()
+			}
+
+			mgrs, _ := point.ToMGRS(5)
+			back.FromMGRS(mgrs)
+			distance, _, _ = GeodesicInverseKarney(point, back)
+			if distance.GetMeters() > maxMGRSError {
+				maxMGRSError = distance.GetMeters()
+			}
+		}
+	}
+
+	fmt.Printf("utm error below 1 μm: %v\n", maxUTMError < 1e-6)
+	fmt.Printf("mgrs error below the diagonal of 1 m: %v\n", maxMGRSError < 1.4143)
+
+	// Output:
+	// utm error below 1 μm: true
+	// mgrs error below the diagonal of 1 m: true
+}
+
+func ExampleEllipsoidStt_ToUTM() {
+	// the same point on the ellipsoids of two datums, without changing the datum selected
+	var point PointStt
+	point.SetLngLatDegrees(-47.8825, -15.7942)
+
+	for _, datum := range []DatumStt{DATUM_WGS84, DATUM_SAD69} {
+		utm, _ := datum.Ellipsoid.ToUTM(point)
+		back, _ := datum.Ellipsoid.FromUTM(utm.Zone, utm.Hemisphere, utm.Easting, utm.Northing)
+
+		fmt.Printf("%v: %v%v %.3f %.3f, back %.6f\n", datum.Name, utm.Zone, utm.Hemisphere, utm.Easting, utm.Northing, back.Loc)
+	}
+
+	fmt.Printf("selected: %v\n", GetDatum().Name)
+
+	// Output:
+	// WGS84: 23S 191171.363 8251713.127, back [-47.882500 -15.794200]
+	// SAD69: 23S 191170.248 8251707.096, back [-47.882500 -15.794200]
+	// selected: WGS84
+}
@@ -14,6 +14,7 @@ import (
 	"math"
 	"os"
 	"strconv"
+	"time"
 )
 
 type WayStt struct {
@@ -29,6 +30,26 @@ type WayStt struct {
 	DistanceTotal DistanceStt       `bson:"distanceTotal"`
 	Angle         []AngleStt        `bson:"angle"`
 
+	// en: id of the nodes that form the way, in the same order as Loc and Rad
+	// pt: id dos nodes que formam o way, na mesma ordem de Loc e Rad
+	IdNode []int64 `bson:"idNode"`
+
+	// en: altitude of the points, in meters, in the same order as Loc, when the source has it (e.g. GeoJSON with three
+	// dimensions)
+	// pt: altitude dos pontos, em metros, na mesma ordem de Loc, quando a fonte a tem (ex.: GeoJSON com três dimensões)
+	Alt []float64 `bson:"alt,omitempty"`
+
+	// Versão dentro do Open Street Maps
+	Version int64 `bson:"version"`
+	// TimeStamp dentro do Open Street Maps
+	TimeStamp time.Time `bson:"timeStamp"`
+	// ChangeSet dentro do Open Street Maps
+	ChangeSet int64 `bson:"changeSet"`
+	// User Id dentro do Open Street Maps
+	UId int64 `bson:"userId"`
+	// User Name dentro do Open Street Maps
+	User string `bson:"-"`
+
 	Data map[string]string `bson:"data"`
 	// en: boundary box in degrees
 	// pt: caixa de perímetro em graus decimais
@@ -149,9 +170,7 @@ func (el *WayStt) Init() error {
 				return err
 			}
 
-			angleList[keyRefLInt64-1] = DirectionBetweenTwoPoints(pointA, pointB)
-
-			distanceList[keyRefLInt64] = DistanceBetweenTwoPoints(pointA, pointB)
+			distanceList[keyRefLInt64], angleList[keyRefLInt64-1] = distanceAndDirection(pointA, pointB)
 			distance.AddMeters(distanceList[keyRefLInt64].GetMeters())
 		}
 
@@ -619,3 +638,10 @@ func (el *WayStt) FromFilePath(filePath string) error {
 	err = bson.Unmarshal(byteBSon, el)
 	return err
 }
+
+// English: Box that contains all the points of the way, in decimal degrees.
+//
+// Português: Caixa que contém todos os pontos do way, em graus decimais.
+func (el *WayStt) GetBox() BoxStt {
+	return GetBoxFlt(&el.Loc)
+}